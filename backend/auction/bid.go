@@ -0,0 +1,433 @@
+package auction
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/bider"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// PlaceBid - places a bid on an auction, the auction row is locked for the whole check and update
+// so concurrent bids on the same auction are applied one after the other
+func (s *ServiceAuction) PlaceBid(input model.BidInput) (model.Auction, error) {
+	if input.AuctionUuid == "" || input.BidderUuid == "" || input.Amount <= 0 {
+		return model.Auction{}, fmt.Errorf("%w: auctionUuid, bidderUuid and a positive amount are required", model.ErrInvalidInput)
+	}
+
+	result, err := s.place(input.AuctionUuid, input.BidderUuid, input.ActorUuid, input.IP, func(auction *model.Auction, proxies []bidermodel.ProxyBid, now time.Time) (model.Placement, error) {
+		if err := checkCurrency(*auction, input.Currency); err != nil {
+			return model.Placement{}, err
+		}
+
+		if auction.Type == model.Dutch {
+			return model.Placement{}, fmt.Errorf("%w: dutch auctions are taken by accepting the current price", model.ErrInvalidInput)
+		}
+
+		if auction.Type.Sealed() {
+			return applySealedBid(auction, input, now)
+		}
+
+		increments, err := s.increments(*auction)
+		if err != nil {
+			return model.Placement{}, err
+		}
+
+		if err = applyBid(auction, input, increments.At, now); err != nil {
+			return model.Placement{}, err
+		}
+
+		bids := []model.Bid{{BidderUuid: input.BidderUuid, Amount: input.Amount}}
+
+		bids = append(bids, resolveProxies(auction, proxies, increments.At)...)
+
+		return model.Placement{
+			Bids: append(bids, meetReserve(auction, proxies)...),
+		}, nil
+	})
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.PlaceBid failed placing bid", zap.Any("input", input), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	return result, nil
+}
+
+// PlaceProxyBid - stores the bidder's secret maximum and lets the system bid on the bidder's behalf in minimum increments
+func (s *ServiceAuction) PlaceProxyBid(input model.ProxyBidInput) (model.Auction, error) {
+	if input.AuctionUuid == "" || input.BidderUuid == "" || input.MaxAmount <= 0 {
+		return model.Auction{}, fmt.Errorf("%w: auctionUuid, bidderUuid and a positive maxAmount are required", model.ErrInvalidInput)
+	}
+
+	result, err := s.place(input.AuctionUuid, input.BidderUuid, input.ActorUuid, input.IP, func(auction *model.Auction, proxies []bidermodel.ProxyBid, now time.Time) (model.Placement, error) {
+		if err := checkCurrency(*auction, input.Currency); err != nil {
+			return model.Placement{}, err
+		}
+
+		if auction.Type != model.English {
+			return model.Placement{}, fmt.Errorf("%w: proxy bids are accepted on english auctions only", model.ErrInvalidInput)
+		}
+
+		increments, err := s.increments(*auction)
+		if err != nil {
+			return model.Placement{}, err
+		}
+
+		return applyProxyBid(auction, input, proxies, increments.At, now)
+	})
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.PlaceProxyBid failed placing proxy bid", zap.String("auction", input.AuctionUuid), zap.String("bidder", input.BidderUuid), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	return result, nil
+}
+
+// BuyNow - ends the auction as sold to the bidder at the buy it now price, while the offer is still available
+func (s *ServiceAuction) BuyNow(input model.AcceptInput) (model.Auction, error) {
+	if input.AuctionUuid == "" || input.BidderUuid == "" {
+		return model.Auction{}, fmt.Errorf("%w: auctionUuid and bidderUuid are required", model.ErrInvalidInput)
+	}
+
+	result, err := s.place(input.AuctionUuid, input.BidderUuid, input.ActorUuid, input.IP, func(auction *model.Auction, proxies []bidermodel.ProxyBid, now time.Time) (model.Placement, error) {
+		return applyBuyNow(auction, input.BidderUuid, now)
+	})
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.BuyNow failed buying auction", zap.Any("input", input), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	s.publishClosed(result)
+	present(&result, time.Now())
+
+	return result, nil
+}
+
+// ListBids - a page of the bid history of an auction including the bids made by proxies and the retracted ones,
+// bidders are masked and the bids of a sealed auction stay hidden until it is closed
+func (s *ServiceAuction) ListBids(input model.BidListInput) (model.BidHistory, error) {
+	auction, err := s.repo.Single(input.AuctionUuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.ListBids failed getting auction", zap.String("auction", input.AuctionUuid), zap.Error(err))
+		return model.BidHistory{}, err
+	}
+
+	if auction.Type.Sealed() && auction.Status == model.InProgress {
+		return model.BidHistory{Bids: []model.Bid{}}, nil
+	}
+
+	result, err := s.repo.ListBids(input)
+	if err != nil {
+		s.logger.Error("ServiceAuction.ListBids failed listing bids", zap.String("auction", input.AuctionUuid), zap.Error(err))
+		return model.BidHistory{}, err
+	}
+
+	for i := range result.Bids {
+		result.Bids[i].Bidder = bidermodel.MaskIdentity(result.Bids[i].BidderUuid)
+	}
+	result.Currency = auction.Currency()
+
+	return result, nil
+}
+
+// place - runs apply on the locked auction, extends the auction when the bids landed in the soft close window
+// and once the transaction is committed publishes the new high bid, or the extension when the end moved.
+// the bidder must bid for the actor and never for the seller of the auction.
+// the bidder's commitment is checked against the bidder's credit before anything is saved, and the bids of a bidder
+// flagged for fraud are refused when the service blocks them. sealed auctions publish nothing so their bids stay hidden
+func (s *ServiceAuction) place(auctionUuid string, bidderUuid string, actorUuid string, ip string, apply func(auction *model.Auction, proxies []bidermodel.ProxyBid, now time.Time) (model.Placement, error)) (model.Auction, error) {
+	var placed, extended bool
+	var previousWinner string
+	now := time.Now()
+
+	bidder, err := s.actingBidder(bidderUuid, actorUuid)
+	if err != nil {
+		return model.Auction{}, err
+	}
+
+	if err = s.checkFlagged(bidderUuid); err != nil {
+		return model.Auction{}, err
+	}
+
+	result, err := s.repo.PlaceBid(auctionUuid, bidderUuid, func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error) {
+		previousWinner = auction.WinnerUuid
+
+		if bidder.UserUuid == auction.UserUuid {
+			return model.Placement{}, fmt.Errorf("%w: sellers can not bid on their own auction", model.ErrInvalidInput)
+		}
+
+		placement, err := apply(auction, proxies, now)
+		if err != nil {
+			return model.Placement{}, err
+		}
+		placement.Commitment = commitment(*auction, placement, bidderUuid)
+		for i := range placement.Bids {
+			if placement.Bids[i].BidderUuid == bidderUuid {
+				placement.Bids[i].IP = ip
+			}
+		}
+
+		placed = len(placement.Bids) > 0
+		if placed && auction.Type == model.English {
+			extended = extendSoftClose(auction, s.config.SoftClose, now)
+		}
+
+		return placement, nil
+	})
+
+	if err != nil {
+		return model.Auction{}, err
+	}
+
+	if !placed || result.Status != model.InProgress || result.Type.Sealed() {
+		return result, nil
+	}
+
+	eventType := model.EventBid
+	if extended {
+		eventType = model.EventExtended
+	}
+
+	s.publish(model.Event{
+		Type:               eventType,
+		AuctionUuid:        result.Uuid,
+		Status:             result.Status,
+		WinnerUuid:         result.WinnerUuid,
+		WinningPrice:       result.WinningPrice.Amount,
+		Currency:           result.Currency(),
+		ExpiredAt:          result.ExpiredAt,
+		OccurredAt:         now,
+		PreviousWinnerUuid: previousWinner,
+		BidderUuid:         bidderUuid,
+	})
+
+	return result, nil
+}
+
+// actingBidder - the bidder, which must bid for the user of the request
+func (s *ServiceAuction) actingBidder(bidderUuid string, actorUuid string) (bidermodel.Bidder, error) {
+	if actorUuid == "" {
+		return bidermodel.Bidder{}, model.ErrForbidden
+	}
+
+	bidder, err := s.bidders.FindOne(bidderUuid)
+	if err != nil && !errors.Is(err, bidermodel.ErrNotFound) {
+		return bidermodel.Bidder{}, err
+	}
+
+	if err != nil || bidder.UserUuid != actorUuid {
+		return bidermodel.Bidder{}, model.ErrForbidden
+	}
+
+	return bidder, nil
+}
+
+// commitment - the most the bidder stands to pay on the auction after the placement: the highest of the new bids
+// and of the new proxy maximum, nil when the placement commits the bidder to nothing
+func commitment(auction model.Auction, placement model.Placement, bidderUuid string) *bidermodel.Commitment {
+	var amount int64
+
+	for _, bid := range placement.Bids {
+		if bid.BidderUuid == bidderUuid {
+			amount = max(amount, bid.Amount)
+		}
+	}
+
+	if placement.Proxy != nil && placement.Proxy.BidderUuid == bidderUuid {
+		amount = max(amount, placement.Proxy.MaxAmount)
+	}
+
+	if amount == 0 {
+		return nil
+	}
+
+	return &bidermodel.Commitment{BidderUuid: bidderUuid, Amount: auction.Money(amount)}
+}
+
+// checkBiddable - bids are accepted only on auctions in progress, place refuses the bids of the seller
+func checkBiddable(auction *model.Auction, now time.Time) error {
+	if auction.Status != model.InProgress || !now.Before(auction.ExpiredAt) {
+		return model.ErrNotInProgress
+	}
+
+	return nil
+}
+
+// applyBid - validates the bid against the locked auction and moves the auction to the new high bid
+func applyBid(auction *model.Auction, bid model.BidInput, increment bidermodel.Increment, now time.Time) error {
+	if err := checkBiddable(auction, now); err != nil {
+		return err
+	}
+
+	minimum := minimumBid(*auction, increment)
+	if bid.Amount < minimum {
+		return fmt.Errorf("%w: minimum bid is %d", model.ErrBidTooLow, minimum)
+	}
+
+	auction.WinningPrice = auction.Money(bid.Amount)
+	auction.WinnerUuid = bid.BidderUuid
+
+	return nil
+}
+
+// applyProxyBid - stores the bidder's maximum, opens with the minimum bid when the bidder is not leading
+// and lets the proxies compete
+func applyProxyBid(auction *model.Auction, input model.ProxyBidInput, proxies []bidermodel.ProxyBid, increment bidermodel.Increment, now time.Time) (model.Placement, error) {
+	var bids []model.Bid
+
+	if err := checkBiddable(auction, now); err != nil {
+		return model.Placement{}, err
+	}
+
+	leading := auction.WinnerUuid == input.BidderUuid
+	minimum := minimumBid(*auction, increment)
+	if leading {
+		minimum = auction.WinningPrice.Amount
+	}
+
+	if input.MaxAmount < minimum {
+		return model.Placement{}, fmt.Errorf("%w: maximum bid must be at least %d", model.ErrBidTooLow, minimum)
+	}
+
+	proxy := bidermodel.ProxyBid{
+		AuctionUuid: auction.Uuid,
+		BidderUuid:  input.BidderUuid,
+		MaxAmount:   input.MaxAmount,
+		CreatedAt:   now,
+	}
+	proxies = upsertProxy(proxies, &proxy)
+
+	if !leading {
+		auction.WinningPrice = auction.Money(minimum)
+		auction.WinnerUuid = input.BidderUuid
+		bids = append(bids, model.Bid{BidderUuid: input.BidderUuid, Amount: minimum, Proxy: true})
+	}
+
+	bids = append(bids, resolveProxies(auction, proxies, increment)...)
+
+	return model.Placement{
+		Bids:  append(bids, meetReserve(auction, proxies)...),
+		Proxy: &proxy,
+	}, nil
+}
+
+// meetReserve - a leading proxy whose maximum covers the reserve bids the reserve right away
+func meetReserve(auction *model.Auction, proxies []bidermodel.ProxyBid) []model.Bid {
+	if auction.HasReserveMet() {
+		return nil
+	}
+
+	for _, proxy := range proxies {
+		if proxy.BidderUuid == auction.WinnerUuid && proxy.MaxAmount >= auction.ReservePrice.Amount {
+			auction.WinningPrice = auction.ReservePrice
+
+			return []model.Bid{{BidderUuid: proxy.BidderUuid, Amount: auction.ReservePrice.Amount, Proxy: true}}
+		}
+	}
+
+	return nil
+}
+
+// applyBuyNow - sells the locked auction at its buy it now price
+func applyBuyNow(auction *model.Auction, bidderUuid string, now time.Time) (model.Placement, error) {
+	if err := checkBiddable(auction, now); err != nil {
+		return model.Placement{}, err
+	}
+
+	if !auction.CanBuyNow() {
+		return model.Placement{}, model.ErrBuyNowClosed
+	}
+
+	auction.WinningPrice = auction.BuyNowPrice
+	auction.WinnerUuid = bidderUuid
+	auction.Status = model.Sold
+
+	return model.Placement{
+		Bids: []model.Bid{{BidderUuid: bidderUuid, Amount: auction.BuyNowPrice.Amount}},
+	}, nil
+}
+
+// upsertProxy - replaces the bidder's previous maximum, keeping its original time so it keeps its priority on ties
+func upsertProxy(proxies []bidermodel.ProxyBid, proxy *bidermodel.ProxyBid) []bidermodel.ProxyBid {
+	result := make([]bidermodel.ProxyBid, 0, len(proxies)+1)
+
+	for _, existing := range proxies {
+		if existing.BidderUuid == proxy.BidderUuid {
+			proxy.CreatedAt = existing.CreatedAt
+			continue
+		}
+		result = append(result, existing)
+	}
+
+	return append(result, *proxy)
+}
+
+// resolveProxies - runs the proxy bidders against the new high bid and returns the bids they made
+func resolveProxies(auction *model.Auction, proxies []bidermodel.ProxyBid, increment bidermodel.Increment) []model.Bid {
+	var bids []model.Bid
+
+	state, autoBids := bider.ResolveProxyBids(bidermodel.ProxyState{
+		Price:  auction.WinningPrice.Amount,
+		Leader: auction.WinnerUuid,
+	}, proxies, increment)
+
+	auction.WinningPrice = auction.Money(state.Price)
+	auction.WinnerUuid = state.Leader
+
+	for _, autoBid := range autoBids {
+		bids = append(bids, model.Bid{BidderUuid: autoBid.BidderUuid, Amount: autoBid.Amount, Proxy: true})
+	}
+
+	return bids
+}
+
+// minimumBid - the opening price until the first bid, afterward the high bid plus the increment of its price band
+func minimumBid(auction model.Auction, increment bidermodel.Increment) int64 {
+	if auction.WinnerUuid == "" {
+		return auction.Price.Amount
+	}
+
+	return auction.WinningPrice.Amount + increment(auction.WinningPrice.Amount)
+}
+
+// extendSoftClose - pushes the end of the auction when a bid lands inside the soft close window,
+// the new end never goes past the original end plus the maximum extension
+func extendSoftClose(auction *model.Auction, config SoftCloseConfig, now time.Time) bool {
+	window := time.Duration(config.WindowSeconds) * time.Second
+	extension := time.Duration(config.ExtensionSeconds) * time.Second
+
+	if window <= 0 || extension <= 0 || auction.ExpiredAt.Sub(now) > window {
+		return false
+	}
+
+	extended := auction.ExpiredAt.Add(extension)
+	limit := auction.OriginalExpiredAt.Add(time.Duration(config.MaxExtensionSeconds) * time.Second)
+	if extended.After(limit) {
+		extended = limit
+	}
+
+	if !extended.After(auction.ExpiredAt) {
+		return false
+	}
+
+	auction.ExpiredAt = extended
+
+	return true
+}
+
+// checkCurrency - a bid naming a currency must be in the currency of the auction, bids without one are taken in it
+func checkCurrency(auction model.Auction, currency money.Currency) error {
+	if currency == "" || currency == auction.Currency() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w: bid in %s on an auction in %s", model.ErrInvalidInput, money.ErrCurrencyMismatch, currency, auction.Currency())
+}
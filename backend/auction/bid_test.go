@@ -0,0 +1,591 @@
+package auction
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+func flatIncrement(increment int64) bidermodel.Increment {
+	return func(price int64) int64 {
+		return increment
+	}
+}
+
+func TestApplyBid(t *testing.T) {
+	now := time.Now()
+	open := model.Auction{
+		Uuid:      "auction-uuid",
+		UserUuid:  "seller-uuid",
+		Price:     usd(100),
+		ExpiredAt: now.Add(time.Hour),
+		Status:    model.InProgress,
+	}
+	leading := open
+	leading.WinningPrice = usd(120)
+	leading.WinnerUuid = "bidder-a"
+	leading.BiddersCount = 1
+
+	tests := []struct {
+		name      string
+		auction   model.Auction
+		bid       model.BidInput
+		wantErr   error
+		wantPrice int64
+	}{
+		{
+			name:      "first bid at opening price",
+			auction:   open,
+			bid:       model.BidInput{BidderUuid: "bidder-a", Amount: 100},
+			wantPrice: 100,
+		},
+		{
+			name:    "first bid under opening price",
+			auction: open,
+			bid:     model.BidInput{BidderUuid: "bidder-a", Amount: 99},
+			wantErr: model.ErrBidTooLow,
+		},
+		{
+			name:    "bid under high bid plus increment",
+			auction: leading,
+			bid:     model.BidInput{BidderUuid: "bidder-b", Amount: 124},
+			wantErr: model.ErrBidTooLow,
+		},
+		{
+			name:      "bid at high bid plus increment",
+			auction:   leading,
+			bid:       model.BidInput{BidderUuid: "bidder-b", Amount: 125},
+			wantPrice: 125,
+		},
+		{
+			name:    "auction already expired",
+			auction: model.Auction{Price: usd(100), ExpiredAt: now.Add(-time.Second), Status: model.InProgress},
+			bid:     model.BidInput{BidderUuid: "bidder-a", Amount: 200},
+			wantErr: model.ErrNotInProgress,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			auction := test.auction
+
+			err := applyBid(&auction, test.bid, flatIncrement(5), now)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				assert.Equal(t, test.auction, auction)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantPrice, auction.WinningPrice.Amount)
+			assert.Equal(t, test.bid.BidderUuid, auction.WinnerUuid)
+		})
+	}
+}
+
+func TestServiceAuction_PlaceBid(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("PlaceBid", "auction-uuid").Return(model.Auction{
+		Uuid:      "auction-uuid",
+		UserUuid:  "seller-uuid",
+		Price:     usd(100),
+		ExpiredAt: time.Now().Add(time.Hour),
+		Status:    model.InProgress,
+	}, nil, nil)
+	service := New(repo, acceptingPublisher(), userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 5}}, zap.NewNop())
+
+	result, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 150})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), result.WinningPrice.Amount)
+	assert.Equal(t, "bidder-a", result.WinnerUuid)
+
+	_, err = service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a"})
+
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestServiceAuction_PlaceBidActor(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   string
+		auction model.Auction
+		wantErr error
+	}{
+		{
+			name:    "another user's bidder",
+			actor:   "user-bidder-b",
+			auction: model.Auction{Uuid: "auction-uuid", UserUuid: "seller-uuid"},
+			wantErr: model.ErrForbidden,
+		},
+		{
+			name:    "no user",
+			auction: model.Auction{Uuid: "auction-uuid", UserUuid: "seller-uuid"},
+			wantErr: model.ErrForbidden,
+		},
+		{
+			name:    "the seller's own bidder",
+			actor:   "user-bidder-a",
+			auction: model.Auction{Uuid: "auction-uuid", UserUuid: "user-bidder-a"},
+			wantErr: model.ErrInvalidInput,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.auction.Price = usd(100)
+			test.auction.Status = model.InProgress
+			test.auction.ExpiredAt = time.Now().Add(time.Hour)
+			repo := &MockRepository{}
+			repo.mock.On("PlaceBid", "auction-uuid").Return(test.auction, nil, nil)
+			service := New(repo, acceptingPublisher(), userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 5}}, zap.NewNop())
+
+			_, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: test.actor, Amount: 150})
+
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Empty(t, repo.placements)
+		})
+	}
+}
+
+func TestServiceAuction_PlaceBidRefusesOtherCurrency(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("PlaceBid", "auction-uuid").Return(model.Auction{
+		Uuid:      "auction-uuid",
+		UserUuid:  "seller-uuid",
+		Price:     money.Money{Amount: 100, Currency: money.EUR},
+		ExpiredAt: time.Now().Add(time.Hour),
+		Status:    model.InProgress,
+	}, nil, nil)
+	service := New(repo, acceptingPublisher(), userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 5}}, zap.NewNop())
+
+	_, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 150, Currency: money.USD})
+
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	_, err = service.PlaceProxyBid(model.ProxyBidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", MaxAmount: 150, Currency: money.USD})
+
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	result, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 150, Currency: money.EUR})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), result.WinningPrice.Amount)
+}
+
+func TestExtendSoftClose(t *testing.T) {
+	now := time.Now()
+	config := SoftCloseConfig{WindowSeconds: 60, ExtensionSeconds: 120, MaxExtensionSeconds: 300}
+
+	tests := []struct {
+		name         string
+		expiredAt    time.Time
+		original     time.Time
+		config       SoftCloseConfig
+		wantExtended bool
+		wantEnd      time.Time
+	}{
+		{
+			name:      "bid outside the window",
+			expiredAt: now.Add(2 * time.Minute),
+			original:  now.Add(2 * time.Minute),
+			config:    config,
+			wantEnd:   now.Add(2 * time.Minute),
+		},
+		{
+			name:         "bid inside the window",
+			expiredAt:    now.Add(30 * time.Second),
+			original:     now.Add(30 * time.Second),
+			config:       config,
+			wantExtended: true,
+			wantEnd:      now.Add(150 * time.Second),
+		},
+		{
+			name:         "extension capped by the maximum",
+			expiredAt:    now.Add(30 * time.Second),
+			original:     now.Add(-4 * time.Minute),
+			config:       config,
+			wantExtended: true,
+			wantEnd:      now.Add(time.Minute),
+		},
+		{
+			name:      "cap already reached",
+			expiredAt: now.Add(30 * time.Second),
+			original:  now.Add(-270 * time.Second),
+			config:    config,
+			wantEnd:   now.Add(30 * time.Second),
+		},
+		{
+			name:      "soft close disabled",
+			expiredAt: now.Add(30 * time.Second),
+			original:  now.Add(30 * time.Second),
+			config:    SoftCloseConfig{},
+			wantEnd:   now.Add(30 * time.Second),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			auction := model.Auction{ExpiredAt: test.expiredAt, OriginalExpiredAt: test.original}
+
+			extended := extendSoftClose(&auction, test.config, now)
+
+			assert.Equal(t, test.wantExtended, extended)
+			assert.True(t, test.wantEnd.Equal(auction.ExpiredAt), "expected end %v got %v", test.wantEnd, auction.ExpiredAt)
+		})
+	}
+}
+
+func TestServiceAuction_PlaceBidPublishesExtension(t *testing.T) {
+	now := time.Now()
+	repo := &MockRepository{}
+	pub := &MockPublisher{}
+	repo.mock.On("PlaceBid", "auction-uuid").Return(model.Auction{
+		Uuid:              "auction-uuid",
+		UserUuid:          "seller-uuid",
+		Price:             usd(100),
+		ExpiredAt:         now.Add(10 * time.Second),
+		OriginalExpiredAt: now.Add(10 * time.Second),
+		Status:            model.InProgress,
+	}, nil, nil)
+	pub.mock.On("Publish", mock.Anything).Return(nil)
+	config := Config{
+		Bidding:   BiddingConfig{MinIncrement: 1},
+		SoftClose: SoftCloseConfig{WindowSeconds: 60, ExtensionSeconds: 60, MaxExtensionSeconds: 600},
+	}
+	service := New(repo, pub, userBidders{}, config, zap.NewNop())
+
+	result, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 100})
+
+	assert.NoError(t, err)
+	assert.True(t, result.ExpiredAt.After(now.Add(time.Minute)))
+	pub.mock.AssertNumberOfCalls(t, "Publish", 1)
+
+	var event model.Event
+	assert.NoError(t, json.Unmarshal(pub.mock.Calls[0].Arguments.Get(0).([]byte), &event))
+	assert.Equal(t, model.EventExtended, event.Type)
+	assert.True(t, result.ExpiredAt.Equal(event.ExpiredAt))
+}
+
+func TestServiceAuction_PlaceBidAgainstProxy(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("PlaceBid", "auction-uuid").Return(model.Auction{
+		Uuid:         "auction-uuid",
+		UserUuid:     "seller-uuid",
+		Price:        usd(100),
+		WinningPrice: usd(100),
+		WinnerUuid:   "bidder-a",
+		ExpiredAt:    time.Now().Add(time.Hour),
+		Status:       model.InProgress,
+	}, []bidermodel.ProxyBid{
+		{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", MaxAmount: 200},
+	}, nil)
+	service := New(repo, acceptingPublisher(), userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 5}}, zap.NewNop())
+
+	result, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-b", ActorUuid: "user-bidder-b", Amount: 150})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bidder-a", result.WinnerUuid)
+	assert.Equal(t, int64(155), result.WinningPrice.Amount)
+	assert.Equal(t, []model.Bid{
+		{BidderUuid: "bidder-b", Amount: 150},
+		{BidderUuid: "bidder-a", Amount: 155, Proxy: true},
+	}, repo.placements[0].Bids)
+}
+
+func TestServiceAuction_PlaceProxyBid(t *testing.T) {
+	tests := []struct {
+		name       string
+		auction    model.Auction
+		proxies    []bidermodel.ProxyBid
+		input      model.ProxyBidInput
+		wantErr    error
+		wantLeader string
+		wantPrice  int64
+		wantBids   []model.Bid
+	}{
+		{
+			name:       "opening proxy bids the opening price",
+			auction:    model.Auction{Uuid: "auction-uuid", Price: usd(100)},
+			input:      model.ProxyBidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", MaxAmount: 300},
+			wantLeader: "bidder-a",
+			wantPrice:  100,
+			wantBids:   []model.Bid{{BidderUuid: "bidder-a", Amount: 100, Proxy: true}},
+		},
+		{
+			name:       "proxy outbids a manual leader",
+			auction:    model.Auction{Uuid: "auction-uuid", Price: usd(100), WinningPrice: usd(120), WinnerUuid: "bidder-b"},
+			input:      model.ProxyBidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", MaxAmount: 300},
+			wantLeader: "bidder-a",
+			wantPrice:  125,
+			wantBids:   []model.Bid{{BidderUuid: "bidder-a", Amount: 125, Proxy: true}},
+		},
+		{
+			name:       "higher existing proxy keeps the lead",
+			auction:    model.Auction{Uuid: "auction-uuid", Price: usd(100), WinningPrice: usd(100), WinnerUuid: "bidder-b"},
+			proxies:    []bidermodel.ProxyBid{{AuctionUuid: "auction-uuid", BidderUuid: "bidder-b", MaxAmount: 500}},
+			input:      model.ProxyBidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", MaxAmount: 300},
+			wantLeader: "bidder-b",
+			wantPrice:  305,
+			wantBids: []model.Bid{
+				{BidderUuid: "bidder-a", Amount: 105, Proxy: true},
+				{BidderUuid: "bidder-a", Amount: 300, Proxy: true},
+				{BidderUuid: "bidder-b", Amount: 305, Proxy: true},
+			},
+		},
+		{
+			name:       "leader raising the maximum places no bid",
+			auction:    model.Auction{Uuid: "auction-uuid", Price: usd(100), WinningPrice: usd(150), WinnerUuid: "bidder-a"},
+			input:      model.ProxyBidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", MaxAmount: 400},
+			wantLeader: "bidder-a",
+			wantPrice:  150,
+		},
+		{
+			name:    "maximum under the minimum bid",
+			auction: model.Auction{Uuid: "auction-uuid", Price: usd(100), WinningPrice: usd(150), WinnerUuid: "bidder-b"},
+			input:   model.ProxyBidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", MaxAmount: 154},
+			wantErr: model.ErrBidTooLow,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.auction.Status = model.InProgress
+			test.auction.UserUuid = "seller-uuid"
+			test.auction.ExpiredAt = time.Now().Add(time.Hour)
+			test.input.ActorUuid = "user-" + test.input.BidderUuid
+			repo := &MockRepository{}
+			repo.mock.On("PlaceBid", "auction-uuid").Return(test.auction, test.proxies, nil)
+			service := New(repo, acceptingPublisher(), userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 5}}, zap.NewNop())
+
+			result, err := service.PlaceProxyBid(test.input)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantLeader, result.WinnerUuid)
+			assert.Equal(t, test.wantPrice, result.WinningPrice.Amount)
+			assert.Equal(t, test.wantBids, repo.placements[0].Bids)
+			assert.Equal(t, test.input.MaxAmount, repo.placements[0].Proxy.MaxAmount)
+		})
+	}
+}
+
+func TestAuction_CanBuyNow(t *testing.T) {
+	tests := []struct {
+		name    string
+		auction model.Auction
+		want    bool
+	}{
+		{
+			name:    "no buy it now price",
+			auction: model.Auction{Status: model.InProgress},
+		},
+		{
+			name:    "before the first bid",
+			auction: model.Auction{BuyNowPrice: usd(500), Status: model.InProgress},
+			want:    true,
+		},
+		{
+			name:    "gone after the first bid without a reserve",
+			auction: model.Auction{BuyNowPrice: usd(500), WinnerUuid: "bidder-a", WinningPrice: usd(100), Status: model.InProgress},
+		},
+		{
+			name:    "still offered while the reserve is not met",
+			auction: model.Auction{BuyNowPrice: usd(500), ReservePrice: usd(300), WinnerUuid: "bidder-a", WinningPrice: usd(250), Status: model.InProgress},
+			want:    true,
+		},
+		{
+			name:    "gone once the reserve is met",
+			auction: model.Auction{BuyNowPrice: usd(500), ReservePrice: usd(300), WinnerUuid: "bidder-a", WinningPrice: usd(300), Status: model.InProgress},
+		},
+		{
+			name:    "closed auction",
+			auction: model.Auction{BuyNowPrice: usd(500), Status: model.Expired},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.auction.CanBuyNow())
+		})
+	}
+}
+
+func TestServiceAuction_BuyNow(t *testing.T) {
+	auction := model.Auction{
+		Uuid:        "auction-uuid",
+		UserUuid:    "seller-uuid",
+		Price:       usd(100),
+		BuyNowPrice: usd(500),
+		ExpiredAt:   time.Now().Add(time.Hour),
+		Status:      model.InProgress,
+	}
+
+	t.Run("sold at the buy it now price", func(t *testing.T) {
+		repo := &MockRepository{}
+		pub := &MockPublisher{}
+		repo.mock.On("PlaceBid", "auction-uuid").Return(auction, nil, nil)
+		pub.mock.On("Publish", mock.Anything).Return(nil)
+		service := New(repo, pub, userBidders{}, Config{}, zap.NewNop())
+
+		result, err := service.BuyNow(model.AcceptInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Sold, result.Status)
+		assert.Equal(t, int64(500), result.WinningPrice.Amount)
+		assert.False(t, result.BuyNowAvailable)
+		pub.mock.AssertNumberOfCalls(t, "Publish", 1)
+	})
+
+	t.Run("gone after the first bid", func(t *testing.T) {
+		bidden := auction
+		bidden.WinnerUuid = "bidder-b"
+		bidden.WinningPrice = usd(100)
+		repo := &MockRepository{}
+		repo.mock.On("PlaceBid", "auction-uuid").Return(bidden, nil, nil)
+		service := New(repo, &MockPublisher{}, userBidders{}, Config{}, zap.NewNop())
+
+		_, err := service.BuyNow(model.AcceptInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a"})
+
+		assert.ErrorIs(t, err, model.ErrBuyNowClosed)
+	})
+}
+
+func TestServiceAuction_GetAuctionHidesReserve(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Single", "auction-uuid").Return(model.Auction{
+		Uuid:         "auction-uuid",
+		Price:        usd(100),
+		WinningPrice: usd(150),
+		WinnerUuid:   "bidder-a",
+		ReservePrice: usd(400),
+		Status:       model.InProgress,
+	}, nil)
+	service := New(repo, &MockPublisher{}, userBidders{}, Config{}, zap.NewNop())
+
+	result, err := service.GetAuction("auction-uuid")
+	assert.NoError(t, err)
+	assert.False(t, result.ReserveMet)
+
+	encoded, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encoded), "400")
+	assert.Contains(t, string(encoded), `"reserveMet":false`)
+}
+
+func TestMeetReserve(t *testing.T) {
+	auction := model.Auction{WinnerUuid: "bidder-a", WinningPrice: usd(150), ReservePrice: usd(300)}
+	proxies := []bidermodel.ProxyBid{{BidderUuid: "bidder-a", MaxAmount: 400}}
+
+	bids := meetReserve(&auction, proxies)
+
+	assert.Equal(t, int64(300), auction.WinningPrice.Amount)
+	assert.Equal(t, []model.Bid{{BidderUuid: "bidder-a", Amount: 300, Proxy: true}}, bids)
+}
+
+func TestServiceAuction_PlaceBidPublishesBid(t *testing.T) {
+	auction := model.Auction{
+		Uuid:      "auction-uuid",
+		UserUuid:  "seller-uuid",
+		Price:     usd(100),
+		ExpiredAt: time.Now().Add(time.Hour),
+		Status:    model.InProgress,
+	}
+
+	t.Run("open auction", func(t *testing.T) {
+		repo := &MockRepository{}
+		pub := acceptingPublisher()
+		repo.mock.On("PlaceBid", "auction-uuid").Return(auction, nil, nil)
+		service := New(repo, pub, userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 1}}, zap.NewNop())
+
+		_, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 120})
+
+		assert.NoError(t, err)
+		pub.mock.AssertNumberOfCalls(t, "Publish", 1)
+
+		var event model.Event
+		assert.NoError(t, json.Unmarshal(pub.mock.Calls[0].Arguments.Get(0).([]byte), &event))
+		assert.Equal(t, model.EventBid, event.Type)
+		assert.Equal(t, "bidder-a", event.WinnerUuid)
+		assert.Equal(t, int64(120), event.WinningPrice)
+	})
+
+	t.Run("names the outbid leader", func(t *testing.T) {
+		led := auction
+		led.WinnerUuid = "bidder-b"
+		led.WinningPrice = usd(110)
+		repo := &MockRepository{}
+		pub := acceptingPublisher()
+		repo.mock.On("PlaceBid", "auction-uuid").Return(led, nil, nil)
+		service := New(repo, pub, userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 1}}, zap.NewNop())
+
+		_, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 120})
+
+		assert.NoError(t, err)
+
+		var event model.Event
+		assert.NoError(t, json.Unmarshal(pub.mock.Calls[0].Arguments.Get(0).([]byte), &event))
+		assert.Equal(t, "bidder-a", event.WinnerUuid)
+		assert.Equal(t, "bidder-b", event.PreviousWinnerUuid)
+		assert.Equal(t, "bidder-a", event.BidderUuid)
+	})
+
+	t.Run("sealed auction stays quiet", func(t *testing.T) {
+		sealed := auction
+		sealed.Type = model.SealedFirstPrice
+		repo := &MockRepository{}
+		pub := acceptingPublisher()
+		repo.mock.On("PlaceBid", "auction-uuid").Return(sealed, nil, nil)
+		service := New(repo, pub, userBidders{}, Config{}, zap.NewNop())
+
+		_, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 120})
+
+		assert.NoError(t, err)
+		pub.mock.AssertNotCalled(t, "Publish", mock.Anything)
+	})
+}
+
+func TestServiceAuction_ListBids(t *testing.T) {
+	input := model.BidListInput{AuctionUuid: "auction-uuid", Page: model.PageRequest{Offset: 0, Limit: 2}}
+	repo := &MockRepository{}
+	repo.mock.On("Single", "auction-uuid").Return(model.Auction{Uuid: "auction-uuid", Status: model.InProgress}, nil)
+	repo.mock.On("ListBids", input).Return(model.BidHistory{
+		Bids: []model.Bid{
+			{Uuid: "bid-1", BidderUuid: "3f2a6c1e-8d4b-4f0a-9c1d-2b7e5a0c419e", Amount: 100},
+			{Uuid: "bid-2", BidderUuid: "7b1d0e2f-1a2b-4c3d-8e9f-0a1b2c3d4e5f", Amount: 110, Proxy: true, Retracted: true},
+		},
+		Total: 5,
+	}, nil)
+	service := New(repo, &MockPublisher{}, userBidders{}, Config{}, zap.NewNop())
+
+	result, err := service.ListBids(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), result.Total)
+	assert.Equal(t, "3f***9e", result.Bids[0].Bidder)
+	assert.Equal(t, "7b***5f", result.Bids[1].Bidder)
+
+	encoded, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encoded), "3f2a6c1e")
+	assert.Contains(t, string(encoded), `"retracted":true`)
+}
+
+// userBidders - every bidder bids for the user "user-" followed by the bidder's uuid
+type userBidders struct{}
+
+func (userBidders) FindOne(uuid string) (bidermodel.Bidder, error) {
+	return bidermodel.Bidder{Uuid: uuid, UserUuid: "user-" + uuid}, nil
+}
@@ -0,0 +1,179 @@
+package auction
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/fraud"
+	"github.com/ireuven89/hello-world/backend/utils"
+)
+
+type Config struct {
+	utils.Config
+	Bidding    BiddingConfig    `json:"bidding"`
+	Scheduler  SchedulerConfig  `json:"scheduler"`
+	SoftClose  SoftCloseConfig  `json:"softClose"`
+	Retraction RetractionConfig `json:"retraction"`
+	Rates      RatesConfig      `json:"rates"`
+	Fraud      FraudConfig      `json:"fraud"`
+	Feedback   FeedbackConfig   `json:"feedback"`
+}
+
+// BiddingConfig - Increments is the default increment table of the service, when it is empty
+// every bid must raise by MinIncrement
+type BiddingConfig struct {
+	MinIncrement int64                 `json:"minIncrement"`
+	Increments   []model.IncrementBand `json:"increments"`
+}
+
+// DefaultIncrements - the increment table of the auctions without a table of their own or of their category
+func (c BiddingConfig) DefaultIncrements() model.IncrementTable {
+	if len(c.Increments) > 0 {
+		return model.IncrementTable{Bands: c.Increments}
+	}
+
+	return model.IncrementTable{Bands: []model.IncrementBand{{Increment: c.MinIncrement}}}
+}
+
+// SchedulerConfig - EndingSoonSeconds is how long before the end an auction announces it is ending soon
+type SchedulerConfig struct {
+	IntervalSeconds   int64 `json:"intervalSeconds"`
+	BatchSize         int64 `json:"batchSize"`
+	EndingSoonSeconds int64 `json:"endingSoonSeconds"`
+}
+
+// SoftCloseConfig - a bid placed less than WindowSeconds before the end extends the auction by
+// ExtensionSeconds, as long as the end does not move more than MaxExtensionSeconds past the original end
+type SoftCloseConfig struct {
+	WindowSeconds       int64 `json:"windowSeconds"`
+	ExtensionSeconds    int64 `json:"extensionSeconds"`
+	MaxExtensionSeconds int64 `json:"maxExtensionSeconds"`
+}
+
+// RetractionConfig - a bidder may retract a bid within WindowSeconds of placing it, and never when less than
+// CutoffSeconds are left before the end. the seller and the AdminUuids may cancel the bids of any bidder,
+// only the AdminUuids may set the credit of bidders
+type RetractionConfig struct {
+	WindowSeconds int64    `json:"windowSeconds"`
+	CutoffSeconds int64    `json:"cutoffSeconds"`
+	AdminUuids    []string `json:"adminUuids"`
+}
+
+// RatesConfig - File is the rates file used to convert prices to the currency of the viewer,
+// rates are cached for CacheTtlSeconds
+type RatesConfig struct {
+	File            string `json:"file"`
+	CacheTtlSeconds int64  `json:"cacheTtlSeconds"`
+}
+
+// FraudConfig - the scanner analyzes the bids of the last LookbackHours every IntervalSeconds,
+// when BlockFlagged is set the bidders with a confirmed flag can not bid. TrustedProxies are the addresses or the
+// networks of the proxies in front of the service, the address of a bidder is read from X-Forwarded-For behind them only
+type FraudConfig struct {
+	fraud.Config
+	IntervalSeconds int64    `json:"intervalSeconds"`
+	LookbackHours   int64    `json:"lookbackHours"`
+	BlockFlagged    bool     `json:"blockFlagged"`
+	TrustedProxies  []string `json:"trustedProxies"`
+}
+
+// FeedbackConfig - the buyer and the seller of a sold auction may rate each other within WindowSeconds of the sale
+type FeedbackConfig struct {
+	WindowSeconds int64 `json:"windowSeconds"`
+}
+
+const (
+	defaultMinIncrement      = 1
+	defaultScheduleInterval  = 10
+	defaultScheduleBatchSize = 100
+	defaultEndingSoon        = 3600
+	defaultRatesFile         = "money/rates.json"
+	defaultRatesCacheTtl     = 900
+	defaultFraudInterval     = 900
+	defaultFraudLookback     = 720
+	defaultFeedbackWindow    = 30 * 24 * 3600
+)
+
+// LoadConfig - loads the auction service config of the given environment
+func LoadConfig(env string) (Config, error) {
+	var config Config
+
+	if err := utils.LoadConfigInto("auction", env, &config); err != nil {
+		return Config{}, err
+	}
+
+	if config.Bidding.MinIncrement <= 0 {
+		config.Bidding.MinIncrement = defaultMinIncrement
+	}
+
+	if len(config.Bidding.Increments) > 0 {
+		if err := validateBands(config.Bidding.Increments); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if config.Scheduler.IntervalSeconds <= 0 {
+		config.Scheduler.IntervalSeconds = defaultScheduleInterval
+	}
+
+	if config.Scheduler.BatchSize <= 0 {
+		config.Scheduler.BatchSize = defaultScheduleBatchSize
+	}
+
+	if config.Scheduler.EndingSoonSeconds <= 0 {
+		config.Scheduler.EndingSoonSeconds = defaultEndingSoon
+	}
+
+	if config.Rates.File == "" {
+		config.Rates.File = defaultRatesFile
+	}
+
+	if config.Rates.CacheTtlSeconds <= 0 {
+		config.Rates.CacheTtlSeconds = defaultRatesCacheTtl
+	}
+
+	if config.Fraud.IntervalSeconds <= 0 {
+		config.Fraud.IntervalSeconds = defaultFraudInterval
+	}
+
+	if config.Fraud.LookbackHours <= 0 {
+		config.Fraud.LookbackHours = defaultFraudLookback
+	}
+	config.Fraud.Config = config.Fraud.Config.WithDefaults()
+
+	if config.Feedback.WindowSeconds <= 0 {
+		config.Feedback.WindowSeconds = defaultFeedbackWindow
+	}
+
+	if _, err := ParseProxies(config.Fraud.TrustedProxies); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+// ParseProxies - the networks of the trusted proxies, an address without a prefix length is a network of itself
+func ParseProxies(proxies []string) ([]netip.Prefix, error) {
+	result := make([]netip.Prefix, 0, len(proxies))
+
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %s: %w", proxy, err)
+			}
+			result = append(result, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s: %w", proxy, err)
+		}
+		result = append(result, prefix.Masked())
+	}
+
+	return result, nil
+}
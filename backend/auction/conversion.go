@@ -0,0 +1,47 @@
+package auction
+
+import (
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// convertAuction - converts the prices of the auction to the currency of the viewer. conversions are a
+// display aid only, an auction whose rate is unavailable is shown in its own currency alone
+func convertAuction(rates money.RateProvider, auction *model.Auction, to money.Currency) {
+	if rates == nil || to == "" || auction.Currency() == to {
+		return
+	}
+
+	rate, err := rates.Rate(auction.Currency(), to)
+	if err != nil {
+		return
+	}
+
+	_ = auction.Convert(rate)
+}
+
+func convertAuctions(rates money.RateProvider, auctions []model.Auction, to money.Currency) {
+	for i := range auctions {
+		convertAuction(rates, &auctions[i], to)
+	}
+}
+
+// convertBids - converts the amounts of the bid history to the currency of the viewer
+func convertBids(rates money.RateProvider, history *model.BidHistory, to money.Currency) {
+	if rates == nil || to == "" || history.Currency == to {
+		return
+	}
+
+	rate, err := rates.Rate(history.Currency, to)
+	if err != nil {
+		return
+	}
+
+	for i := range history.Bids {
+		conversion, err := rate.Convert(money.Money{Amount: history.Bids[i].Amount, Currency: history.Currency})
+		if err != nil {
+			return
+		}
+		history.Bids[i].Converted = &conversion
+	}
+}
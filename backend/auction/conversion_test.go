@@ -0,0 +1,89 @@
+package auction
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+func testRates() money.RateProvider {
+	return money.NewStaticProvider(money.RatesFile{
+		Base:  money.USD,
+		AsOf:  time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Rates: map[money.Currency]float64{money.EUR: 0.8, money.JPY: 150},
+	})
+}
+
+func TestConvertAuctions(t *testing.T) {
+	auctions := []model.Auction{
+		{Uuid: "dollars", Price: usd(1000), CurrentPrice: usd(1500), WinnerUuid: "bidder-a", WinningPrice: usd(1500)},
+		{Uuid: "sealed", Price: usd(1000), CurrentPrice: usd(1000), WinnerUuid: "bidder-a", WinningPrice: usd(1500), Type: model.SealedFirstPrice},
+		{Uuid: "euros", Price: money.Money{Amount: 1000, Currency: money.EUR}, CurrentPrice: money.Money{Amount: 1000, Currency: money.EUR}},
+		{Uuid: "pounds", Price: money.Money{Amount: 1000, Currency: money.GBP}, CurrentPrice: money.Money{Amount: 1000, Currency: money.GBP}},
+	}
+
+	convertAuctions(testRates(), auctions, money.EUR)
+
+	assert.Equal(t, &model.Converted{
+		Currency:     money.EUR,
+		Rate:         0.8,
+		AsOf:         time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Indicative:   true,
+		Price:        800,
+		CurrentPrice: 1200,
+		WinningPrice: 1200,
+	}, auctions[0].Converted)
+	assert.Zero(t, auctions[1].Converted.WinningPrice, "sealed bids stay hidden")
+	assert.Nil(t, auctions[2].Converted, "already in the viewer currency")
+	assert.Nil(t, auctions[3].Converted, "no rate for the auction currency")
+	assert.Equal(t, int64(1000), auctions[0].Price.Amount, "the auction keeps its own prices")
+}
+
+func TestConvertBids(t *testing.T) {
+	history := model.BidHistory{
+		Currency: money.USD,
+		Bids:     []model.Bid{{Amount: 1000}, {Amount: 1234}},
+	}
+
+	convertBids(testRates(), &history, money.JPY)
+
+	assert.Equal(t, money.Money{Amount: 1500, Currency: money.JPY}, history.Bids[0].Converted.Money)
+	assert.Equal(t, money.Money{Amount: 1851, Currency: money.JPY}, history.Bids[1].Converted.Money)
+	assert.True(t, history.Bids[0].Converted.Indicative)
+
+	convertBids(nil, &history, money.EUR)
+	assert.Equal(t, money.JPY, history.Bids[0].Converted.Money.Currency)
+}
+
+func TestViewerCurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    money.Currency
+		wantErr error
+	}{
+		{"none", "", "", nil},
+		{"currency", "?currency=eur", money.EUR, nil},
+		{"region", "?region=IL", money.ILS, nil},
+		{"currency wins over region", "?currency=JPY&region=IL", money.JPY, nil},
+		{"unknown currency", "?currency=ABC", "", model.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currency, err := viewerCurrency(httptest.NewRequest("GET", "/auctions"+tt.query, nil))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, currency)
+		})
+	}
+}
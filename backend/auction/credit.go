@@ -0,0 +1,71 @@
+package auction
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/bider"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// SetCredit - an admin sets the credit limit and the deposit the bids of the bidder are checked against.
+// a zero limit or deposit takes the currency of the other one
+func (s *ServiceAuction) SetCredit(input bidermodel.CreditInput) (bidermodel.Credit, error) {
+	if input.BidderUuid == "" {
+		return bidermodel.Credit{}, fmt.Errorf("%w: bidderUuid is required", model.ErrInvalidInput)
+	}
+
+	if !s.isAdmin(input.ActorUuid) {
+		return bidermodel.Credit{}, model.ErrForbidden
+	}
+
+	if err := bider.ValidateCredit(input); err != nil {
+		return bidermodel.Credit{}, fmt.Errorf("%w: %w", model.ErrInvalidInput, err)
+	}
+
+	currency := bidermodel.Credit{Limit: input.Limit, Deposit: input.Deposit}.Currency()
+	input.Limit.Currency, input.Deposit.Currency = currency, currency
+
+	result, err := s.repo.SetCredit(input)
+	if err != nil {
+		s.logger.Error("ServiceAuction.SetCredit failed setting credit", zap.Any("input", input), zap.Error(err))
+		return bidermodel.Credit{}, err
+	}
+
+	return result, nil
+}
+
+// GetCredit - the credit of the bidder with what it is committed to on the open auctions and what is left of it,
+// only an admin and the user the bidder bids for see it
+func (s *ServiceAuction) GetCredit(bidderUuid string, actorUuid string) (bidermodel.Exposure, error) {
+	if !s.isAdmin(actorUuid) {
+		if _, err := s.actingBidder(bidderUuid, actorUuid); err != nil {
+			return bidermodel.Exposure{}, err
+		}
+	}
+
+	credit, err := s.repo.Credit(bidderUuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.GetCredit failed getting credit", zap.String("bidder", bidderUuid), zap.Error(err))
+		return bidermodel.Exposure{}, err
+	}
+
+	result := bidermodel.Exposure{Credit: credit}
+	if !credit.Limited() {
+		return result, nil
+	}
+
+	committed, err := s.repo.Exposure(bidderUuid, credit.Currency())
+	if err != nil {
+		s.logger.Error("ServiceAuction.GetCredit failed getting exposure", zap.String("bidder", bidderUuid), zap.Error(err))
+		return bidermodel.Exposure{}, err
+	}
+
+	result.Exposure = money.Money{Amount: committed, Currency: credit.Currency()}
+	result.Remaining = money.Money{Amount: max(credit.Available().Amount-committed, 0), Currency: credit.Currency()}
+
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package auction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+func TestServiceAuction_SetCredit(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("SetCredit", mock.Anything).Return(bidermodel.Credit{BidderUuid: "bidder-a"}, nil)
+	service := New(repo, acceptingPublisher(), userBidders{}, Config{Retraction: RetractionConfig{AdminUuids: []string{"admin-uuid"}}}, zap.NewNop())
+
+	_, err := service.SetCredit(bidermodel.CreditInput{
+		BidderUuid: "bidder-a",
+		ActorUuid:  "admin-uuid",
+		Limit:      money.Money{Amount: 10000, Currency: money.EUR},
+	})
+
+	assert.NoError(t, err)
+	input := repo.mock.Calls[0].Arguments.Get(0).(bidermodel.CreditInput)
+	assert.Equal(t, money.Money{Currency: money.EUR}, input.Deposit)
+}
+
+func TestServiceAuction_SetCreditRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   bidermodel.CreditInput
+		wantErr error
+	}{
+		{"not an admin", bidermodel.CreditInput{BidderUuid: "bidder-a", ActorUuid: "bidder-a", Limit: money.Money{Amount: 100, Currency: money.USD}}, model.ErrForbidden},
+		{"no bidder", bidermodel.CreditInput{ActorUuid: "admin-uuid", Limit: money.Money{Amount: 100, Currency: money.USD}}, model.ErrInvalidInput},
+		{"negative limit", bidermodel.CreditInput{BidderUuid: "bidder-a", ActorUuid: "admin-uuid", Limit: money.Money{Amount: -100, Currency: money.USD}}, model.ErrInvalidInput},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			service := New(repo, acceptingPublisher(), userBidders{}, Config{Retraction: RetractionConfig{AdminUuids: []string{"admin-uuid"}}}, zap.NewNop())
+
+			_, err := service.SetCredit(test.input)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			repo.mock.AssertNotCalled(t, "SetCredit", mock.Anything)
+		})
+	}
+}
+
+func TestServiceAuction_GetCredit(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Credit", "bidder-a").Return(bidermodel.Credit{
+		BidderUuid: "bidder-a",
+		Limit:      money.Money{Amount: 10000, Currency: money.USD},
+		Deposit:    money.Money{Amount: 2000, Currency: money.USD},
+	}, nil)
+	repo.mock.On("Exposure", "bidder-a", money.USD).Return(int64(7500), nil)
+	service := New(repo, acceptingPublisher(), userBidders{}, Config{}, zap.NewNop())
+
+	result, err := service.GetCredit("bidder-a", "user-bidder-a")
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.Money{Amount: 7500, Currency: money.USD}, result.Exposure)
+	assert.Equal(t, money.Money{Amount: 4500, Currency: money.USD}, result.Remaining)
+}
+
+func TestServiceAuction_GetCreditOfUnlimitedBidder(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Credit", "bidder-a").Return(bidermodel.Credit{BidderUuid: "bidder-a"}, nil)
+	service := New(repo, acceptingPublisher(), userBidders{}, Config{}, zap.NewNop())
+
+	result, err := service.GetCredit("bidder-a", "user-bidder-a")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Remaining.IsZero())
+	repo.mock.AssertNotCalled(t, "Exposure", mock.Anything, mock.Anything)
+}
+
+func TestServiceAuction_GetCreditAccess(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   string
+		wantErr error
+	}{
+		{"an admin", "admin-uuid", nil},
+		{"another user", "user-bidder-b", model.ErrForbidden},
+		{"no user", "", model.ErrForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Credit", "bidder-a").Return(bidermodel.Credit{BidderUuid: "bidder-a"}, nil)
+			service := New(repo, acceptingPublisher(), userBidders{}, Config{Retraction: RetractionConfig{AdminUuids: []string{"admin-uuid"}}}, zap.NewNop())
+
+			_, err := service.GetCredit("bidder-a", test.actor)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			if test.wantErr != nil {
+				repo.mock.AssertNotCalled(t, "Credit", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestServiceAuction_PlacementsCarryTheCommitment(t *testing.T) {
+	auction := model.Auction{
+		Uuid:      "auction-uuid",
+		UserUuid:  "seller-uuid",
+		Price:     money.Money{Amount: 100, Currency: money.EUR},
+		ExpiredAt: time.Now().Add(time.Hour),
+		Status:    model.InProgress,
+	}
+
+	repo := &MockRepository{}
+	repo.mock.On("PlaceBid", "auction-uuid").Return(auction, nil, nil)
+	service := New(repo, acceptingPublisher(), userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 5}}, zap.NewNop())
+
+	_, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 150})
+	assert.NoError(t, err)
+	_, err = service.PlaceProxyBid(model.ProxyBidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-b", ActorUuid: "user-bidder-b", MaxAmount: 400})
+	assert.NoError(t, err)
+
+	assert.Equal(t, &bidermodel.Commitment{BidderUuid: "bidder-a", Amount: money.Money{Amount: 150, Currency: money.EUR}}, repo.placements[0].Commitment)
+	assert.Equal(t, &bidermodel.Commitment{BidderUuid: "bidder-b", Amount: money.Money{Amount: 400, Currency: money.EUR}}, repo.placements[1].Commitment)
+}
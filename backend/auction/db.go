@@ -0,0 +1,47 @@
+package auction
+
+import (
+	"database/sql"
+	"path/filepath"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ido50/sqlz"
+
+	"github.com/ireuven89/hello-world/backend/environment"
+)
+
+// MustNewDB - returns the db connection, the migrations directory of the db, and an error if anything failed
+func MustNewDB() (*sqlz.DB, string, error) {
+	cfg := mysql.Config{
+		User:      environment.Variables.AuctionsDbUser,
+		Passwd:    environment.Variables.AuctionsDbPassword,
+		Addr:      environment.Variables.AuctionsDbHost,
+		DBName:    "auctions",
+		Net:       "tcp",
+		ParseTime: true,
+	}
+	auctionsDB, err := sql.Open("mysql", cfg.FormatDSN())
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	//ping check
+	if err = auctionsDB.Ping(); err != nil {
+		return nil, "", err
+	}
+
+	//create lock table if not exists
+	if _, err = auctionsDB.Exec("create table if not exists lock_table(lock_row int)"); err != nil {
+		return nil, "", err
+	}
+
+	//set migration dir
+	migrationDir, err := filepath.Abs("./db/migrations/auctions")
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return sqlz.New(auctionsDB, "mysql"), migrationDir, nil
+}
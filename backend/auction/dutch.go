@@ -0,0 +1,54 @@
+package auction
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+// AcceptAuction - takes a dutch auction at its current price. the auction row is locked while accepting,
+// so only the first acceptance wins and the others find the auction sold
+func (s *ServiceAuction) AcceptAuction(input model.AcceptInput) (model.Auction, error) {
+	if input.AuctionUuid == "" || input.BidderUuid == "" {
+		return model.Auction{}, fmt.Errorf("%w: auctionUuid and bidderUuid are required", model.ErrInvalidInput)
+	}
+
+	result, err := s.place(input.AuctionUuid, input.BidderUuid, input.ActorUuid, input.IP, func(auction *model.Auction, proxies []bidermodel.ProxyBid, now time.Time) (model.Placement, error) {
+		return applyAccept(auction, input.BidderUuid, now)
+	})
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.AcceptAuction failed accepting auction", zap.Any("input", input), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	s.publishClosed(result)
+	present(&result, time.Now())
+
+	return result, nil
+}
+
+// applyAccept - sells the locked dutch auction to the bidder at the price of the given instant
+func applyAccept(auction *model.Auction, bidderUuid string, now time.Time) (model.Placement, error) {
+	if auction.Type != model.Dutch {
+		return model.Placement{}, fmt.Errorf("%w: only dutch auctions can be accepted", model.ErrInvalidInput)
+	}
+
+	if err := checkBiddable(auction, now); err != nil {
+		return model.Placement{}, err
+	}
+
+	price := auction.PriceAt(now)
+
+	auction.WinningPrice = price
+	auction.WinnerUuid = bidderUuid
+	auction.Status = model.Sold
+
+	return model.Placement{
+		Bids: []model.Bid{{BidderUuid: bidderUuid, Amount: price.Amount}},
+	}, nil
+}
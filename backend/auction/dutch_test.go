@@ -0,0 +1,168 @@
+package auction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+func TestAuction_PriceAt(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	dutch := model.Auction{
+		Price:               usd(1000),
+		FloorPrice:          usd(600),
+		PriceStep:           usd(50),
+		StepIntervalSeconds: 60,
+		CreatedAt:           start,
+		Status:              model.InProgress,
+		Type:                model.Dutch,
+	}
+
+	tests := []struct {
+		name    string
+		auction model.Auction
+		at      time.Time
+		want    int64
+	}{
+		{
+			name:    "start price before the first step",
+			auction: dutch,
+			at:      start.Add(59 * time.Second),
+			want:    1000,
+		},
+		{
+			name:    "dropped by the elapsed steps",
+			auction: dutch,
+			at:      start.Add(3*time.Minute + 10*time.Second),
+			want:    850,
+		},
+		{
+			name:    "never below the floor",
+			auction: dutch,
+			at:      start.Add(time.Hour),
+			want:    600,
+		},
+		{
+			name:    "english auction without bids",
+			auction: model.Auction{Price: usd(100), Status: model.InProgress},
+			at:      start,
+			want:    100,
+		},
+		{
+			name:    "english auction with a high bid",
+			auction: model.Auction{Price: usd(100), WinningPrice: usd(150), WinnerUuid: "bidder-a", Status: model.InProgress},
+			at:      start,
+			want:    150,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.auction.PriceAt(test.at).Amount)
+		})
+	}
+}
+
+func TestServiceAuction_AcceptAuction(t *testing.T) {
+	repo := &MockRepository{}
+	pub := &MockPublisher{}
+	repo.mock.On("PlaceBid", "dutch-uuid").Return(model.Auction{
+		Uuid:                "dutch-uuid",
+		UserUuid:            "seller-uuid",
+		Price:               usd(1000),
+		FloorPrice:          usd(100),
+		PriceStep:           usd(100),
+		StepIntervalSeconds: 60,
+		CreatedAt:           time.Now().Add(-150 * time.Second),
+		ExpiredAt:           time.Now().Add(time.Hour),
+		Status:              model.InProgress,
+		Type:                model.Dutch,
+	}, nil, nil)
+	pub.mock.On("Publish", mock.Anything).Return(nil)
+	service := New(repo, pub, userBidders{}, Config{}, zap.NewNop())
+
+	result, err := service.AcceptAuction(model.AcceptInput{AuctionUuid: "dutch-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Sold, result.Status)
+	assert.Equal(t, "bidder-a", result.WinnerUuid)
+	assert.Equal(t, int64(800), result.WinningPrice.Amount)
+	assert.Equal(t, []model.Bid{{BidderUuid: "bidder-a", Amount: 800}}, repo.placements[0].Bids)
+	pub.mock.AssertNumberOfCalls(t, "Publish", 1)
+}
+
+func TestApplyAccept(t *testing.T) {
+	now := time.Now()
+	sold := model.Auction{Type: model.Dutch, Status: model.Sold, WinnerUuid: "bidder-a", ExpiredAt: now.Add(time.Hour)}
+
+	_, err := applyAccept(&sold, "bidder-b", now)
+	assert.ErrorIs(t, err, model.ErrNotInProgress)
+
+	english := model.Auction{Type: model.English, Status: model.InProgress, ExpiredAt: now.Add(time.Hour)}
+
+	_, err = applyAccept(&english, "bidder-b", now)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestServiceAuction_UpdateDutchAuction(t *testing.T) {
+	stored := model.Auction{
+		Uuid:                "dutch-uuid",
+		UserUuid:            "seller-uuid",
+		Price:               usd(1000),
+		FloorPrice:          usd(400),
+		PriceStep:           usd(50),
+		StepIntervalSeconds: 60,
+		Status:              model.InProgress,
+		Type:                model.Dutch,
+	}
+
+	tests := []struct {
+		name    string
+		input   model.AuctionInput
+		wantErr error
+	}{
+		{
+			name:  "lower floor",
+			input: model.AuctionInput{Uuid: "dutch-uuid", FloorPrice: usd(300)},
+		},
+		{
+			name:    "floor above the stored price",
+			input:   model.AuctionInput{Uuid: "dutch-uuid", FloorPrice: usd(1200)},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "price below the stored floor",
+			input:   model.AuctionInput{Uuid: "dutch-uuid", Price: usd(300)},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "negative step",
+			input:   model.AuctionInput{Uuid: "dutch-uuid", PriceStep: usd(-10)},
+			wantErr: model.ErrInvalidInput,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Single", "dutch-uuid").Return(stored, nil)
+			repo.mock.On("Update", mock.Anything).Return(nil)
+			service := New(repo, &MockPublisher{}, userBidders{}, Config{}, zap.NewNop())
+			test.input.UserUuid = "seller-uuid"
+
+			err := service.UpdateAuction(test.input)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				repo.mock.AssertNotCalled(t, "Update", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,556 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	fraudmodel "github.com/ireuven89/hello-world/backend/fraud/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+type GetAuctionRequest struct {
+	Uuid     string
+	Locale   string
+	Currency money.Currency
+}
+
+func MakeEndpointGetAuction(s Service, rates money.RateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetAuctionRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointGetAuction failed cast request")
+		}
+
+		result, err := s.GetAuction(req.Uuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointGetAuction: %w", err)
+		}
+
+		convertAuction(rates, &result, req.Currency)
+
+		if req.Locale != "" {
+			result.Localize(req.Locale)
+		}
+
+		return result, nil
+	}
+}
+
+type ListAuctionsRequest struct {
+	input    model.ListInput
+	locale   string
+	currency money.Currency
+}
+
+type ListAuctionsResponse struct {
+	auctions []model.Auction
+}
+
+func MakeEndpointListAuctions(s Service, rates money.RateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListAuctionsRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListAuctions failed cast request")
+		}
+
+		result, err := s.ListAuctions(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListAuctions: %w", err)
+		}
+
+		convertAuctions(rates, result, req.currency)
+
+		if req.locale != "" {
+			for i := range result {
+				result[i].Localize(req.locale)
+			}
+		}
+
+		return ListAuctionsResponse{
+			auctions: result,
+		}, nil
+	}
+}
+
+type CreateAuctionRequest struct {
+	auction model.AuctionInput
+}
+
+type CreateAuctionResponse struct {
+	Uuid string `json:"uuid"`
+}
+
+func MakeEndpointCreateAuction(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(CreateAuctionRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointCreateAuction failed cast request")
+		}
+
+		id, err := s.CreateAuction(req.auction)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointCreateAuction: %w", err)
+		}
+
+		return CreateAuctionResponse{
+			Uuid: id,
+		}, nil
+	}
+}
+
+type UpdateAuctionRequest struct {
+	auction model.AuctionInput
+}
+
+func MakeEndpointUpdateAuction(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(UpdateAuctionRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointUpdateAuction failed cast request")
+		}
+
+		if err = s.UpdateAuction(req.auction); err != nil {
+			return nil, fmt.Errorf("MakeEndpointUpdateAuction: %w", err)
+		}
+
+		return nil, nil
+	}
+}
+
+type CancelAuctionRequest struct {
+	Uuid      string
+	ActorUuid string
+}
+
+func MakeEndpointCancelAuction(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(CancelAuctionRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointCancelAuction failed cast request")
+		}
+
+		if err = s.CancelAuction(req.Uuid, req.ActorUuid); err != nil {
+			return nil, fmt.Errorf("MakeEndpointCancelAuction: %w", err)
+		}
+
+		return nil, nil
+	}
+}
+
+type PlaceBidRequest struct {
+	bid model.BidInput
+}
+
+func MakeEndpointPlaceBid(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(PlaceBidRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointPlaceBid failed cast request")
+		}
+
+		result, err := s.PlaceBid(req.bid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointPlaceBid: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type PlaceProxyBidRequest struct {
+	bid model.ProxyBidInput
+}
+
+func MakeEndpointPlaceProxyBid(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(PlaceProxyBidRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointPlaceProxyBid failed cast request")
+		}
+
+		result, err := s.PlaceProxyBid(req.bid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointPlaceProxyBid: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type BuyNowRequest struct {
+	buy model.AcceptInput
+}
+
+func MakeEndpointBuyNow(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(BuyNowRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointBuyNow failed cast request")
+		}
+
+		result, err := s.BuyNow(req.buy)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointBuyNow: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type AcceptAuctionRequest struct {
+	accept model.AcceptInput
+}
+
+func MakeEndpointAcceptAuction(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(AcceptAuctionRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointAcceptAuction failed cast request")
+		}
+
+		result, err := s.AcceptAuction(req.accept)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointAcceptAuction: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type RetractBidRequest struct {
+	retract model.RetractInput
+}
+
+func MakeEndpointRetractBid(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(RetractBidRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointRetractBid failed cast request")
+		}
+
+		result, err := s.RetractBid(req.retract)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointRetractBid: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type CancelBidsRequest struct {
+	cancel model.CancelBidsInput
+}
+
+func MakeEndpointCancelBids(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(CancelBidsRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointCancelBids failed cast request")
+		}
+
+		result, err := s.CancelBids(req.cancel)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointCancelBids: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ListBidsRequest struct {
+	input    model.BidListInput
+	currency money.Currency
+}
+
+type ListBidsResponse struct {
+	history model.BidHistory
+}
+
+func MakeEndpointListBids(s Service, rates money.RateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListBidsRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListBids failed cast request")
+		}
+
+		result, err := s.ListBids(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListBids: %w", err)
+		}
+
+		convertBids(rates, &result, req.currency)
+
+		return ListBidsResponse{
+			history: result,
+		}, nil
+	}
+}
+
+type CreateAuctionEventRequest struct {
+	event model.AuctionEventInput
+}
+
+func MakeEndpointCreateAuctionEvent(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(CreateAuctionEventRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointCreateAuctionEvent failed cast request")
+		}
+
+		id, err := s.CreateAuctionEvent(req.event)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointCreateAuctionEvent: %w", err)
+		}
+
+		return CreateAuctionResponse{
+			Uuid: id,
+		}, nil
+	}
+}
+
+type GetCatalogueRequest struct {
+	Uuid string
+}
+
+func MakeEndpointGetCatalogue(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetCatalogueRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointGetCatalogue failed cast request")
+		}
+
+		result, err := s.GetCatalogue(req.Uuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointGetCatalogue: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type RelistAuctionRequest struct {
+	relist model.RelistInput
+}
+
+func MakeEndpointRelistAuction(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(RelistAuctionRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointRelistAuction failed cast request")
+		}
+
+		id, err := s.RelistAuction(req.relist)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointRelistAuction: %w", err)
+		}
+
+		return CreateAuctionResponse{
+			Uuid: id,
+		}, nil
+	}
+}
+
+func MakeEndpointCloneAuction(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(RelistAuctionRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointCloneAuction failed cast request")
+		}
+
+		id, err := s.CloneAuction(req.relist)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointCloneAuction: %w", err)
+		}
+
+		return CreateAuctionResponse{
+			Uuid: id,
+		}, nil
+	}
+}
+
+type CreateIncrementTableRequest struct {
+	table model.IncrementTableInput
+}
+
+func MakeEndpointCreateIncrementTable(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(CreateIncrementTableRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointCreateIncrementTable failed cast request")
+		}
+
+		id, err := s.CreateIncrementTable(req.table)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointCreateIncrementTable: %w", err)
+		}
+
+		return CreateAuctionResponse{
+			Uuid: id,
+		}, nil
+	}
+}
+
+type GetIncrementTableRequest struct {
+	Uuid string
+}
+
+func MakeEndpointGetIncrementTable(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetIncrementTableRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointGetIncrementTable failed cast request")
+		}
+
+		result, err := s.GetIncrementTable(req.Uuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointGetIncrementTable: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type NextBidRequest struct {
+	AuctionUuid string
+}
+
+func MakeEndpointNextBid(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(NextBidRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointNextBid failed cast request")
+		}
+
+		result, err := s.NextBid(req.AuctionUuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointNextBid: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type SetCreditRequest struct {
+	credit bidermodel.CreditInput
+}
+
+func MakeEndpointSetCredit(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(SetCreditRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointSetCredit failed cast request")
+		}
+
+		result, err := s.SetCredit(req.credit)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointSetCredit: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type GetCreditRequest struct {
+	BidderUuid string
+	ActorUuid  string
+}
+
+func MakeEndpointGetCredit(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetCreditRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointGetCredit failed cast request")
+		}
+
+		result, err := s.GetCredit(req.BidderUuid, req.ActorUuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointGetCredit: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ListFlagsRequest struct {
+	input fraudmodel.FlagListInput
+}
+
+func MakeEndpointListFlags(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListFlagsRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListFlags failed cast request")
+		}
+
+		result, err := s.ListFlags(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListFlags: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ReviewFlagRequest struct {
+	review fraudmodel.ReviewInput
+}
+
+func MakeEndpointReviewFlag(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ReviewFlagRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointReviewFlag failed cast request")
+		}
+
+		result, err := s.ReviewFlag(req.review)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointReviewFlag: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type LeaveFeedbackRequest struct {
+	feedback model.FeedbackInput
+}
+
+func MakeEndpointLeaveFeedback(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(LeaveFeedbackRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointLeaveFeedback failed cast request")
+		}
+
+		result, err := s.LeaveFeedback(req.feedback)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointLeaveFeedback: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ListFeedbackRequest struct {
+	input model.FeedbackListInput
+}
+
+func MakeEndpointListFeedback(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListFeedbackRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListFeedback failed cast request")
+		}
+
+		result, err := s.ListFeedback(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListFeedback: %w", err)
+		}
+
+		return result, nil
+	}
+}
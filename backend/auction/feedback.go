@@ -0,0 +1,138 @@
+package auction
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+// LeaveFeedback - the buyer or the seller of a sold auction rates the other party, once per auction and within
+// the feedback window which starts at the sale. the buyer is the user the winning bidder bought for, so both
+// parties are rated as users. the new reputation of the rated user is published to the services keeping a copy of it
+func (s *ServiceAuction) LeaveFeedback(input model.FeedbackInput) (model.Feedback, error) {
+	if err := validateFeedback(input); err != nil {
+		return model.Feedback{}, err
+	}
+
+	auction, err := s.repo.Single(input.AuctionUuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.LeaveFeedback failed getting auction", zap.String("auction", input.AuctionUuid), zap.Error(err))
+		return model.Feedback{}, err
+	}
+
+	buyerUuid, err := s.buyer(auction)
+	if err != nil {
+		return model.Feedback{}, err
+	}
+
+	now := time.Now()
+	feedback, err := rate(auction, buyerUuid, input, time.Duration(s.config.Feedback.WindowSeconds)*time.Second, now)
+	if err != nil {
+		return model.Feedback{}, err
+	}
+
+	reputation, err := s.repo.CreateFeedback(feedback)
+	if err != nil {
+		s.logger.Error("ServiceAuction.LeaveFeedback failed creating feedback", zap.Any("input", input), zap.Error(err))
+		return model.Feedback{}, err
+	}
+
+	s.publish(model.Event{
+		Type:        model.EventFeedback,
+		AuctionUuid: auction.Uuid,
+		Status:      auction.Status,
+		ExpiredAt:   auction.ExpiredAt,
+		OccurredAt:  now,
+		Reputation:  &reputation,
+	})
+
+	return feedback, nil
+}
+
+// ListFeedback - a page of the feedback left on an auction or received by a user, newest first
+func (s *ServiceAuction) ListFeedback(input model.FeedbackListInput) (model.FeedbackList, error) {
+	if input.AuctionUuid == "" && input.SubjectUuid == "" {
+		return model.FeedbackList{}, fmt.Errorf("%w: an auction or a user is required", model.ErrInvalidInput)
+	}
+
+	result, err := s.repo.ListFeedback(input)
+	if err != nil {
+		s.logger.Error("ServiceAuction.ListFeedback failed listing feedback", zap.Any("input", input), zap.Error(err))
+		return model.FeedbackList{}, err
+	}
+
+	return result, nil
+}
+
+func validateFeedback(input model.FeedbackInput) error {
+	if input.AuctionUuid == "" || input.AuthorUuid == "" {
+		return fmt.Errorf("%w: auctionUuid and authorUuid are required", model.ErrInvalidInput)
+	}
+
+	if input.Rating < model.MinRating || input.Rating > model.MaxRating {
+		return fmt.Errorf("%w: rating must be between %d and %d", model.ErrInvalidInput, model.MinRating, model.MaxRating)
+	}
+
+	if utf8.RuneCountInString(input.Comment) > model.MaxCommentLength {
+		return fmt.Errorf("%w: comment is longer than %d characters", model.ErrInvalidInput, model.MaxCommentLength)
+	}
+
+	return nil
+}
+
+// buyer - the user the winning bidder of the sold auction bought for, a bidder which bids for no user
+// leaves the auction without a buyer to rate or to be rated by
+func (s *ServiceAuction) buyer(auction model.Auction) (string, error) {
+	if auction.Status != model.Sold || auction.WinnerUuid == "" {
+		return "", fmt.Errorf("%w: the auction was not sold", model.ErrFeedbackClosed)
+	}
+
+	bidder, err := s.bidders.FindOne(auction.WinnerUuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.buyer failed getting the winning bidder", zap.String("bidder", auction.WinnerUuid), zap.Error(err))
+		return "", err
+	}
+
+	if bidder.UserUuid == "" {
+		return "", fmt.Errorf("%w: the winning bidder bids for no user", model.ErrFeedbackClosed)
+	}
+
+	return bidder.UserUuid, nil
+}
+
+// rate - the feedback of the author on the other party of the sold auction, the seller or the buyer user
+func rate(auction model.Auction, buyerUuid string, input model.FeedbackInput, window time.Duration, now time.Time) (model.Feedback, error) {
+	if auction.Status != model.Sold || auction.SoldAt == nil {
+		return model.Feedback{}, fmt.Errorf("%w: the auction was not sold", model.ErrFeedbackClosed)
+	}
+
+	if now.After(auction.SoldAt.Add(window)) {
+		return model.Feedback{}, fmt.Errorf("%w: the feedback window ended", model.ErrFeedbackClosed)
+	}
+
+	feedback := model.Feedback{
+		Uuid:        uuid.New().String(),
+		AuctionUuid: auction.Uuid,
+		AuthorUuid:  input.AuthorUuid,
+		Rating:      input.Rating,
+		Comment:     strings.TrimSpace(input.Comment),
+		CreatedAt:   now,
+	}
+
+	switch input.AuthorUuid {
+	case buyerUuid:
+		feedback.SubjectUuid, feedback.SubjectRole = auction.UserUuid, model.Seller
+	case auction.UserUuid:
+		feedback.SubjectUuid, feedback.SubjectRole = buyerUuid, model.Buyer
+	default:
+		return model.Feedback{}, model.ErrForbidden
+	}
+
+	return feedback, nil
+}
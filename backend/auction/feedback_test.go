@@ -0,0 +1,118 @@
+package auction
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+// soldAuction - the auction was updated after the sale, the feedback window still starts at the sale
+func soldAuction(soldAt time.Time) model.Auction {
+	return model.Auction{
+		Uuid:       "auction-uuid",
+		UserUuid:   "seller-uuid",
+		WinnerUuid: "winner-bidder",
+		Status:     model.Sold,
+		SoldAt:     &soldAt,
+		UpdatedAt:  time.Now(),
+	}
+}
+
+func buyerBidders() *MockBidders {
+	bidders := &MockBidders{}
+	bidders.mock.On("FindOne", "winner-bidder").Return(bidermodel.Bidder{Uuid: "winner-bidder", UserUuid: "buyer-uuid"}, nil)
+	bidders.mock.On("FindOne", "legacy-bidder").Return(bidermodel.Bidder{Uuid: "legacy-bidder"}, nil)
+
+	return bidders
+}
+
+func TestServiceAuction_LeaveFeedback(t *testing.T) {
+	tests := []struct {
+		name        string
+		author      string
+		wantSubject string
+		wantRole    model.Role
+	}{
+		{"buyer rates the seller", "buyer-uuid", "seller-uuid", model.Seller},
+		{"seller rates the buyer", "seller-uuid", "buyer-uuid", model.Buyer},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reputation := model.Reputation{UserUuid: test.wantSubject}
+			reputation.Add(test.wantRole, 4)
+
+			repo := &MockRepository{}
+			repo.mock.On("Single", "auction-uuid").Return(soldAuction(time.Now().Add(-time.Hour)), nil)
+			repo.mock.On("CreateFeedback", mock.Anything).Return(reputation, nil)
+			pub := acceptingPublisher()
+			service := New(repo, pub, buyerBidders(), Config{Feedback: FeedbackConfig{WindowSeconds: 3600 * 24}}, zap.NewNop())
+
+			result, err := service.LeaveFeedback(model.FeedbackInput{AuctionUuid: "auction-uuid", AuthorUuid: test.author, Rating: 4, Comment: " fast shipping "})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantSubject, result.SubjectUuid)
+			assert.Equal(t, test.wantRole, result.SubjectRole)
+			assert.Equal(t, "fast shipping", result.Comment)
+
+			var event model.Event
+			assert.NoError(t, json.Unmarshal(pub.mock.Calls[0].Arguments.Get(0).([]byte), &event))
+			assert.Equal(t, model.EventFeedback, event.Type)
+			assert.Equal(t, test.wantSubject, event.Reputation.UserUuid)
+			assert.Equal(t, 4.0, event.Reputation.Score)
+		})
+	}
+}
+
+func TestServiceAuction_LeaveFeedbackRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		auction model.Auction
+		input   model.FeedbackInput
+		wantErr error
+	}{
+		{"rating too high", soldAuction(time.Now()), model.FeedbackInput{AuthorUuid: "buyer-uuid", Rating: 6}, model.ErrInvalidInput},
+		{"rating too low", soldAuction(time.Now()), model.FeedbackInput{AuthorUuid: "buyer-uuid", Rating: 0}, model.ErrInvalidInput},
+		{"comment too long", soldAuction(time.Now()), model.FeedbackInput{AuthorUuid: "buyer-uuid", Rating: 3, Comment: strings.Repeat("a", 1001)}, model.ErrInvalidInput},
+		{"not a party", soldAuction(time.Now()), model.FeedbackInput{AuthorUuid: "bidder-uuid", Rating: 3}, model.ErrForbidden},
+		{"the winning bidder is not the buyer user", soldAuction(time.Now()), model.FeedbackInput{AuthorUuid: "winner-bidder", Rating: 3}, model.ErrForbidden},
+		{"window ended", soldAuction(time.Now().Add(-25 * time.Hour)), model.FeedbackInput{AuthorUuid: "buyer-uuid", Rating: 3}, model.ErrFeedbackClosed},
+		{"the winning bidder bids for no user", model.Auction{Uuid: "auction-uuid", UserUuid: "seller-uuid", WinnerUuid: "legacy-bidder", Status: model.Sold, SoldAt: soldAuction(time.Now()).SoldAt}, model.FeedbackInput{AuthorUuid: "seller-uuid", Rating: 3}, model.ErrFeedbackClosed},
+		{"not sold", model.Auction{Uuid: "auction-uuid", UserUuid: "seller-uuid", Status: model.Expired}, model.FeedbackInput{AuthorUuid: "seller-uuid", Rating: 3}, model.ErrFeedbackClosed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Single", "auction-uuid").Return(test.auction, nil)
+			service := New(repo, acceptingPublisher(), buyerBidders(), Config{Feedback: FeedbackConfig{WindowSeconds: 3600 * 24}}, zap.NewNop())
+			test.input.AuctionUuid = "auction-uuid"
+
+			_, err := service.LeaveFeedback(test.input)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			repo.mock.AssertNotCalled(t, "CreateFeedback", mock.Anything)
+		})
+	}
+}
+
+func TestReputation_Add(t *testing.T) {
+	var reputation model.Reputation
+
+	reputation.Add(model.Seller, 5)
+	reputation.Add(model.Seller, 4)
+	reputation.Add(model.Buyer, 2)
+
+	assert.Equal(t, int64(3), reputation.Count)
+	assert.Equal(t, 3.67, reputation.Score)
+	assert.Equal(t, 4.5, reputation.SellerScore)
+	assert.Equal(t, 2.0, reputation.BuyerScore)
+}
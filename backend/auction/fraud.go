@@ -0,0 +1,222 @@
+package auction
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/fraud"
+	fraudmodel "github.com/ireuven89/hello-world/backend/fraud/model"
+)
+
+type FraudRepository interface {
+	ActiveSellers(since time.Time) ([]string, error)
+	BidRecords(since time.Time, sellers []string, bidders []string) ([]fraudmodel.BidRecord, error)
+	SaveFindings(findings []fraudmodel.Finding) error
+}
+
+// FraudBidders - finds the users the bidders of the bid history bid for
+type FraudBidders interface {
+	FindOne(uuid string) (bidermodel.Bidder, error)
+	List(input bidermodel.BiddersInput) ([]bidermodel.Bidder, error)
+}
+
+// FraudScanner - analyzes the recent bid history for shill bidding and collusion and flags what it finds for review.
+// scanned is when the last scan started, a scan analyzes the sellers active since then only
+type FraudScanner struct {
+	repo     FraudRepository
+	bidders  FraudBidders
+	analyzer *fraud.Analyzer
+	interval time.Duration
+	lookback time.Duration
+	scanned  time.Time
+	logger   *zap.Logger
+}
+
+func NewFraudScanner(repo FraudRepository, bidders FraudBidders, config FraudConfig, logger *zap.Logger) *FraudScanner {
+
+	return &FraudScanner{
+		repo:     repo,
+		bidders:  bidders,
+		analyzer: fraud.New(config.Config),
+		interval: time.Duration(config.IntervalSeconds) * time.Second,
+		lookback: time.Duration(config.LookbackHours) * time.Hour,
+		logger:   logger,
+	}
+}
+
+// Run - scans the bid history on every tick until stop is closed
+func (s *FraudScanner) Run(stop chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("fraud scanner started", zap.Duration("interval", s.interval))
+
+	for {
+		select {
+		case <-stop:
+			s.logger.Info("fraud scanner stopped")
+			return
+		case now := <-ticker.C:
+			if err := s.Scan(now); err != nil {
+				s.logger.Error("FraudScanner.Run failed scanning bids", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Scan - analyzes the bids of the lookback window of the sellers whose auctions got a bid or changed since the
+// last scan and saves the findings as flags, the first scan takes every seller active in the lookback window.
+// the bids of the sellers are the bids of their bidders, every bid is told the user its bidder bids for.
+// a failed scan is retried from the same time on the next tick
+func (s *FraudScanner) Scan(now time.Time) error {
+	since := now.Add(-s.lookback)
+	if s.scanned.After(since) {
+		since = s.scanned
+	}
+
+	sellers, err := s.repo.ActiveSellers(since)
+	if err != nil {
+		return err
+	}
+
+	if len(sellers) == 0 {
+		s.scanned = now
+		return nil
+	}
+
+	users, sellerBidders, err := s.sellerBidders(sellers)
+	if err != nil {
+		return err
+	}
+
+	bids, err := s.repo.BidRecords(now.Add(-s.lookback), sellers, sellerBidders)
+	if err != nil {
+		return err
+	}
+
+	if err = s.bidderUsers(bids, users); err != nil {
+		return err
+	}
+
+	findings := s.analyzer.Analyze(bids)
+	if len(findings) > 0 {
+		s.logger.Info("fraud scanner found suspicious activity", zap.Int("bids", len(bids)), zap.Int("findings", len(findings)))
+
+		if err = s.repo.SaveFindings(findings); err != nil {
+			return err
+		}
+	}
+	s.scanned = now
+
+	return nil
+}
+
+// sellerBidders - the bidders of the sellers, and the user every bidder bids for by bidder
+func (s *FraudScanner) sellerBidders(sellers []string) (map[string]string, []string, error) {
+	users := make(map[string]string)
+	var bidders []string
+
+	input := bidermodel.BiddersInput{UserUuids: sellers}
+	for {
+		page, err := s.bidders.List(input)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, bidder := range page {
+			users[bidder.Uuid] = bidder.UserUuid
+			bidders = append(bidders, bidder.Uuid)
+		}
+
+		if int64(len(page)) < input.Page.GetLimit() {
+			return users, bidders, nil
+		}
+		input.Page.Offset += input.Page.GetLimit()
+	}
+}
+
+// bidderUsers - tells every bid the user its bidder bids for, users holds the bidders already found.
+// a bidder which is gone bids for no user
+func (s *FraudScanner) bidderUsers(bids []fraudmodel.BidRecord, users map[string]string) error {
+	for i := range bids {
+		userUuid, ok := users[bids[i].BidderUuid]
+		if !ok {
+			bidder, err := s.bidders.FindOne(bids[i].BidderUuid)
+			if err != nil && !errors.Is(err, bidermodel.ErrNotFound) {
+				return err
+			}
+
+			userUuid = bidder.UserUuid
+			users[bids[i].BidderUuid] = userUuid
+		}
+
+		bids[i].BidderUserUuid = userUuid
+	}
+
+	return nil
+}
+
+// ListFlags - an admin lists the fraud flags, newest first
+func (s *ServiceAuction) ListFlags(input fraudmodel.FlagListInput) (fraudmodel.FlagList, error) {
+	if !s.isAdmin(input.ActorUuid) {
+		return fraudmodel.FlagList{}, model.ErrForbidden
+	}
+
+	if input.Status != "" && !input.Status.Valid() {
+		return fraudmodel.FlagList{}, fmt.Errorf("%w: unknown status %s", fraudmodel.ErrInvalidInput, input.Status)
+	}
+
+	result, err := s.repo.ListFlags(input)
+	if err != nil {
+		s.logger.Error("ServiceAuction.ListFlags failed listing flags", zap.Any("input", input), zap.Error(err))
+		return fraudmodel.FlagList{}, err
+	}
+
+	return result, nil
+}
+
+// ReviewFlag - an admin confirms or dismisses a flag, a dismissed flag is not raised again by the scanner
+func (s *ServiceAuction) ReviewFlag(input fraudmodel.ReviewInput) (fraudmodel.Flag, error) {
+	if input.FlagUuid == "" {
+		return fraudmodel.Flag{}, fmt.Errorf("%w: flagUuid is required", fraudmodel.ErrInvalidInput)
+	}
+
+	if !s.isAdmin(input.ActorUuid) {
+		return fraudmodel.Flag{}, model.ErrForbidden
+	}
+
+	if input.Status != fraudmodel.Confirmed && input.Status != fraudmodel.Dismissed {
+		return fraudmodel.Flag{}, fmt.Errorf("%w: status must be %s or %s", fraudmodel.ErrInvalidInput, fraudmodel.Confirmed, fraudmodel.Dismissed)
+	}
+
+	result, err := s.repo.ReviewFlag(input)
+	if err != nil {
+		s.logger.Error("ServiceAuction.ReviewFlag failed reviewing flag", zap.Any("input", input), zap.Error(err))
+		return fraudmodel.Flag{}, err
+	}
+
+	return result, nil
+}
+
+// checkFlagged - refuses the bids of a bidder with a confirmed flag when the service blocks flagged bidders
+func (s *ServiceAuction) checkFlagged(bidderUuid string) error {
+	if !s.config.Fraud.BlockFlagged {
+		return nil
+	}
+
+	flagged, err := s.repo.Flagged(bidderUuid)
+	if err != nil {
+		return err
+	}
+
+	if flagged {
+		return fmt.Errorf("%w: the bidder is flagged for review", model.ErrForbidden)
+	}
+
+	return nil
+}
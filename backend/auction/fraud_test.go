@@ -0,0 +1,212 @@
+package auction
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/fraud"
+	fraudmodel "github.com/ireuven89/hello-world/backend/fraud/model"
+)
+
+type MockFraudRepository struct {
+	mock mock.Mock
+}
+
+func (m *MockFraudRepository) ActiveSellers(since time.Time) ([]string, error) {
+	args := m.mock.Called(since)
+
+	sellers, _ := args.Get(0).([]string)
+
+	return sellers, args.Error(1)
+}
+
+func (m *MockFraudRepository) BidRecords(since time.Time, sellers []string, bidders []string) ([]fraudmodel.BidRecord, error) {
+	args := m.mock.Called(since, sellers, bidders)
+
+	records, _ := args.Get(0).([]fraudmodel.BidRecord)
+
+	return records, args.Error(1)
+}
+
+func (m *MockFraudRepository) SaveFindings(findings []fraudmodel.Finding) error {
+	args := m.mock.Called(findings)
+
+	return args.Error(0)
+}
+
+// stubFraudBidders - the user each known bidder bids for, other bidders are not found
+type stubFraudBidders map[string]string
+
+func (b stubFraudBidders) FindOne(uuid string) (bidermodel.Bidder, error) {
+	userUuid, ok := b[uuid]
+	if !ok {
+		return bidermodel.Bidder{}, bidermodel.ErrNotFound
+	}
+
+	return bidermodel.Bidder{Uuid: uuid, UserUuid: userUuid}, nil
+}
+
+func (b stubFraudBidders) List(input bidermodel.BiddersInput) ([]bidermodel.Bidder, error) {
+	var result []bidermodel.Bidder
+	for uuid, userUuid := range b {
+		if slices.Contains(input.UserUuids, userUuid) {
+			result = append(result, bidermodel.Bidder{Uuid: uuid, UserUuid: userUuid})
+		}
+	}
+	slices.SortFunc(result, func(a, b bidermodel.Bidder) int {
+		return strings.Compare(a.Uuid, b.Uuid)
+	})
+
+	return result, nil
+}
+
+func TestFraudScanner_Scan(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	records := []fraudmodel.BidRecord{
+		{AuctionUuid: "auction-a", SellerUuid: "seller-uuid", BidderUuid: "shill-uuid", IP: "10.0.0.2", CreatedAt: now.Add(-2 * time.Hour)},
+		{AuctionUuid: "auction-b", SellerUuid: "other-seller", BidderUuid: "seller-bidder", IP: "10.0.0.1", CreatedAt: now.Add(-time.Hour)},
+		{AuctionUuid: "auction-b", SellerUuid: "other-seller", BidderUuid: "shill-uuid", IP: "10.0.0.1", CreatedAt: now.Add(-time.Hour)},
+		{AuctionUuid: "auction-a", SellerUuid: "seller-uuid", BidderUuid: "shill-uuid", IP: "10.0.0.1", CreatedAt: now.Add(-time.Minute)},
+		{AuctionUuid: "auction-a", SellerUuid: "seller-uuid", BidderUuid: "seller-uuid", IP: "10.0.0.3", CreatedAt: now.Add(-time.Minute)},
+	}
+	bidders := stubFraudBidders{"seller-bidder": "seller-uuid", "shill-uuid": "shill-user", "seller-uuid": "someone-else"}
+
+	repo := &MockFraudRepository{}
+	repo.mock.On("ActiveSellers", now.Add(-24*time.Hour)).Return([]string{"seller-uuid"}, nil)
+	repo.mock.On("BidRecords", now.Add(-24*time.Hour), []string{"seller-uuid"}, []string{"seller-bidder"}).Return(records, nil)
+	repo.mock.On("SaveFindings", mock.Anything).Return(nil)
+	scanner := NewFraudScanner(repo, bidders, FraudConfig{Config: fraud.Config{}, IntervalSeconds: 60, LookbackHours: 24}, zap.NewNop())
+
+	err := scanner.Scan(now)
+
+	assert.NoError(t, err)
+	findings := repo.mock.Calls[2].Arguments.Get(0).([]fraudmodel.Finding)
+	assert.Len(t, findings, 2)
+	assert.Equal(t, fraudmodel.BidderSubject, findings[0].Subject)
+	assert.Equal(t, "shill-uuid", findings[0].SubjectUuid)
+	assert.Equal(t, fraudmodel.AuctionSubject, findings[1].Subject)
+	assert.Equal(t, "auction-a", findings[1].SubjectUuid)
+}
+
+func TestFraudScanner_ScanSinceLastScan(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(15 * time.Minute)
+
+	repo := &MockFraudRepository{}
+	repo.mock.On("ActiveSellers", now.Add(-24*time.Hour)).Return([]string{"seller-uuid"}, nil).Once()
+	repo.mock.On("BidRecords", now.Add(-24*time.Hour), []string{"seller-uuid"}, []string(nil)).Return(nil, errors.New("db error")).Once()
+	repo.mock.On("ActiveSellers", later.Add(-24*time.Hour)).Return(nil, nil).Once()
+	repo.mock.On("ActiveSellers", later).Return(nil, nil).Once()
+	scanner := NewFraudScanner(repo, stubFraudBidders{}, FraudConfig{IntervalSeconds: 60, LookbackHours: 24}, zap.NewNop())
+
+	assert.Error(t, scanner.Scan(now))
+	assert.NoError(t, scanner.Scan(later))
+	assert.NoError(t, scanner.Scan(later.Add(15*time.Minute)))
+
+	repo.mock.AssertExpectations(t)
+	repo.mock.AssertNumberOfCalls(t, "BidRecords", 1)
+}
+
+func TestFraudScanner_ScanWithoutFindings(t *testing.T) {
+	repo := &MockFraudRepository{}
+	repo.mock.On("ActiveSellers", mock.Anything).Return([]string{"seller-uuid"}, nil)
+	repo.mock.On("BidRecords", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	scanner := NewFraudScanner(repo, stubFraudBidders{}, FraudConfig{IntervalSeconds: 60, LookbackHours: 24}, zap.NewNop())
+
+	err := scanner.Scan(time.Now())
+
+	assert.NoError(t, err)
+	repo.mock.AssertNotCalled(t, "SaveFindings", mock.Anything)
+}
+
+func TestServiceAuction_ListFlagsForbidden(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, acceptingPublisher(), userBidders{}, Config{Retraction: RetractionConfig{AdminUuids: []string{"admin-uuid"}}}, zap.NewNop())
+
+	_, err := service.ListFlags(fraudmodel.FlagListInput{ActorUuid: "bidder-a"})
+
+	assert.ErrorIs(t, err, model.ErrForbidden)
+	repo.mock.AssertNotCalled(t, "ListFlags", mock.Anything)
+}
+
+func TestServiceAuction_ReviewFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   fraudmodel.ReviewInput
+		wantErr error
+	}{
+		{"confirmed", fraudmodel.ReviewInput{FlagUuid: "flag-uuid", ActorUuid: "admin-uuid", Status: fraudmodel.Confirmed}, nil},
+		{"dismissed", fraudmodel.ReviewInput{FlagUuid: "flag-uuid", ActorUuid: "admin-uuid", Status: fraudmodel.Dismissed}, nil},
+		{"reopened", fraudmodel.ReviewInput{FlagUuid: "flag-uuid", ActorUuid: "admin-uuid", Status: fraudmodel.Open}, fraudmodel.ErrInvalidInput},
+		{"not an admin", fraudmodel.ReviewInput{FlagUuid: "flag-uuid", ActorUuid: "bidder-a", Status: fraudmodel.Dismissed}, model.ErrForbidden},
+		{"no flag", fraudmodel.ReviewInput{ActorUuid: "admin-uuid", Status: fraudmodel.Dismissed}, fraudmodel.ErrInvalidInput},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("ReviewFlag", test.input).Return(fraudmodel.Flag{Uuid: "flag-uuid", Status: test.input.Status}, nil)
+			service := New(repo, acceptingPublisher(), userBidders{}, Config{Retraction: RetractionConfig{AdminUuids: []string{"admin-uuid"}}}, zap.NewNop())
+
+			result, err := service.ReviewFlag(test.input)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				repo.mock.AssertNotCalled(t, "ReviewFlag", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.input.Status, result.Status)
+		})
+	}
+}
+
+func TestServiceAuction_PlaceBidOfFlaggedBidder(t *testing.T) {
+	auction := model.Auction{
+		Uuid:      "auction-uuid",
+		UserUuid:  "seller-uuid",
+		Price:     usd(100),
+		ExpiredAt: time.Now().Add(time.Hour),
+		Status:    model.InProgress,
+	}
+
+	tests := []struct {
+		name    string
+		block   bool
+		flagged bool
+		wantErr error
+	}{
+		{"blocked", true, true, model.ErrForbidden},
+		{"not flagged", true, false, nil},
+		{"blocking disabled", false, true, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Flagged", "bidder-a").Return(test.flagged, nil)
+			repo.mock.On("PlaceBid", "auction-uuid").Return(auction, nil, nil)
+			config := Config{Bidding: BiddingConfig{MinIncrement: 5}, Fraud: FraudConfig{BlockFlagged: test.block}}
+			service := New(repo, acceptingPublisher(), userBidders{}, config, zap.NewNop())
+
+			_, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 150, IP: "10.0.0.1"})
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				repo.mock.AssertNotCalled(t, "PlaceBid", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "10.0.0.1", repo.placements[0].Bids[0].IP)
+		})
+	}
+}
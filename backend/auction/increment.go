@@ -0,0 +1,150 @@
+package auction
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+// CreateIncrementTable - stores an increment table for the auctions of its category or for the auctions pointing at it
+func (s *ServiceAuction) CreateIncrementTable(input model.IncrementTableInput) (string, error) {
+	if input.Name == "" {
+		return "", fmt.Errorf("%w: name is required", model.ErrInvalidInput)
+	}
+
+	if !s.isAdmin(input.ActorUuid) {
+		return "", model.ErrForbidden
+	}
+
+	if err := validateBands(input.Bands); err != nil {
+		return "", err
+	}
+
+	id, err := s.repo.CreateIncrementTable(input)
+	if err != nil {
+		s.logger.Error("ServiceAuction.CreateIncrementTable failed creating increment table", zap.Any("input", input), zap.Error(err))
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (s *ServiceAuction) GetIncrementTable(uuid string) (model.IncrementTable, error) {
+	result, err := s.repo.IncrementTable(uuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.GetIncrementTable failed getting increment table", zap.String("uuid", uuid), zap.Error(err))
+		return model.IncrementTable{}, err
+	}
+
+	return result, nil
+}
+
+// NextBid - the lowest bid the auction accepts right now, dutch auctions are taken at their current price
+// and sealed auctions accept any bid from the opening price
+func (s *ServiceAuction) NextBid(auctionUuid string) (model.NextBid, error) {
+	auction, err := s.repo.Single(auctionUuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.NextBid failed getting auction", zap.String("auction", auctionUuid), zap.Error(err))
+		return model.NextBid{}, err
+	}
+
+	if auction.Status != model.InProgress {
+		return model.NextBid{}, model.ErrNotInProgress
+	}
+
+	increments, err := s.increments(auction)
+	if err != nil {
+		return model.NextBid{}, err
+	}
+
+	result := model.NextBid{
+		AuctionUuid:  auction.Uuid,
+		CurrentPrice: auction.PriceAt(time.Now()),
+	}
+
+	switch {
+	case auction.Type == model.Dutch:
+		result.MinimumBid = result.CurrentPrice
+	case auction.Type.Sealed():
+		result.MinimumBid = auction.Price
+	default:
+		result.Increment = auction.Money(increments.At(auction.WinningPrice.Amount))
+		result.MinimumBid = auction.Money(minimumBid(auction, increments.At))
+	}
+
+	return result, nil
+}
+
+// increments - the increment table of the auction, its own table first, then the table of its category
+// and last the default table of the service
+func (s *ServiceAuction) increments(auction model.Auction) (model.IncrementTable, error) {
+	if auction.IncrementTableUuid != "" {
+		table, err := s.repo.IncrementTable(auction.IncrementTableUuid)
+		if err != nil {
+			s.logger.Error("ServiceAuction.increments failed getting increment table", zap.String("auction", auction.Uuid), zap.Error(err))
+			return model.IncrementTable{}, err
+		}
+
+		return table, nil
+	}
+
+	if auction.Category != "" {
+		table, err := s.repo.CategoryIncrementTable(auction.Category)
+		if err == nil {
+			return table, nil
+		}
+
+		if !errors.Is(err, model.ErrNotFound) {
+			s.logger.Error("ServiceAuction.increments failed getting category increment table", zap.String("auction", auction.Uuid), zap.Error(err))
+			return model.IncrementTable{}, err
+		}
+	}
+
+	return s.config.Bidding.DefaultIncrements(), nil
+}
+
+// checkIncrementTable - an auction may only point at an existing increment table
+func (s *ServiceAuction) checkIncrementTable(uuid string) error {
+	if uuid == "" {
+		return nil
+	}
+
+	if _, err := s.repo.IncrementTable(uuid); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("%w: unknown increment table %s", model.ErrInvalidInput, uuid)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// validateBands - every band raises by a positive increment, the bounds go up and only the last band is unbounded
+func validateBands(bands []model.IncrementBand) error {
+	if len(bands) == 0 {
+		return fmt.Errorf("%w: an increment table needs at least one band", model.ErrInvalidInput)
+	}
+
+	var previous int64
+	for i, band := range bands {
+		if band.Increment <= 0 {
+			return fmt.Errorf("%w: band %d must have a positive increment", model.ErrInvalidInput, i+1)
+		}
+
+		last := i == len(bands)-1
+		if last && band.Below != 0 {
+			return fmt.Errorf("%w: the last band must be unbounded", model.ErrInvalidInput)
+		}
+
+		if !last && band.Below <= previous {
+			return fmt.Errorf("%w: band %d must end above the previous band", model.ErrInvalidInput, i+1)
+		}
+		previous = band.Below
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package auction
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+func bandedTable() model.IncrementTable {
+	return model.IncrementTable{
+		Uuid: "table-uuid",
+		Bands: []model.IncrementBand{
+			{Below: 50, Increment: 1},
+			{Below: 500, Increment: 5},
+			{Increment: 25},
+		},
+	}
+}
+
+func TestIncrementTable_At(t *testing.T) {
+	table := bandedTable()
+
+	assert.Equal(t, int64(1), table.At(0))
+	assert.Equal(t, int64(1), table.At(49))
+	assert.Equal(t, int64(5), table.At(50))
+	assert.Equal(t, int64(5), table.At(499))
+	assert.Equal(t, int64(25), table.At(500))
+	assert.Equal(t, int64(25), table.At(100000))
+}
+
+func TestValidateBands(t *testing.T) {
+	tests := []struct {
+		name    string
+		bands   []model.IncrementBand
+		wantErr bool
+	}{
+		{name: "banded", bands: bandedTable().Bands},
+		{name: "single unbounded band", bands: []model.IncrementBand{{Increment: 1}}},
+		{name: "no bands", wantErr: true},
+		{name: "zero increment", bands: []model.IncrementBand{{Below: 50, Increment: 0}, {Increment: 5}}, wantErr: true},
+		{name: "bounded last band", bands: []model.IncrementBand{{Below: 50, Increment: 1}}, wantErr: true},
+		{name: "descending bounds", bands: []model.IncrementBand{{Below: 500, Increment: 1}, {Below: 50, Increment: 5}, {Increment: 10}}, wantErr: true},
+		{name: "unbounded middle band", bands: []model.IncrementBand{{Increment: 1}, {Increment: 5}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBands(tt.bands)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, model.ErrInvalidInput)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceAuction_PlaceBidUsesCategoryIncrements(t *testing.T) {
+	auction := model.Auction{
+		Uuid:         "auction-uuid",
+		UserUuid:     "seller-uuid",
+		Price:        usd(10),
+		WinnerUuid:   "bidder-b",
+		WinningPrice: usd(60),
+		ExpiredAt:    time.Now().Add(time.Hour),
+		Status:       model.InProgress,
+		Category:     "art",
+	}
+
+	t.Run("below the band increment", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("PlaceBid", "auction-uuid").Return(auction, nil, nil)
+		repo.mock.On("CategoryIncrementTable", "art").Return(bandedTable(), nil)
+		service := New(repo, acceptingPublisher(), userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 1}}, zap.NewNop())
+
+		_, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 64})
+
+		assert.ErrorIs(t, err, model.ErrBidTooLow)
+	})
+
+	t.Run("at the band increment", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("PlaceBid", "auction-uuid").Return(auction, nil, nil)
+		repo.mock.On("CategoryIncrementTable", "art").Return(bandedTable(), nil)
+		service := New(repo, acceptingPublisher(), userBidders{}, Config{Bidding: BiddingConfig{MinIncrement: 1}}, zap.NewNop())
+
+		result, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 65})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(65), result.WinningPrice.Amount)
+	})
+}
+
+func TestServiceAuction_IncrementsFallback(t *testing.T) {
+	config := Config{Bidding: BiddingConfig{Increments: []model.IncrementBand{{Increment: 7}}}}
+
+	t.Run("auction table first", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("IncrementTable", "table-uuid").Return(bandedTable(), nil)
+		service := &ServiceAuction{repo: repo, config: config, logger: zap.NewNop()}
+
+		table, err := service.increments(model.Auction{IncrementTableUuid: "table-uuid", Category: "art"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "table-uuid", table.Uuid)
+		repo.mock.AssertNotCalled(t, "CategoryIncrementTable", mock.Anything)
+	})
+
+	t.Run("category without a table uses the default", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("CategoryIncrementTable", "art").Return(model.IncrementTable{}, model.ErrNotFound)
+		service := &ServiceAuction{repo: repo, config: config, logger: zap.NewNop()}
+
+		table, err := service.increments(model.Auction{Category: "art"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), table.At(1000))
+	})
+
+	t.Run("category lookup fails", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("CategoryIncrementTable", "art").Return(model.IncrementTable{}, errors.New("db is down"))
+		service := &ServiceAuction{repo: repo, config: config, logger: zap.NewNop()}
+
+		_, err := service.increments(model.Auction{Category: "art"})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestServiceAuction_NextBid(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		auction model.Auction
+		want    model.NextBid
+	}{
+		{
+			name:    "no bids yet",
+			auction: model.Auction{Uuid: "auction-uuid", Price: usd(40), Status: model.InProgress},
+			want:    model.NextBid{AuctionUuid: "auction-uuid", CurrentPrice: usd(40), MinimumBid: usd(40), Increment: usd(1)},
+		},
+		{
+			name:    "high bid in the second band",
+			auction: model.Auction{Uuid: "auction-uuid", Price: usd(40), WinnerUuid: "bidder-a", WinningPrice: usd(120), Status: model.InProgress},
+			want:    model.NextBid{AuctionUuid: "auction-uuid", CurrentPrice: usd(120), MinimumBid: usd(125), Increment: usd(5)},
+		},
+		{
+			name: "dutch auction",
+			auction: model.Auction{Uuid: "auction-uuid", Type: model.Dutch, Price: usd(100), FloorPrice: usd(10), PriceStep: usd(10),
+				StepIntervalSeconds: 60, CreatedAt: now.Add(-2 * time.Minute), Status: model.InProgress},
+			want: model.NextBid{AuctionUuid: "auction-uuid", CurrentPrice: usd(80), MinimumBid: usd(80)},
+		},
+		{
+			name:    "sealed auction",
+			auction: model.Auction{Uuid: "auction-uuid", Type: model.SealedFirstPrice, Price: usd(40), Status: model.InProgress},
+			want:    model.NextBid{AuctionUuid: "auction-uuid", CurrentPrice: usd(40), MinimumBid: usd(40)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Single", "auction-uuid").Return(tt.auction, nil)
+			service := New(repo, nil, userBidders{}, Config{Bidding: BiddingConfig{Increments: bandedTable().Bands}}, zap.NewNop())
+
+			result, err := service.NextBid("auction-uuid")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestServiceAuction_NextBidClosedAuction(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Single", "auction-uuid").Return(model.Auction{Uuid: "auction-uuid", Status: model.Sold}, nil)
+	service := New(repo, nil, userBidders{}, Config{}, zap.NewNop())
+
+	_, err := service.NextBid("auction-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotInProgress)
+}
+
+func TestServiceAuction_CreateAuctionUnknownIncrementTable(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("IncrementTable", "missing-uuid").Return(model.IncrementTable{}, model.ErrNotFound)
+	service := New(repo, nil, userBidders{}, Config{}, zap.NewNop())
+
+	_, err := service.CreateAuction(model.AuctionInput{
+		Item:               "clock",
+		UserUuid:           "seller-uuid",
+		Price:              usd(100),
+		ExpiredAt:          time.Now().Add(time.Hour),
+		IncrementTableUuid: "missing-uuid",
+	})
+
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	repo.mock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestServiceAuction_CreateIncrementTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   string
+		wantErr error
+	}{
+		{"an admin", "admin-uuid", nil},
+		{"not an admin", "user-uuid", model.ErrForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("CreateIncrementTable", mock.Anything).Return("table-uuid", nil)
+			service := New(repo, acceptingPublisher(), userBidders{}, Config{Retraction: RetractionConfig{AdminUuids: []string{"admin-uuid"}}}, zap.NewNop())
+
+			_, err := service.CreateIncrementTable(model.IncrementTableInput{Name: "coins", Bands: bandedTable().Bands, ActorUuid: test.actor})
+
+			assert.ErrorIs(t, err, test.wantErr)
+			if test.wantErr != nil {
+				repo.mock.AssertNotCalled(t, "CreateIncrementTable", mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package auction
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// maxLots - the largest number of lots a single auction event may hold
+const maxLots = 500
+
+// CreateAuctionEvent - creates an auction event with an auction for every lot, lot n closes
+// (n-1)*StaggerSeconds after the first lot
+func (s *ServiceAuction) CreateAuctionEvent(input model.AuctionEventInput) (string, error) {
+	if input.Currency == "" {
+		input.Currency = money.Default
+	}
+
+	if err := validateEvent(input); err != nil {
+		return "", err
+	}
+
+	lots := make([]model.AuctionInput, 0, len(input.Lots))
+	for i, lot := range input.Lots {
+		auction := lotAuction(input, lot, int64(i+1))
+
+		if err := validateCreate(auction); err != nil {
+			return "", fmt.Errorf("lot %d: %w", auction.LotNumber, err)
+		}
+
+		lots = append(lots, auction)
+	}
+
+	id, err := s.repo.CreateEvent(input, lots)
+	if err != nil {
+		s.logger.Error("ServiceAuction.CreateAuctionEvent failed creating auction event", zap.Any("input", input), zap.Error(err))
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetCatalogue - returns the auction event with all of its lots
+func (s *ServiceAuction) GetCatalogue(uuid string) (model.Catalogue, error) {
+	result, err := s.repo.Catalogue(uuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.GetCatalogue failed getting catalogue", zap.String("uuid", uuid), zap.Error(err))
+		return model.Catalogue{}, err
+	}
+
+	now := time.Now()
+	for i := range result.Lots {
+		present(&result.Lots[i], now)
+	}
+
+	return result, nil
+}
+
+func validateEvent(input model.AuctionEventInput) error {
+	if input.Title == "" || input.UserUuid == "" {
+		return fmt.Errorf("%w: title and userUuid are required", model.ErrInvalidInput)
+	}
+
+	if len(input.Lots) == 0 || len(input.Lots) > maxLots {
+		return fmt.Errorf("%w: an auction event holds between 1 and %d lots", model.ErrInvalidInput, maxLots)
+	}
+
+	if input.StaggerSeconds < 0 {
+		return fmt.Errorf("%w: staggerSeconds must not be negative", model.ErrInvalidInput)
+	}
+
+	for i, lot := range input.Lots {
+		if lot.ItemUuid == "" {
+			return fmt.Errorf("%w: lot %d: itemUuid is required", model.ErrInvalidInput, i+1)
+		}
+	}
+
+	return nil
+}
+
+// lotAuction - the auction of a lot, sold by the seller of the event and titled "lot n" unless the lot has a title
+func lotAuction(event model.AuctionEventInput, lot model.LotInput, number int64) model.AuctionInput {
+	item := lot.Item
+	if item == "" {
+		item = fmt.Sprintf("lot %d", number)
+	}
+
+	price := func(amount int64) money.Money {
+		return money.Money{Amount: amount, Currency: event.Currency}
+	}
+
+	return model.AuctionInput{
+		Item:                item,
+		ItemUuid:            lot.ItemUuid,
+		Price:               price(lot.Price),
+		UserUuid:            event.UserUuid,
+		BiddersThreshold:    lot.BiddersThreshold,
+		ExpiredAt:           event.FirstLotClosesAt.Add(time.Duration((number-1)*event.StaggerSeconds) * time.Second),
+		Type:                lot.Type,
+		FloorPrice:          price(lot.FloorPrice),
+		PriceStep:           price(lot.PriceStep),
+		StepIntervalSeconds: lot.StepIntervalSeconds,
+		ReservePrice:        price(lot.ReservePrice),
+		BuyNowPrice:         price(lot.BuyNowPrice),
+		LotNumber:           number,
+	}
+}
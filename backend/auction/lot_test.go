@@ -0,0 +1,142 @@
+package auction
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+func TestServiceAuction_CreateAuctionEventStaggersLots(t *testing.T) {
+	firstClose := time.Now().Add(24 * time.Hour)
+	input := model.AuctionEventInput{
+		Title:            "estate sale",
+		UserUuid:         "seller-uuid",
+		FirstLotClosesAt: firstClose,
+		StaggerSeconds:   60,
+		Currency:         money.EUR,
+		Lots: []model.LotInput{
+			{ItemUuid: "item-a", Item: "clock", Price: 100},
+			{ItemUuid: "item-b", Price: 50, ReservePrice: 80},
+			{ItemUuid: "item-c", Price: 10},
+		},
+	}
+	repo := &MockRepository{}
+	service := New(repo, nil, nil, Config{}, zap.NewNop())
+	repo.mock.On("CreateEvent", input, mock.Anything).Return("event-uuid", nil)
+
+	id, err := service.CreateAuctionEvent(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "event-uuid", id)
+
+	lots := repo.mock.Calls[0].Arguments.Get(1).([]model.AuctionInput)
+	assert.Len(t, lots, 3)
+	assert.Equal(t, "clock", lots[0].Item)
+	assert.Equal(t, "lot 2", lots[1].Item)
+	assert.Equal(t, int64(80), lots[1].ReservePrice.Amount)
+	for i, lot := range lots {
+		assert.Equal(t, int64(i+1), lot.LotNumber)
+		assert.Equal(t, "seller-uuid", lot.UserUuid)
+		assert.Equal(t, money.EUR, lot.Currency())
+		assert.Equal(t, firstClose.Add(time.Duration(i)*time.Minute), lot.ExpiredAt)
+	}
+}
+
+func TestServiceAuction_CreateAuctionEventInvalid(t *testing.T) {
+	valid := model.AuctionEventInput{
+		Title:            "estate sale",
+		UserUuid:         "seller-uuid",
+		FirstLotClosesAt: time.Now().Add(time.Hour),
+		Lots:             []model.LotInput{{ItemUuid: "item-a", Price: 100}},
+	}
+
+	tests := []struct {
+		name  string
+		input func(input model.AuctionEventInput) model.AuctionEventInput
+	}{
+		{
+			name: "no lots",
+			input: func(input model.AuctionEventInput) model.AuctionEventInput {
+				input.Lots = nil
+				return input
+			},
+		},
+		{
+			name: "lot without item",
+			input: func(input model.AuctionEventInput) model.AuctionEventInput {
+				input.Lots = []model.LotInput{{Price: 100}}
+				return input
+			},
+		},
+		{
+			name: "negative stagger",
+			input: func(input model.AuctionEventInput) model.AuctionEventInput {
+				input.StaggerSeconds = -1
+				return input
+			},
+		},
+		{
+			name: "first lot closes in the past",
+			input: func(input model.AuctionEventInput) model.AuctionEventInput {
+				input.FirstLotClosesAt = time.Now().Add(-time.Hour)
+				return input
+			},
+		},
+		{
+			name: "invalid lot",
+			input: func(input model.AuctionEventInput) model.AuctionEventInput {
+				input.Lots = []model.LotInput{{ItemUuid: "item-a", Price: 100, Type: model.Dutch}}
+				return input
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			service := New(repo, nil, nil, Config{}, zap.NewNop())
+
+			_, err := service.CreateAuctionEvent(tt.input(valid))
+
+			assert.ErrorIs(t, err, model.ErrInvalidInput)
+			repo.mock.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestServiceAuction_GetCatalogue(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, nil, nil, Config{}, zap.NewNop())
+	repo.mock.On("Catalogue", "event-uuid").Return(model.Catalogue{
+		Event: model.AuctionEvent{Uuid: "event-uuid", LotsCount: 2},
+		Lots: []model.Auction{
+			{Uuid: "lot-a", LotNumber: 1, Price: usd(100), Status: model.InProgress},
+			{Uuid: "lot-b", LotNumber: 2, Price: usd(50), WinnerUuid: "bidder-a", WinningPrice: usd(70), ReservePrice: usd(80), Status: model.InProgress},
+		},
+	}, nil)
+
+	result, err := service.GetCatalogue("event-uuid")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Lots, 2)
+	assert.Equal(t, int64(100), result.Lots[0].CurrentPrice.Amount)
+	assert.Equal(t, int64(70), result.Lots[1].CurrentPrice.Amount)
+	assert.False(t, result.Lots[1].ReserveMet)
+}
+
+func TestServiceAuction_GetCatalogueNotFound(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, nil, nil, Config{}, zap.NewNop())
+	repo.mock.On("Catalogue", "missing-uuid").Return(model.Catalogue{}, model.ErrNotFound)
+
+	_, err := service.GetCatalogue("missing-uuid")
+
+	assert.True(t, errors.Is(err, model.ErrNotFound))
+}
@@ -1,20 +1,204 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
 
 type Auction struct {
-	ID               int64     `json:"-" db:"id"`
-	Uuid             string    `json:"uuid" db:"uuid"`
-	Item             string    `json:"item" db:"item"`
-	Price            int64     `json:"price" db:"price"`
-	WinningPrice     int64     `json:"winningPrice" db:"winning_price"`
-	UserUuid         string    `json:"UserUuid" db:"user_uuid"`
-	BiddersCount     int64     `json:"biddersCount" db:"bidders_count"`
-	BiddersThreshold int64     `json:"biddersThreshold" db:"bidders_threshold"`
-	CreatedAt        time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt        time.Time `json:"updatedAt" db:"updated_at"`
-	ExpiredAt        time.Time `json:"expiredAt" db:"expired_at"`
-	Status           Status    `json:"status" db:"status"`
+	ID                int64       `json:"-" db:"id"`
+	Uuid              string      `json:"uuid" db:"uuid"`
+	Item              string      `json:"item" db:"item"`
+	Price             money.Money `json:"price" db:"price"`
+	WinningPrice      money.Money `json:"winningPrice" db:"winning_price"`
+	WinnerUuid        string      `json:"-" db:"winner_uuid"`
+	UserUuid          string      `json:"UserUuid" db:"user_uuid"`
+	BiddersCount      int64       `json:"biddersCount" db:"bidders_count"`
+	BiddersThreshold  int64       `json:"biddersThreshold" db:"bidders_threshold"`
+	CreatedAt         time.Time   `json:"createdAt" db:"created_at"`
+	UpdatedAt         time.Time   `json:"updatedAt" db:"updated_at"`
+	ExpiredAt         time.Time   `json:"expiredAt" db:"expired_at"`
+	OriginalExpiredAt time.Time   `json:"originalExpiredAt" db:"original_expired_at"`
+	SoldAt            *time.Time  `json:"soldAt,omitempty" db:"sold_at"`
+	Status            Status      `json:"status" db:"status"`
+	Type              Type        `json:"type" db:"type"`
+	// FloorPrice, PriceStep and StepIntervalSeconds - a dutch auction starts at Price and drops by PriceStep
+	// every StepIntervalSeconds until it reaches FloorPrice
+	FloorPrice          money.Money `json:"floorPrice" db:"floor_price"`
+	PriceStep           money.Money `json:"priceStep" db:"price_step"`
+	StepIntervalSeconds int64       `json:"stepIntervalSeconds" db:"step_interval_seconds"`
+	// ReservePrice - hidden from bidders, they only see whether it was met
+	ReservePrice    money.Money `json:"-" db:"reserve_price"`
+	ReserveMet      bool        `json:"reserveMet" db:"-"`
+	BuyNowPrice     money.Money `json:"buyNowPrice" db:"buy_now_price"`
+	BuyNowAvailable bool        `json:"buyNowAvailable" db:"-"`
+	CurrentPrice    money.Money `json:"currentPrice" db:"-"`
+	// EventUuid, LotNumber and ItemUuid - set when the auction is a lot of an auction event
+	EventUuid string `json:"eventUuid,omitempty" db:"event_uuid"`
+	LotNumber int64  `json:"lotNumber,omitempty" db:"lot_number"`
+	ItemUuid  string `json:"itemUuid,omitempty" db:"item_uuid"`
+	// ParentUuid, Origin and RelistCount - the lineage of an auction created from another one,
+	// RelistCount is how many times in a row the item was relisted
+	ParentUuid  string `json:"parentUuid,omitempty" db:"parent_uuid"`
+	Origin      Origin `json:"origin,omitempty" db:"origin"`
+	RelistCount int64  `json:"relistCount" db:"relist_count"`
+	// Category and IncrementTableUuid - pick the increment table of the auction, the table set on the auction
+	// comes first, then the table of the category and last the default table of the service
+	Category           string `json:"category" db:"category"`
+	IncrementTableUuid string `json:"incrementTableUuid,omitempty" db:"increment_table_uuid"`
+	// Display - the prices written for the locale the client asked for, nil when it asked for none
+	Display *Display `json:"display,omitempty" db:"-"`
+	// Converted - the prices in the currency of the viewer, nil when the viewer uses the auction currency
+	Converted *Converted `json:"converted,omitempty" db:"-"`
+}
+
+// MarshalJSON - the winner is shown masked, like the bidders of the bid history
+func (a Auction) MarshalJSON() ([]byte, error) {
+	type auction Auction
+	public := struct {
+		auction
+		Winner string `json:"winner,omitempty"`
+	}{auction: auction(a)}
+
+	if a.WinnerUuid != "" {
+		public.Winner = bidermodel.MaskIdentity(a.WinnerUuid)
+	}
+
+	return json.Marshal(public)
+}
+
+// Currency - the ISO 4217 currency of all the prices and bids of the auction, amounts are in its minor units.
+// the prices share the currency column of the auction so the currency of the start price stands for all of them
+func (a Auction) Currency() money.Currency {
+	return a.Price.Currency
+}
+
+// Money - the amount in the currency of the auction
+func (a Auction) Money(amount int64) money.Money {
+	return money.Money{Amount: amount, Currency: a.Currency()}
+}
+
+// Display - the prices of an auction formatted for a locale, "$1,234.56" in en-US
+type Display struct {
+	Locale       string `json:"locale"`
+	Price        string `json:"price"`
+	CurrentPrice string `json:"currentPrice"`
+	WinningPrice string `json:"winningPrice,omitempty"`
+	BuyNowPrice  string `json:"buyNowPrice,omitempty"`
+}
+
+// Localize - fills Display with the prices of the auction written for the locale
+func (a *Auction) Localize(locale string) {
+	display := Display{
+		Locale:       locale,
+		Price:        a.Price.Format(locale),
+		CurrentPrice: a.CurrentPrice.Format(locale),
+	}
+
+	if a.WinnerUuid != "" && !a.Type.Sealed() {
+		display.WinningPrice = a.WinningPrice.Format(locale)
+	}
+
+	if a.BuyNowPrice.Amount > 0 {
+		display.BuyNowPrice = a.BuyNowPrice.Format(locale)
+	}
+
+	a.Display = &display
+}
+
+// Converted - the prices of an auction converted to another currency. the conversion is indicative only,
+// bids are placed and settled in the currency of the auction
+type Converted struct {
+	Currency     money.Currency `json:"currency"`
+	Rate         float64        `json:"rate"`
+	AsOf         time.Time      `json:"asOf"`
+	Indicative   bool           `json:"indicative"`
+	Price        int64          `json:"price"`
+	CurrentPrice int64          `json:"currentPrice"`
+	WinningPrice int64          `json:"winningPrice,omitempty"`
+	BuyNowPrice  int64          `json:"buyNowPrice,omitempty"`
+}
+
+// Convert - fills Converted with the prices of the auction converted with the rate, which must be from the
+// currency of the auction
+func (a *Auction) Convert(rate money.Rate) error {
+	if rate.From != a.Currency() {
+		return fmt.Errorf("%w: rate from %s for an auction in %s", money.ErrCurrencyMismatch, rate.From, a.Currency())
+	}
+
+	convert := func(price money.Money) int64 {
+		conversion, _ := rate.Convert(price)
+		return conversion.Money.Amount
+	}
+
+	converted := Converted{
+		Currency:     rate.To,
+		Rate:         rate.Value,
+		AsOf:         rate.AsOf,
+		Indicative:   true,
+		Price:        convert(a.Price),
+		CurrentPrice: convert(a.CurrentPrice),
+		BuyNowPrice:  convert(a.BuyNowPrice),
+	}
+
+	if a.WinnerUuid != "" && !a.Type.Sealed() {
+		converted.WinningPrice = convert(a.WinningPrice)
+	}
+
+	a.Converted = &converted
+
+	return nil
+}
+
+// HasReserveMet - an auction without a reserve is always met, otherwise the high bid must reach the reserve
+func (a Auction) HasReserveMet() bool {
+	if a.ReservePrice.Amount == 0 {
+		return true
+	}
+
+	return a.WinnerUuid != "" && a.WinningPrice.Amount >= a.ReservePrice.Amount
+}
+
+// CanBuyNow - buy it now is offered on auctions in progress until the first bid,
+// or until the high bid meets the reserve when the auction has one
+func (a Auction) CanBuyNow() bool {
+	if a.BuyNowPrice.Amount == 0 || a.Status != InProgress {
+		return false
+	}
+
+	if a.ReservePrice.Amount > 0 {
+		return !a.HasReserveMet()
+	}
+
+	return a.WinnerUuid == ""
+}
+
+// PriceAt - the price of the auction at the given instant, for dutch auctions in progress
+// it is the start price minus the steps elapsed since the auction was created, never lower than the floor
+func (a Auction) PriceAt(now time.Time) money.Money {
+	if a.Type != Dutch || a.Status != InProgress {
+		if a.WinnerUuid != "" {
+			return a.WinningPrice
+		}
+		return a.Price
+	}
+
+	if a.StepIntervalSeconds <= 0 || now.Before(a.CreatedAt) {
+		return a.Price
+	}
+
+	steps := int64(now.Sub(a.CreatedAt)/time.Second) / a.StepIntervalSeconds
+	price := a.Price.Amount - steps*a.PriceStep.Amount
+	if price < a.FloorPrice.Amount {
+		return a.Money(a.FloorPrice.Amount)
+	}
+
+	return a.Money(price)
 }
 
 type Status int
@@ -23,4 +207,128 @@ const (
 	InProgress Status = iota
 	Sold
 	Expired
+	Cancelled
+)
+
+// Type - the auction format, English auctions are open and ascending while sealed auctions hide
+// the bids until the close where the winner and the price are computed
+type Type int
+
+const (
+	English Type = iota
+	SealedFirstPrice
+	SealedSecondPrice
+	Dutch
+)
+
+func (t Type) Valid() bool {
+	return t >= English && t <= Dutch
+}
+
+func (t Type) Sealed() bool {
+	return t == SealedFirstPrice || t == SealedSecondPrice
+}
+
+// Origin - how an auction was created from another auction
+type Origin string
+
+const (
+	OriginRelist Origin = "relist"
+	OriginClone  Origin = "clone"
 )
+
+var (
+	ErrNotFound      = errors.New("auction not found")
+	ErrInvalidInput  = errors.New("invalid auction input")
+	ErrNotInProgress = errors.New("auction is not in progress")
+	ErrBidTooLow     = errors.New("bid is lower than the minimum allowed bid")
+	ErrBuyNowClosed  = errors.New("buy it now is no longer available")
+	ErrForbidden     = errors.New("not allowed to act on this auction")
+	ErrRetractClosed = errors.New("bid can no longer be retracted")
+	ErrNotRelistable = errors.New("only expired auctions can be relisted")
+	ErrRelisted      = errors.New("auction was already relisted")
+	ErrHasBids       = errors.New("prices can not change once the auction has bids")
+)
+
+// AuctionInput - UserUuid is the seller, on create and update it is the user of the request
+type AuctionInput struct {
+	Uuid                string      `json:"uuid"`
+	Item                string      `json:"item"`
+	Price               money.Money `json:"price"`
+	UserUuid            string      `json:"-"`
+	BiddersThreshold    int64       `json:"biddersThreshold"`
+	ExpiredAt           time.Time   `json:"expiredAt"`
+	Type                Type        `json:"type"`
+	FloorPrice          money.Money `json:"floorPrice"`
+	PriceStep           money.Money `json:"priceStep"`
+	StepIntervalSeconds int64       `json:"stepIntervalSeconds"`
+	ReservePrice        money.Money `json:"reservePrice"`
+	BuyNowPrice         money.Money `json:"buyNowPrice"`
+	ItemUuid            string      `json:"itemUuid"`
+	EventUuid           string      `json:"-"`
+	LotNumber           int64       `json:"-"`
+	ParentUuid          string      `json:"-"`
+	Origin              Origin      `json:"-"`
+	RelistCount         int64       `json:"-"`
+	Category            string      `json:"category"`
+	IncrementTableUuid  string      `json:"incrementTableUuid"`
+}
+
+// Currency - the currency of the auction, the currency of its price
+func (i AuctionInput) Currency() money.Currency {
+	return i.Price.Currency
+}
+
+// Prices - the prices of the input, which must all be in the currency of the auction
+func (i AuctionInput) Prices() []money.Money {
+	return []money.Money{i.Price, i.FloorPrice, i.PriceStep, i.ReservePrice, i.BuyNowPrice}
+}
+
+// InCurrency - the input with the prices which name no currency in the currency, the prices which name one keep it
+func (i AuctionInput) InCurrency(currency money.Currency) AuctionInput {
+	for _, price := range []*money.Money{&i.Price, &i.FloorPrice, &i.PriceStep, &i.ReservePrice, &i.BuyNowPrice} {
+		if price.Currency == "" {
+			price.Currency = currency
+		}
+	}
+
+	return i
+}
+
+// ChangesPrices - whether an update sets any of the prices of the auction
+func (i AuctionInput) ChangesPrices() bool {
+	return i.Price.Amount != 0 || i.FloorPrice.Amount != 0 || i.PriceStep.Amount != 0 || i.StepIntervalSeconds != 0 ||
+		i.ReservePrice.Amount != 0 || i.BuyNowPrice.Amount != 0
+}
+
+// RelistInput - creates a new auction from AuctionUuid closing at ExpiredAt, the prices which are set
+// replace the prices of the original auction
+type RelistInput struct {
+	AuctionUuid  string       `json:"-"`
+	UserUuid     string       `json:"userUuid"`
+	ExpiredAt    time.Time    `json:"expiredAt"`
+	Price        *money.Money `json:"price"`
+	ReservePrice *money.Money `json:"reservePrice"`
+	BuyNowPrice  *money.Money `json:"buyNowPrice"`
+}
+
+type ListInput struct {
+	Page     PageRequest
+	Item     string
+	ItemUuid string
+	UserUuid string
+	Status   *Status
+}
+
+type PageRequest struct {
+	Offset int64
+	Limit  int64
+}
+
+func (p *PageRequest) GetLimit() int64 {
+	if p.Limit == 0 {
+		return 50
+	}
+
+	return p.Limit
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"time"
+
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// Bid - a single entry of the bid history of an auction, the bidder is exposed masked only
+type Bid struct {
+	ID          int64     `json:"-" db:"id"`
+	Uuid        string    `json:"uuid" db:"uuid"`
+	AuctionUuid string    `json:"auctionUuid" db:"auction_uuid"`
+	BidderUuid  string    `json:"-" db:"bidder_uuid"`
+	Bidder      string    `json:"bidder" db:"-"`
+	Amount      int64     `json:"amount" db:"amount"`
+	Proxy       bool      `json:"proxy" db:"proxy"`
+	Retracted   bool      `json:"retracted" db:"retracted"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	// IP - the address the bidder bid from, kept for the fraud analysis and never shown
+	IP string `json:"-" db:"ip"`
+	// Converted - the amount in the currency of the viewer, indicative only
+	Converted *money.Conversion `json:"converted,omitempty" db:"-"`
+}
+
+type BidListInput struct {
+	AuctionUuid string
+	Page        PageRequest
+}
+
+// BidHistory - a page of the bid history, oldest bid first, with the total number of bids of the auction
+type BidHistory struct {
+	Bids     []Bid          `json:"bids"`
+	Total    int64          `json:"total"`
+	Currency money.Currency `json:"currency"`
+}
+
+// BidInput - ActorUuid is the user of the request, who must be the user the bidder bids for
+type BidInput struct {
+	AuctionUuid string `json:"auctionUuid"`
+	BidderUuid  string `json:"bidderUuid"`
+	ActorUuid   string `json:"-"`
+	Amount      int64  `json:"amount"`
+	// Currency - optional, a bid in another currency than the auction's is refused
+	Currency money.Currency `json:"currency"`
+	// IP - the address of the request, set by the transport
+	IP string `json:"-"`
+}
+
+// AcceptInput - a bidder taking a dutch auction at its current price or an auction at its buy it now price,
+// ActorUuid is the user of the request, who must be the user the bidder bids for
+type AcceptInput struct {
+	AuctionUuid string `json:"auctionUuid"`
+	BidderUuid  string `json:"bidderUuid"`
+	ActorUuid   string `json:"-"`
+	IP          string `json:"-"`
+}
+
+// RetractInput - a bidder taking back one of their bids, the later bids they placed on top of it go with it.
+// ActorUuid is the user of the request, who must be the user the bidder of the bid bids for
+type RetractInput struct {
+	AuctionUuid string `json:"auctionUuid"`
+	BidUuid     string `json:"bidUuid"`
+	ActorUuid   string `json:"-"`
+	Reason      string `json:"reason"`
+}
+
+// CancelBidsInput - the seller or an admin cancelling all the bids of a bidder, ActorUuid is the user of the request
+type CancelBidsInput struct {
+	AuctionUuid string `json:"auctionUuid"`
+	BidderUuid  string `json:"bidderUuid"`
+	ActorUuid   string `json:"-"`
+	Reason      string `json:"reason"`
+}
+
+// ProxyBidInput - ActorUuid is the user of the request, who must be the user the bidder bids for
+type ProxyBidInput struct {
+	AuctionUuid string         `json:"auctionUuid"`
+	BidderUuid  string         `json:"bidderUuid"`
+	ActorUuid   string         `json:"-"`
+	MaxAmount   int64          `json:"maxAmount"`
+	Currency    money.Currency `json:"currency"`
+	IP          string         `json:"-"`
+}
+
+// Placement - what a bid or a retraction changed besides the auction row: the bids to append to the history,
+// the proxy maximum to store or the bidder whose maximum is dropped, and the bids retracted with their reason
+type Placement struct {
+	Bids        []Bid
+	Proxy       *bidermodel.ProxyBid
+	DropProxyOf string
+	Retracted   []string
+	Reason      string
+	// Commitment - what the placing bidder stands to pay, checked against the bidder's credit before anything is saved
+	Commitment *bidermodel.Commitment
+}
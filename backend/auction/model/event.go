@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+type EventType string
+
+const (
+	EventBid        EventType = "auction.bid"
+	EventClosed     EventType = "auction.closed"
+	EventExtended   EventType = "auction.extended"
+	EventRetract    EventType = "auction.bids_retracted"
+	EventEndingSoon EventType = "auction.ending_soon"
+	EventFeedback   EventType = "auction.feedback"
+)
+
+// Event - an auction lifecycle event published to the other services
+type Event struct {
+	Type        EventType `json:"type"`
+	AuctionUuid string    `json:"auctionUuid"`
+	Status      Status    `json:"status"`
+	WinnerUuid  string    `json:"winnerUuid,omitempty"`
+	// PreviousWinnerUuid - the bidder who led the auction before the bid, set on bid events only
+	PreviousWinnerUuid string `json:"previousWinnerUuid,omitempty"`
+	// BidderUuid - the bidder who placed the bid, set on bid events only. a proxy auto-bid can outbid the bidder
+	// at once so the bidder is not always the winner
+	BidderUuid string `json:"bidderUuid,omitempty"`
+	// SellerUuid and Category - the seller and the category of the auction, set on closed events only
+	// so the sale can be settled and its fee priced
+	SellerUuid   string         `json:"sellerUuid,omitempty"`
+	Category     string         `json:"category,omitempty"`
+	WinningPrice int64          `json:"winningPrice,omitempty"`
+	Currency     money.Currency `json:"currency,omitempty"`
+	ExpiredAt    time.Time      `json:"expiredAt"`
+	OccurredAt   time.Time      `json:"occurredAt"`
+	// Reputation - the reputation of the rated user after the feedback, set on feedback events only
+	Reputation *Reputation `json:"reputation,omitempty"`
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrFeedbackClosed = errors.New("feedback can no longer be left on this auction")
+	ErrFeedbackExists = errors.New("feedback was already left on this auction")
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+	// MaxCommentLength - the longest comment a feedback may carry, in characters
+	MaxCommentLength = 1000
+)
+
+// Role - the part the rated user played in the auction
+type Role string
+
+const (
+	Buyer  Role = "buyer"
+	Seller Role = "seller"
+)
+
+// Feedback - the rating and the comment one party of a sold auction left the other one, the author and the subject
+// are users
+type Feedback struct {
+	ID          int64     `json:"-" db:"id"`
+	Uuid        string    `json:"uuid" db:"uuid"`
+	AuctionUuid string    `json:"auctionUuid" db:"auction_uuid"`
+	AuthorUuid  string    `json:"authorUuid" db:"author_uuid"`
+	SubjectUuid string    `json:"subjectUuid" db:"subject_uuid"`
+	SubjectRole Role      `json:"subjectRole" db:"subject_role"`
+	Rating      int64     `json:"rating" db:"rating"`
+	Comment     string    `json:"comment" db:"comment"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+// FeedbackInput - the author, the user who sold the auction or the user its winning bidder bought for, rating the
+// other party. the author is the user of the request
+type FeedbackInput struct {
+	AuctionUuid string `json:"-"`
+	AuthorUuid  string `json:"-"`
+	Rating      int64  `json:"rating"`
+	Comment     string `json:"comment"`
+}
+
+// FeedbackListInput - the feedback left on an auction or the feedback a user received, newest first
+type FeedbackListInput struct {
+	AuctionUuid string
+	SubjectUuid string
+	Page        PageRequest
+}
+
+type FeedbackList struct {
+	Feedback []Feedback `json:"feedback"`
+	Total    int64      `json:"total"`
+}
+
+// Reputation - the ratings a user received as a seller and as a buyer, the sums are kept so every
+// new rating updates the scores without reading the feedback again. a score is the average rating
+type Reputation struct {
+	UserUuid    string    `json:"userUuid" db:"user_uuid"`
+	Count       int64     `json:"count" db:"-"`
+	Score       float64   `json:"score" db:"-"`
+	SellerCount int64     `json:"sellerCount" db:"seller_count"`
+	SellerSum   int64     `json:"-" db:"seller_sum"`
+	SellerScore float64   `json:"sellerScore" db:"-"`
+	BuyerCount  int64     `json:"buyerCount" db:"buyer_count"`
+	BuyerSum    int64     `json:"-" db:"buyer_sum"`
+	BuyerScore  float64   `json:"buyerScore" db:"-"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Add - counts a new rating the user received in the role
+func (r *Reputation) Add(role Role, rating int64) {
+	if role == Seller {
+		r.SellerCount++
+		r.SellerSum += rating
+	} else {
+		r.BuyerCount++
+		r.BuyerSum += rating
+	}
+
+	r.Summarize()
+}
+
+// Summarize - computes the counts and the scores from the sums, scores are rounded to two decimals
+func (r *Reputation) Summarize() {
+	r.Count = r.SellerCount + r.BuyerCount
+	r.Score = average(r.SellerSum+r.BuyerSum, r.Count)
+	r.SellerScore = average(r.SellerSum, r.SellerCount)
+	r.BuyerScore = average(r.BuyerSum, r.BuyerCount)
+}
+
+func average(sum, count int64) float64 {
+	if count == 0 {
+		return 0
+	}
+
+	return math.Round(float64(sum)/float64(count)*100) / 100
+}
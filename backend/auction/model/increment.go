@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// IncrementBand - the minimum raise over prices below Below, the last band of a table has no bound and Below is 0
+type IncrementBand struct {
+	Below     int64 `json:"below" db:"below"`
+	Increment int64 `json:"increment" db:"increment"`
+}
+
+// IncrementTable - the price bands deciding the minimum raise, used by the auctions pointing at it
+// or by the auctions of its category
+type IncrementTable struct {
+	ID        int64           `json:"-" db:"id"`
+	Uuid      string          `json:"uuid" db:"uuid"`
+	Name      string          `json:"name" db:"name"`
+	Category  string          `json:"category" db:"category"`
+	Bands     []IncrementBand `json:"bands" db:"-"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// At - the increment of the band the price falls in
+func (t IncrementTable) At(price int64) int64 {
+	for _, band := range t.Bands {
+		if band.Below == 0 || price < band.Below {
+			return band.Increment
+		}
+	}
+
+	return 0
+}
+
+// IncrementTableInput - ActorUuid is the user of the request, only admins create increment tables
+type IncrementTableInput struct {
+	Name      string          `json:"name"`
+	Category  string          `json:"category"`
+	Bands     []IncrementBand `json:"bands"`
+	ActorUuid string          `json:"-"`
+}
+
+// NextBid - the lowest bid an auction accepts right now and the increment of the current price band
+type NextBid struct {
+	AuctionUuid  string      `json:"auctionUuid"`
+	CurrentPrice money.Money `json:"currentPrice"`
+	MinimumBid   money.Money `json:"minimumBid"`
+	Increment    money.Money `json:"increment"`
+}
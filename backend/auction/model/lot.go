@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// AuctionEvent - groups the lots of a single seller, every lot is an auction of its own
+// and the lots close one after the other, StaggerSeconds apart, starting at FirstLotClosesAt
+type AuctionEvent struct {
+	ID               int64     `json:"-" db:"id"`
+	Uuid             string    `json:"uuid" db:"uuid"`
+	Title            string    `json:"title" db:"title"`
+	UserUuid         string    `json:"userUuid" db:"user_uuid"`
+	FirstLotClosesAt time.Time `json:"firstLotClosesAt" db:"first_lot_closes_at"`
+	StaggerSeconds   int64     `json:"staggerSeconds" db:"stagger_seconds"`
+	LotsCount        int64     `json:"lotsCount" db:"lots_count"`
+	CreatedAt        time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt        time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// LotInput - a lot of an auction event, linked to an item by ItemUuid. Item is the title shown in the catalogue
+type LotInput struct {
+	ItemUuid            string `json:"itemUuid"`
+	Item                string `json:"item"`
+	Price               int64  `json:"price"`
+	BiddersThreshold    int64  `json:"biddersThreshold"`
+	Type                Type   `json:"type"`
+	FloorPrice          int64  `json:"floorPrice"`
+	PriceStep           int64  `json:"priceStep"`
+	StepIntervalSeconds int64  `json:"stepIntervalSeconds"`
+	ReservePrice        int64  `json:"reservePrice"`
+	BuyNowPrice         int64  `json:"buyNowPrice"`
+}
+
+type AuctionEventInput struct {
+	Title            string    `json:"title"`
+	UserUuid         string    `json:"userUuid"`
+	FirstLotClosesAt time.Time `json:"firstLotClosesAt"`
+	StaggerSeconds   int64     `json:"staggerSeconds"`
+	// Currency - the currency of all the lots of the event
+	Currency money.Currency `json:"currency"`
+	Lots     []LotInput     `json:"lots"`
+}
+
+// Catalogue - an auction event with its lots in lot order
+type Catalogue struct {
+	Event AuctionEvent `json:"event"`
+	Lots  []Auction    `json:"lots"`
+}
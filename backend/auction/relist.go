@@ -0,0 +1,93 @@
+package auction
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+// RelistAuction - creates a new auction from an expired one of the seller, keeping its item, thresholds and format
+func (s *ServiceAuction) RelistAuction(input model.RelistInput) (string, error) {
+	return s.createFrom(input, model.OriginRelist)
+}
+
+// CloneAuction - creates a new auction from any auction of the seller, using it as a template
+func (s *ServiceAuction) CloneAuction(input model.RelistInput) (string, error) {
+	return s.createFrom(input, model.OriginClone)
+}
+
+func (s *ServiceAuction) createFrom(input model.RelistInput, origin model.Origin) (string, error) {
+	if input.AuctionUuid == "" || input.UserUuid == "" {
+		return "", fmt.Errorf("%w: auctionUuid and userUuid are required", model.ErrInvalidInput)
+	}
+
+	source, err := s.repo.Single(input.AuctionUuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.createFrom failed getting auction", zap.Any("input", input), zap.Error(err))
+		return "", err
+	}
+
+	if source.UserUuid != input.UserUuid {
+		return "", model.ErrForbidden
+	}
+
+	if origin == model.OriginRelist && source.Status != model.Expired {
+		return "", model.ErrNotRelistable
+	}
+
+	auction := derive(source, input, origin)
+	if err = validateCreate(auction); err != nil {
+		return "", err
+	}
+
+	id, err := s.repo.Create(auction)
+	if err != nil {
+		s.logger.Error("ServiceAuction.createFrom failed creating auction", zap.Any("input", input), zap.Error(err))
+		return "", err
+	}
+
+	return id, nil
+}
+
+// derive - the input of an auction created from source, a relist counts one more relist of the item
+// while a clone starts a new lineage count. lots are derived as standalone auctions
+func derive(source model.Auction, input model.RelistInput, origin model.Origin) model.AuctionInput {
+	auction := model.AuctionInput{
+		Item:                source.Item,
+		ItemUuid:            source.ItemUuid,
+		Price:               source.Price,
+		UserUuid:            source.UserUuid,
+		BiddersThreshold:    source.BiddersThreshold,
+		ExpiredAt:           input.ExpiredAt,
+		Type:                source.Type,
+		FloorPrice:          source.FloorPrice,
+		PriceStep:           source.PriceStep,
+		StepIntervalSeconds: source.StepIntervalSeconds,
+		ReservePrice:        source.ReservePrice,
+		BuyNowPrice:         source.BuyNowPrice,
+		Category:            source.Category,
+		IncrementTableUuid:  source.IncrementTableUuid,
+		ParentUuid:          source.Uuid,
+		Origin:              origin,
+	}
+
+	if origin == model.OriginRelist {
+		auction.RelistCount = source.RelistCount + 1
+	}
+
+	if input.Price != nil {
+		auction.Price = *input.Price
+	}
+
+	if input.ReservePrice != nil {
+		auction.ReservePrice = *input.ReservePrice
+	}
+
+	if input.BuyNowPrice != nil {
+		auction.BuyNowPrice = *input.BuyNowPrice
+	}
+
+	return auction.InCurrency(source.Currency())
+}
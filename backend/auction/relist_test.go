@@ -0,0 +1,138 @@
+package auction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+func expiredAuction() model.Auction {
+	return model.Auction{
+		Uuid:             "expired-uuid",
+		Item:             "clock",
+		ItemUuid:         "item-uuid",
+		UserUuid:         "seller-uuid",
+		Price:            gbp(100),
+		BiddersThreshold: 2,
+		ReservePrice:     gbp(300),
+		BuyNowPrice:      gbp(500),
+		Status:           model.Expired,
+		Type:             model.English,
+		RelistCount:      1,
+		EventUuid:        "event-uuid",
+		LotNumber:        3,
+	}
+}
+
+func TestServiceAuction_RelistAuction(t *testing.T) {
+	expiredAt := time.Now().Add(24 * time.Hour)
+	reserve := money.Money{Amount: 250}
+	repo := &MockRepository{}
+	service := New(repo, nil, nil, Config{}, zap.NewNop())
+	repo.mock.On("Single", "expired-uuid").Return(expiredAuction(), nil)
+	repo.mock.On("Create", model.AuctionInput{
+		Item:             "clock",
+		ItemUuid:         "item-uuid",
+		Price:            gbp(100),
+		UserUuid:         "seller-uuid",
+		BiddersThreshold: 2,
+		ExpiredAt:        expiredAt,
+		Type:             model.English,
+		FloorPrice:       gbp(0),
+		PriceStep:        gbp(0),
+		ReservePrice:     gbp(250),
+		BuyNowPrice:      gbp(500),
+		ParentUuid:       "expired-uuid",
+		Origin:           model.OriginRelist,
+		RelistCount:      2,
+	}).Return("relisted-uuid", nil)
+
+	id, err := service.RelistAuction(model.RelistInput{
+		AuctionUuid:  "expired-uuid",
+		UserUuid:     "seller-uuid",
+		ExpiredAt:    expiredAt,
+		ReservePrice: &reserve,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "relisted-uuid", id)
+	repo.mock.AssertExpectations(t)
+}
+
+func TestServiceAuction_RelistAuctionRejected(t *testing.T) {
+	expiredAt := time.Now().Add(24 * time.Hour)
+	sold := expiredAuction()
+	sold.Status = model.Sold
+
+	tests := []struct {
+		name    string
+		source  model.Auction
+		input   model.RelistInput
+		wantErr error
+	}{
+		{
+			name:    "not the seller",
+			source:  expiredAuction(),
+			input:   model.RelistInput{AuctionUuid: "expired-uuid", UserUuid: "other-uuid", ExpiredAt: expiredAt},
+			wantErr: model.ErrForbidden,
+		},
+		{
+			name:    "not expired",
+			source:  sold,
+			input:   model.RelistInput{AuctionUuid: "expired-uuid", UserUuid: "seller-uuid", ExpiredAt: expiredAt},
+			wantErr: model.ErrNotRelistable,
+		},
+		{
+			name:    "closes in the past",
+			source:  expiredAuction(),
+			input:   model.RelistInput{AuctionUuid: "expired-uuid", UserUuid: "seller-uuid", ExpiredAt: time.Now().Add(-time.Hour)},
+			wantErr: model.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			service := New(repo, nil, nil, Config{}, zap.NewNop())
+			repo.mock.On("Single", "expired-uuid").Return(tt.source, nil)
+
+			_, err := service.RelistAuction(tt.input)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			repo.mock.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestServiceAuction_CloneAuction(t *testing.T) {
+	expiredAt := time.Now().Add(24 * time.Hour)
+	source := expiredAuction()
+	source.Status = model.Sold
+	repo := &MockRepository{}
+	service := New(repo, nil, nil, Config{}, zap.NewNop())
+	repo.mock.On("Single", "expired-uuid").Return(source, nil)
+	repo.mock.On("Create", mock.Anything).Return("cloned-uuid", nil)
+
+	id, err := service.CloneAuction(model.RelistInput{AuctionUuid: "expired-uuid", UserUuid: "seller-uuid", ExpiredAt: expiredAt})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cloned-uuid", id)
+
+	created := repo.mock.Calls[1].Arguments.Get(0).(model.AuctionInput)
+	assert.Equal(t, model.OriginClone, created.Origin)
+	assert.Equal(t, "expired-uuid", created.ParentUuid)
+	assert.Equal(t, int64(0), created.RelistCount)
+	assert.Empty(t, created.EventUuid)
+	assert.Equal(t, int64(300), created.ReservePrice.Amount)
+	assert.Equal(t, money.GBP, created.Currency())
+}
+
+func gbp(amount int64) money.Money {
+	return money.Money{Amount: amount, Currency: money.GBP}
+}
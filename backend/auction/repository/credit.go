@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+var creditColumns = append(append([]string{"bidder_uuid"}, money.Columns("credit_limit")...),
+	append(money.Columns("deposit"), "deposit_required", "updated_at")...)
+
+// selector - a db or a transaction to select from
+type selector interface {
+	Select(cols ...string) *sqlz.SelectStmt
+}
+
+// commitment - the most a bidder stands to pay on one auction
+type commitment struct {
+	AuctionUuid string `db:"auction_uuid"`
+	Amount      int64  `db:"amount"`
+}
+
+// SetCredit - creates or replaces the credit of the bidder in a single upsert, which waits for a bid being checked
+// against the previous credit since the bid holds the lock of the credit row
+func (r *AuctionRepository) SetCredit(input bidermodel.CreditInput) (bidermodel.Credit, error) {
+	result := bidermodel.Credit{
+		BidderUuid:      input.BidderUuid,
+		Limit:           input.Limit,
+		Deposit:         input.Deposit,
+		DepositRequired: input.DepositRequired,
+		UpdatedAt:       time.Now(),
+	}
+
+	values := map[string]interface{}{
+		"bidder_uuid":      input.BidderUuid,
+		"deposit_required": result.DepositRequired,
+		"updated_at":       result.UpdatedAt,
+	}
+	for column, value := range result.Limit.Values("credit_limit") {
+		values[column] = value
+	}
+	for column, value := range result.Deposit.Values("deposit") {
+		values[column] = value
+	}
+
+	insert := r.db.InsertInto(dbmodel.BidderCredit).ValueMap(values)
+
+	utils.New().DebugInsert(insert, "upsert bidder credit")
+
+	err := upsert(r.db, insert, replace("credit_limit_amount", "credit_limit_currency", "deposit_amount", "deposit_currency", "deposit_required", "updated_at")...)
+	if err != nil {
+		r.logger.Error("AuctionRepository.SetCredit failed setting credit", zap.String("bidder", input.BidderUuid), zap.Error(err))
+		return bidermodel.Credit{}, err
+	}
+
+	return result, nil
+}
+
+// Credit - this method queries the credit of the bidder, a bidder without credit is not limited
+func (r *AuctionRepository) Credit(bidderUuid string) (bidermodel.Credit, error) {
+	result := bidermodel.Credit{BidderUuid: bidderUuid}
+
+	q := r.db.
+		Select(creditColumns...).
+		From(dbmodel.BidderCredit).
+		Where(sqlz.Eq("bidder_uuid", bidderUuid))
+
+	utils.New().DebugSelect(q, "bidder credit")
+
+	if err := q.GetRow(&result); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.logger.Error("AuctionRepository.Credit failed getting credit", zap.String("bidder", bidderUuid), zap.Error(err))
+		return bidermodel.Credit{}, err
+	}
+
+	return result, nil
+}
+
+// Exposure - this method sums the commitments of the bidder on the open auctions in the currency
+func (r *AuctionRepository) Exposure(bidderUuid string, currency money.Currency) (int64, error) {
+	result, err := exposure(r.db, bidderUuid, currency, "")
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.Exposure failed summing exposure", zap.String("bidder", bidderUuid), zap.Error(err))
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// loadCredit - locks the credit row of the bidder, a bidder without credit gets an empty credit and no lock
+func loadCredit(tx *sqlz.Tx, bidderUuid string) (bidermodel.Credit, error) {
+	var result bidermodel.Credit
+
+	q := tx.
+		Select(creditColumns...).
+		From(dbmodel.BidderCredit).
+		Where(sqlz.Eq("bidder_uuid", bidderUuid)).
+		Lock(sqlz.ForUpdate())
+
+	utils.New().DebugSelect(q, "lock bidder credit")
+
+	if err := q.GetRow(&result); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return bidermodel.Credit{}, err
+	}
+
+	return result, nil
+}
+
+// exposure - the bidder's commitments on the open auctions in the currency except the given auction.
+// on every auction the bidder is committed to the highest of the price it leads at, its proxy maximum
+// and, on sealed auctions, its best bid
+func exposure(db selector, bidderUuid string, currency money.Currency, except string) (int64, error) {
+	open := []sqlz.WhereCondition{sqlz.Eq("a.status", model.InProgress), sqlz.Eq("a.currency", currency)}
+	if except != "" {
+		open = append(open, sqlz.Ne("a.uuid", except))
+	}
+
+	leading := db.
+		Select("a.uuid AS auction_uuid", "a.winning_price AS amount").
+		From(dbmodel.Auctions + " a").
+		Where(open...).
+		Where(sqlz.Eq("a.winner_uuid", bidderUuid))
+
+	proxies := db.
+		Select("p.auction_uuid", "p.max_amount AS amount").
+		From(dbmodel.ProxyBids+" p").
+		InnerJoin(dbmodel.Auctions+" a", sqlz.Eq("a.uuid", sqlz.Indirect("p.auction_uuid"))).
+		Where(open...).
+		Where(sqlz.Eq("p.bidder_uuid", bidderUuid))
+
+	sealed := db.
+		Select("b.auction_uuid", "MAX(b.amount) AS amount").
+		From(dbmodel.Bids+" b").
+		InnerJoin(dbmodel.Auctions+" a", sqlz.Eq("a.uuid", sqlz.Indirect("b.auction_uuid"))).
+		LeftJoin(dbmodel.BidRetractions+" r", sqlz.Eq("r.bid_uuid", sqlz.Indirect("b.uuid"))).
+		Where(open...).
+		Where(
+			sqlz.Eq("b.bidder_uuid", bidderUuid),
+			sqlz.In("a.type", model.SealedFirstPrice, model.SealedSecondPrice),
+			sqlz.IsNull("r.id"),
+		).
+		GroupBy("b.auction_uuid")
+
+	committed := make(map[string]int64)
+	for _, q := range []*sqlz.SelectStmt{leading, proxies, sealed} {
+		var rows []commitment
+
+		utils.New().DebugSelect(q, "bidder exposure")
+
+		if err := q.GetAll(&rows); err != nil {
+			return 0, err
+		}
+
+		for _, row := range rows {
+			committed[row.AuctionUuid] = max(committed[row.AuctionUuid], row.Amount)
+		}
+	}
+
+	var total int64
+	for _, amount := range committed {
+		total += amount
+	}
+
+	return total, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+func creditRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"bidder_uuid", "credit_limit.amount", "credit_limit.currency", "deposit.amount", "deposit.currency", "deposit_required", "updated_at"})
+}
+
+// expectExposure - the bidder leads mock-other at 50.00 under a proxy maximum of 60.00, and has a sealed bid of 20.00
+func expectExposure(mockSql sqlmock.Sqlmock) {
+	mockSql.ExpectQuery("SELECT a.uuid AS auction_uuid, a.winning_price AS amount FROM auctions a WHERE a.status = \\? AND a.currency = \\? AND a.uuid <> \\? AND a.winner_uuid = \\?").
+		WithArgs(model.InProgress, money.USD, "mock-uuid", "bidder-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"auction_uuid", "amount"}).AddRow("mock-other", 5000))
+	mockSql.ExpectQuery("SELECT p.auction_uuid, p.max_amount AS amount FROM proxy_bids p INNER JOIN auctions a ON a.uuid = p.auction_uuid WHERE (.+) AND p.bidder_uuid = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"auction_uuid", "amount"}).AddRow("mock-other", 6000))
+	mockSql.ExpectQuery("SELECT b.auction_uuid, MAX\\(b.amount\\) AS amount FROM bids b INNER JOIN auctions a (.+) LEFT JOIN bid_retractions r (.+) AND b.bidder_uuid = \\? AND a.type IN \\(\\?, \\?\\) AND r.id IS NULL GROUP BY b.auction_uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"auction_uuid", "amount"}).AddRow("mock-sealed", 2000))
+}
+
+func expectLockedBid(mockSql sqlmock.Sqlmock, now time.Time, limit int64) {
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE uuid = \\? FOR UPDATE").
+		WithArgs("mock-uuid").
+		WillReturnRows(auctionRows().
+			AddRow(7, "mock-uuid", "item", 100, "USD", 0, "USD", "", "user-uuid", 0, 0, now, now, now.Add(time.Hour), now.Add(time.Hour), nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+	mockSql.ExpectQuery("SELECT (.+) FROM bidder_credit WHERE bidder_uuid = \\? FOR UPDATE").
+		WithArgs("bidder-uuid").
+		WillReturnRows(creditRows().AddRow("bidder-uuid", limit, "USD", 0, "USD", false, now))
+	mockSql.ExpectQuery("SELECT (.+) FROM proxy_bids").
+		WillReturnRows(sqlmock.NewRows(proxyBidColumns))
+}
+
+func placeCommitted(amount int64) func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error) {
+	return func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error) {
+		auction.WinningPrice = auction.Money(amount)
+		auction.WinnerUuid = "bidder-uuid"
+
+		return model.Placement{
+			Bids:       []model.Bid{{BidderUuid: "bidder-uuid", Amount: amount}},
+			Commitment: &bidermodel.Commitment{BidderUuid: "bidder-uuid", Amount: money.Money{Amount: amount, Currency: money.USD}},
+		}, nil
+	}
+}
+
+func TestAuctionRepository_PlaceBidWithinCredit(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	expectLockedBid(mockSql, now, 11000)
+	expectExposure(mockSql)
+	mockSql.ExpectExec("INSERT INTO bids").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectQuery("SELECT COUNT(.+) FROM bids").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mockSql.ExpectExec("UPDATE auctions SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	res, err := repo.PlaceBid("mock-uuid", "bidder-uuid", placeCommitted(3000))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3000), res.WinningPrice.Amount)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_PlaceBidOverCredit(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	expectLockedBid(mockSql, time.Now(), 10000)
+	expectExposure(mockSql)
+	mockSql.ExpectRollback()
+
+	_, err = repo.PlaceBid("mock-uuid", "bidder-uuid", placeCommitted(3000))
+
+	assert.ErrorIs(t, err, bidermodel.ErrCreditLimit)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_SetCreditCreates(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("INSERT INTO bidder_credit (.+) ON DUPLICATE KEY UPDATE credit_limit_amount = VALUES\\(credit_limit_amount\\)").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	credit, err := repo.SetCredit(bidermodel.CreditInput{
+		BidderUuid: "bidder-uuid",
+		Limit:      money.Money{Amount: 10000, Currency: money.USD},
+		Deposit:    money.Money{Currency: money.USD},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bidder-uuid", credit.BidderUuid)
+	assert.Equal(t, int64(10000), credit.Limit.Amount)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_SetCreditUpdates(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("INSERT INTO bidder_credit (.+) ON DUPLICATE KEY UPDATE (.+) deposit_required = VALUES\\(deposit_required\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	_, err = repo.SetCredit(bidermodel.CreditInput{
+		BidderUuid:      "bidder-uuid",
+		Limit:           money.Money{Currency: money.USD},
+		Deposit:         money.Money{Amount: 50000, Currency: money.USD},
+		DepositRequired: true,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CreditOfUnlimitedBidder(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT (.+) FROM bidder_credit WHERE bidder_uuid = \\?").
+		WithArgs("bidder-uuid").
+		WillReturnRows(creditRows())
+
+	credit, err := repo.Credit("bidder-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bidder-uuid", credit.BidderUuid)
+	assert.False(t, credit.Limited())
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_Exposure(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT a.uuid AS auction_uuid, a.winning_price AS amount FROM auctions a WHERE a.status = \\? AND a.currency = \\? AND a.winner_uuid = \\?").
+		WithArgs(model.InProgress, money.EUR, "bidder-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"auction_uuid", "amount"}).AddRow("mock-uuid", 5000).AddRow("mock-other", 700))
+	mockSql.ExpectQuery("SELECT (.+) FROM proxy_bids p").
+		WillReturnRows(sqlmock.NewRows([]string{"auction_uuid", "amount"}).AddRow("mock-uuid", 4000))
+	mockSql.ExpectQuery("SELECT (.+) FROM bids b").
+		WillReturnRows(sqlmock.NewRows([]string{"auction_uuid", "amount"}))
+
+	exposure, err := repo.Exposure("bidder-uuid", money.EUR)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5700), exposure)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
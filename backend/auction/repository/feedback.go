@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+)
+
+// duplicateEntry - the mysql error number of a unique key violation
+const duplicateEntry = 1062
+
+var feedbackColumns = []string{
+	"id",
+	"uuid",
+	"auction_uuid",
+	"author_uuid",
+	"subject_uuid",
+	"subject_role",
+	"rating",
+	"comment",
+	"created_at",
+}
+
+var reputationColumns = []string{
+	"user_uuid",
+	"seller_count",
+	"seller_sum",
+	"buyer_count",
+	"buyer_sum",
+	"updated_at",
+}
+
+// CreateFeedback - stores the feedback and counts its rating in the reputation of the rated user. the rating is
+// added to the reputation row in the upsert itself, which creates the row of a user rated for the first time, so
+// concurrent ratings of the same user wait for each other on the row and are all counted
+func (r *AuctionRepository) CreateFeedback(feedback model.Feedback) (model.Reputation, error) {
+	var result model.Reputation
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		insert := tx.InsertInto(dbmodel.Feedback).
+			ValueMap(map[string]interface{}{
+				"uuid":         feedback.Uuid,
+				"auction_uuid": feedback.AuctionUuid,
+				"author_uuid":  feedback.AuthorUuid,
+				"subject_uuid": feedback.SubjectUuid,
+				"subject_role": feedback.SubjectRole,
+				"rating":       feedback.Rating,
+				"comment":      feedback.Comment,
+				"created_at":   feedback.CreatedAt,
+			})
+
+		utils.New().DebugInsert(insert, "insert feedback")
+
+		if _, err := insert.Exec(); err != nil {
+			if isDuplicate(err) {
+				return model.ErrFeedbackExists
+			}
+			return err
+		}
+
+		var rating model.Reputation
+		rating.Add(feedback.SubjectRole, feedback.Rating)
+
+		insertReputation := tx.InsertInto(dbmodel.Reputations).
+			ValueMap(map[string]interface{}{
+				"user_uuid":    feedback.SubjectUuid,
+				"seller_count": rating.SellerCount,
+				"seller_sum":   rating.SellerSum,
+				"buyer_count":  rating.BuyerCount,
+				"buyer_sum":    rating.BuyerSum,
+				"updated_at":   feedback.CreatedAt,
+			})
+
+		utils.New().DebugInsert(insertReputation, "upsert reputation")
+
+		err := upsert(tx, insertReputation,
+			"seller_count = seller_count + VALUES(seller_count)",
+			"seller_sum = seller_sum + VALUES(seller_sum)",
+			"buyer_count = buyer_count + VALUES(buyer_count)",
+			"buyer_sum = buyer_sum + VALUES(buyer_sum)",
+			"updated_at = VALUES(updated_at)",
+		)
+		if err != nil {
+			return err
+		}
+
+		q := tx.
+			Select(reputationColumns...).
+			From(dbmodel.Reputations).
+			Where(sqlz.Eq("user_uuid", feedback.SubjectUuid))
+
+		utils.New().DebugSelect(q, "reputation")
+
+		if err = q.GetRow(&result); err != nil {
+			return err
+		}
+		result.Summarize()
+
+		return nil
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.CreateFeedback failed creating feedback", zap.String("auction", feedback.AuctionUuid), zap.Error(err))
+		return model.Reputation{}, err
+	}
+
+	return result, nil
+}
+
+// ListFeedback - this method queries a page of the feedback of an auction or of a rated user, newest first,
+// with the number of feedback matching the input
+func (r *AuctionRepository) ListFeedback(input model.FeedbackListInput) (model.FeedbackList, error) {
+	var result model.FeedbackList
+	var conditions []sqlz.WhereCondition
+
+	if input.AuctionUuid != "" {
+		conditions = append(conditions, sqlz.Eq("auction_uuid", input.AuctionUuid))
+	}
+
+	if input.SubjectUuid != "" {
+		conditions = append(conditions, sqlz.Eq("subject_uuid", input.SubjectUuid))
+	}
+
+	q := r.db.
+		Select(feedbackColumns...).
+		From(dbmodel.Feedback).
+		Where(conditions...).
+		OrderBy(sqlz.Desc("id")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list feedback")
+
+	if err := q.GetAll(&result.Feedback); err != nil {
+		r.logger.Error("AuctionRepository.ListFeedback failed listing feedback", zap.Any("input", input), zap.Error(err))
+		return model.FeedbackList{}, err
+	}
+
+	total, err := r.db.
+		Select("*").
+		From(dbmodel.Feedback).
+		Where(conditions...).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.ListFeedback failed counting feedback", zap.Any("input", input), zap.Error(err))
+		return model.FeedbackList{}, err
+	}
+	result.Total = total
+
+	return result, nil
+}
+
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == duplicateEntry
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+func sellerFeedback(now time.Time) model.Feedback {
+	return model.Feedback{
+		Uuid:        "feedback-uuid",
+		AuctionUuid: "auction-uuid",
+		AuthorUuid:  "buyer-uuid",
+		SubjectUuid: "seller-uuid",
+		SubjectRole: model.Seller,
+		Rating:      5,
+		CreatedAt:   now,
+	}
+}
+
+func TestAuctionRepository_CreateFeedback(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("INSERT INTO feedback").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("INSERT INTO reputations \\(buyer_count, buyer_sum, seller_count, seller_sum, updated_at, user_uuid\\) VALUES (.+) ON DUPLICATE KEY UPDATE seller_count = seller_count \\+ VALUES\\(seller_count\\), seller_sum = seller_sum \\+ VALUES\\(seller_sum\\)").
+		WithArgs(0, 0, 1, 5, now, "seller-uuid").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockSql.ExpectQuery("SELECT (.+) FROM reputations WHERE user_uuid = \\?").
+		WithArgs("seller-uuid").
+		WillReturnRows(sqlmock.NewRows(reputationColumns).AddRow("seller-uuid", 2, 8, 2, 10, now))
+	mockSql.ExpectCommit()
+
+	result, err := repo.CreateFeedback(sellerFeedback(now))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), result.Count)
+	assert.Equal(t, 4.5, result.Score)
+	assert.Equal(t, 4.0, result.SellerScore)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CreateFirstFeedback(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("INSERT INTO feedback").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("INSERT INTO reputations (.+) ON DUPLICATE KEY UPDATE").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectQuery("SELECT (.+) FROM reputations WHERE user_uuid = \\?").
+		WithArgs("seller-uuid").
+		WillReturnRows(sqlmock.NewRows(reputationColumns).AddRow("seller-uuid", 1, 5, 0, 0, now))
+	mockSql.ExpectCommit()
+
+	result, err := repo.CreateFeedback(sellerFeedback(now))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "seller-uuid", result.UserUuid)
+	assert.Equal(t, int64(1), result.SellerCount)
+	assert.Equal(t, 5.0, result.Score)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CreateFeedbackTwice(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("INSERT INTO feedback").
+		WillReturnError(&mysql.MySQLError{Number: duplicateEntry, Message: "Duplicate entry"})
+	mockSql.ExpectRollback()
+
+	_, err = repo.CreateFeedback(sellerFeedback(time.Now()))
+
+	assert.ErrorIs(t, err, model.ErrFeedbackExists)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
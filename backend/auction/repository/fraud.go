@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+	fraudmodel "github.com/ireuven89/hello-world/backend/fraud/model"
+)
+
+// signal is a reserved word in mysql
+const signalColumn = "`signal`"
+
+var flagColumns = []string{
+	"id",
+	"uuid",
+	"subject",
+	"subject_uuid",
+	signalColumn,
+	"score",
+	"details",
+	"status",
+	"reviewer_uuid",
+	"created_at",
+	"updated_at",
+	"reviewed_at",
+}
+
+var bidRecordColumns = []string{
+	"b.auction_uuid",
+	"a.user_uuid AS seller_uuid",
+	"a.winner_uuid",
+	fmt.Sprintf("a.status IN (%d, %d) AS closed", model.Sold, model.Expired),
+	"b.bidder_uuid",
+	"b.amount",
+	"b.ip",
+	"b.created_at",
+}
+
+// ActiveSellers - this method queries the sellers of the auctions which got a bid or changed, a closed auction
+// included, since the given time
+func (r *AuctionRepository) ActiveSellers(since time.Time) ([]string, error) {
+	var result []string
+
+	newBids := r.db.
+		Select("1").
+		From(dbmodel.Bids+" b").
+		Where(sqlz.Eq("b.auction_uuid", sqlz.Indirect("a.uuid")), sqlz.Gte("b.created_at", since))
+
+	q := r.db.
+		Select("a.user_uuid").
+		Distinct().
+		From(dbmodel.Auctions + " a").
+		Where(sqlz.Or(sqlz.Gte("a.updated_at", since), sqlz.Exists(newBids)))
+
+	utils.New().DebugSelect(q, "active sellers")
+
+	if err := q.GetAll(&result); err != nil {
+		r.logger.Error("AuctionRepository.ActiveSellers failed listing sellers", zap.Time("since", since), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BidRecords - this method queries the bids placed since the given time on the auctions of the sellers and
+// by the given bidders of the sellers, with the seller, the winner and whether their auction is closed, oldest bid first
+func (r *AuctionRepository) BidRecords(since time.Time, sellers []string, bidders []string) ([]fraudmodel.BidRecord, error) {
+	var result []fraudmodel.BidRecord
+
+	if len(sellers) == 0 {
+		return nil, nil
+	}
+
+	uuids := make([]interface{}, 0, len(sellers))
+	for _, seller := range sellers {
+		uuids = append(uuids, seller)
+	}
+
+	placed := []sqlz.WhereCondition{sqlz.In("a.user_uuid", uuids...)}
+	if len(bidders) > 0 {
+		bidderUuids := make([]interface{}, 0, len(bidders))
+		for _, bidder := range bidders {
+			bidderUuids = append(bidderUuids, bidder)
+		}
+		placed = append(placed, sqlz.In("b.bidder_uuid", bidderUuids...))
+	}
+
+	q := r.db.
+		Select(bidRecordColumns...).
+		From(dbmodel.Bids+" b").
+		InnerJoin(dbmodel.Auctions+" a", sqlz.Eq("a.uuid", sqlz.Indirect("b.auction_uuid"))).
+		Where(
+			sqlz.Gte("b.created_at", since),
+			sqlz.Or(placed...),
+		).
+		OrderBy(sqlz.Asc("b.id"))
+
+	utils.New().DebugSelect(q, "bid records")
+
+	if err := q.GetAll(&result); err != nil {
+		r.logger.Error("AuctionRepository.BidRecords failed listing bids", zap.Time("since", since), zap.Int("sellers", len(sellers)), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SaveFindings - opens a flag for every new finding and raises the score and the details of the open flags.
+// a scan sees the bids of the active sellers only, so an open flag keeps a higher score an earlier scan found,
+// the flags a reviewer already confirmed or dismissed are left as they are
+func (r *AuctionRepository) SaveFindings(findings []fraudmodel.Finding) error {
+	now := time.Now()
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		for _, finding := range findings {
+			var existing fraudmodel.Flag
+
+			q := tx.
+				Select(flagColumns...).
+				From(dbmodel.FraudFlags).
+				Where(
+					sqlz.Eq("subject", finding.Subject),
+					sqlz.Eq("subject_uuid", finding.SubjectUuid),
+					sqlz.Eq(signalColumn, finding.Signal),
+				).
+				Lock(sqlz.ForUpdate())
+
+			utils.New().DebugSelect(q, "lock fraud flag")
+
+			err := q.GetRow(&existing)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			if err == nil {
+				if existing.Status != fraudmodel.Open || existing.Score > finding.Score {
+					continue
+				}
+
+				update := tx.
+					Update(dbmodel.FraudFlags).
+					Set("score", finding.Score).
+					Set("details", finding.Details).
+					Set("updated_at", now).
+					Where(sqlz.Eq("id", existing.ID))
+
+				utils.New().DebugUpdate(update, "update fraud flag")
+
+				if _, err = update.Exec(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			insert := tx.InsertInto(dbmodel.FraudFlags).
+				ValueMap(map[string]interface{}{
+					"uuid":         uuid.New().String(),
+					"subject":      finding.Subject,
+					"subject_uuid": finding.SubjectUuid,
+					signalColumn:   finding.Signal,
+					"score":        finding.Score,
+					"details":      finding.Details,
+					"status":       fraudmodel.Open,
+					"created_at":   now,
+					"updated_at":   now,
+				})
+
+			utils.New().DebugInsert(insert, "insert fraud flag")
+
+			if _, err = insert.Exec(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.SaveFindings failed saving findings", zap.Int("findings", len(findings)), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListFlags - this method queries a page of the flags matching the input, newest first, with the number of
+// flags matching it
+func (r *AuctionRepository) ListFlags(input fraudmodel.FlagListInput) (fraudmodel.FlagList, error) {
+	var result fraudmodel.FlagList
+	var conditions []sqlz.WhereCondition
+
+	if input.Subject != "" {
+		conditions = append(conditions, sqlz.Eq("subject", input.Subject))
+	}
+
+	if input.SubjectUuid != "" {
+		conditions = append(conditions, sqlz.Eq("subject_uuid", input.SubjectUuid))
+	}
+
+	if input.Status != "" {
+		conditions = append(conditions, sqlz.Eq("status", input.Status))
+	}
+
+	q := r.db.
+		Select(flagColumns...).
+		From(dbmodel.FraudFlags).
+		Where(conditions...).
+		OrderBy(sqlz.Desc("id")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list fraud flags")
+
+	if err := q.GetAll(&result.Flags); err != nil {
+		r.logger.Error("AuctionRepository.ListFlags failed listing flags", zap.Any("input", input), zap.Error(err))
+		return fraudmodel.FlagList{}, err
+	}
+
+	total, err := r.db.
+		Select("*").
+		From(dbmodel.FraudFlags).
+		Where(conditions...).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.ListFlags failed counting flags", zap.Any("input", input), zap.Error(err))
+		return fraudmodel.FlagList{}, err
+	}
+	result.Total = total
+
+	return result, nil
+}
+
+// ReviewFlag - sets the status the reviewer decided on the flag
+func (r *AuctionRepository) ReviewFlag(input fraudmodel.ReviewInput) (fraudmodel.Flag, error) {
+	var result fraudmodel.Flag
+	now := time.Now()
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.
+			Select(flagColumns...).
+			From(dbmodel.FraudFlags).
+			Where(sqlz.Eq("uuid", input.FlagUuid)).
+			Lock(sqlz.ForUpdate())
+
+		utils.New().DebugSelect(q, "lock fraud flag")
+
+		if err := q.GetRow(&result); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fraudmodel.ErrNotFound
+			}
+			return err
+		}
+
+		update := tx.
+			Update(dbmodel.FraudFlags).
+			Set("status", input.Status).
+			Set("reviewer_uuid", input.ActorUuid).
+			Set("reviewed_at", now).
+			Set("updated_at", now).
+			Where(sqlz.Eq("id", result.ID))
+
+		utils.New().DebugUpdate(update, "review fraud flag")
+
+		if _, err := update.Exec(); err != nil {
+			return err
+		}
+
+		result.Status = input.Status
+		result.ReviewerUuid = input.ActorUuid
+		result.ReviewedAt = &now
+		result.UpdatedAt = now
+
+		return nil
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.ReviewFlag failed reviewing flag", zap.Any("input", input), zap.Error(err))
+		return fraudmodel.Flag{}, err
+	}
+
+	return result, nil
+}
+
+// Flagged - this method reports whether a reviewer confirmed a flag of the bidder, an open flag is a suspicion
+// only and does not count
+func (r *AuctionRepository) Flagged(bidderUuid string) (bool, error) {
+	count, err := r.db.
+		Select("*").
+		From(dbmodel.FraudFlags).
+		Where(
+			sqlz.Eq("subject", fraudmodel.BidderSubject),
+			sqlz.Eq("subject_uuid", bidderUuid),
+			sqlz.Eq("status", fraudmodel.Confirmed),
+		).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.Flagged failed counting flags", zap.String("bidder", bidderUuid), zap.Error(err))
+		return false, err
+	}
+
+	return count > 0, nil
+}
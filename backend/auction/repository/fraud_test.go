@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	fraudmodel "github.com/ireuven89/hello-world/backend/fraud/model"
+)
+
+func flagRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "uuid", "subject", "subject_uuid", "signal", "score", "details", "status", "reviewer_uuid", "created_at", "updated_at", "reviewed_at"})
+}
+
+func TestAuctionRepository_ActiveSellers(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	since := time.Now().Add(-time.Hour)
+
+	mockSql.ExpectQuery("SELECT DISTINCT a.user_uuid FROM auctions a WHERE a.updated_at >= \\? OR EXISTS \\(SELECT 1 FROM bids b WHERE b.auction_uuid = a.uuid AND b.created_at >= \\?\\)").
+		WithArgs(since, since).
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid"}).AddRow("seller-uuid").AddRow("other-seller"))
+
+	result, err := repo.ActiveSellers(since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"seller-uuid", "other-seller"}, result)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_BidRecords(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	since := time.Now().Add(-time.Hour)
+
+	mockSql.ExpectQuery("SELECT b.auction_uuid, a.user_uuid AS seller_uuid, a.winner_uuid, a.status IN \\(1, 2\\) AS closed, (.+) FROM bids b INNER JOIN auctions a ON a.uuid = b.auction_uuid WHERE b.created_at >= \\? AND \\(a.user_uuid IN \\(\\?\\) OR b.bidder_uuid IN \\(\\?\\)\\) ORDER BY b.id ASC").
+		WithArgs(since, "seller-uuid", "seller-bidder").
+		WillReturnRows(sqlmock.NewRows([]string{"auction_uuid", "seller_uuid", "winner_uuid", "closed", "bidder_uuid", "amount", "ip", "created_at"}).
+			AddRow("auction-uuid", "seller-uuid", "", false, "bidder-uuid", 150, "10.0.0.1", since))
+
+	result, err := repo.BidRecords(since, []string{"seller-uuid"}, []string{"seller-bidder"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []fraudmodel.BidRecord{{
+		AuctionUuid: "auction-uuid", SellerUuid: "seller-uuid", BidderUuid: "bidder-uuid", Amount: 150, IP: "10.0.0.1", CreatedAt: since,
+	}}, result)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_SaveFindings(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	findings := []fraudmodel.Finding{
+		{Subject: fraudmodel.BidderSubject, SubjectUuid: "new-uuid", Signal: fraudmodel.BidUp, Score: 50, Details: "new"},
+		{Subject: fraudmodel.BidderSubject, SubjectUuid: "open-uuid", Signal: fraudmodel.BidUp, Score: 60, Details: "again"},
+		{Subject: fraudmodel.BidderSubject, SubjectUuid: "dismissed-uuid", Signal: fraudmodel.BidUp, Score: 50, Details: "again"},
+		{Subject: fraudmodel.BidderSubject, SubjectUuid: "higher-uuid", Signal: fraudmodel.BidUp, Score: 50, Details: "partial"},
+	}
+
+	lock := "SELECT (.+) FROM fraud_flags WHERE subject = \\? AND subject_uuid = \\? AND `signal` = \\? FOR UPDATE"
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery(lock).
+		WithArgs(fraudmodel.BidderSubject, "new-uuid", fraudmodel.BidUp).
+		WillReturnRows(flagRows())
+	mockSql.ExpectExec("INSERT INTO fraud_flags").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectQuery(lock).
+		WithArgs(fraudmodel.BidderSubject, "open-uuid", fraudmodel.BidUp).
+		WillReturnRows(flagRows().AddRow(2, "flag-open", "bidder", "open-uuid", "bid_up", 50, "before", "open", "", now, now, nil))
+	mockSql.ExpectExec("UPDATE fraud_flags SET (.+) WHERE id = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectQuery(lock).
+		WithArgs(fraudmodel.BidderSubject, "dismissed-uuid", fraudmodel.BidUp).
+		WillReturnRows(flagRows().AddRow(3, "flag-dismissed", "bidder", "dismissed-uuid", "bid_up", 50, "before", "dismissed", "admin-uuid", now, now, now))
+	mockSql.ExpectQuery(lock).
+		WithArgs(fraudmodel.BidderSubject, "higher-uuid", fraudmodel.BidUp).
+		WillReturnRows(flagRows().AddRow(4, "flag-higher", "bidder", "higher-uuid", "bid_up", 100, "before", "open", "", now, now, nil))
+	mockSql.ExpectCommit()
+
+	err = repo.SaveFindings(findings)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_ReviewFlag(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM fraud_flags WHERE uuid = \\? FOR UPDATE").
+		WithArgs("flag-uuid").
+		WillReturnRows(flagRows().AddRow(2, "flag-uuid", "bidder", "bidder-uuid", "bid_up", 50, "details", "open", "", now, now, nil))
+	mockSql.ExpectExec("UPDATE fraud_flags SET (.+) WHERE id = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	result, err := repo.ReviewFlag(fraudmodel.ReviewInput{FlagUuid: "flag-uuid", ActorUuid: "admin-uuid", Status: fraudmodel.Dismissed})
+
+	assert.NoError(t, err)
+	assert.Equal(t, fraudmodel.Dismissed, result.Status)
+	assert.Equal(t, "admin-uuid", result.ReviewerUuid)
+	assert.NotNil(t, result.ReviewedAt)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_ReviewFlagNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM fraud_flags WHERE uuid = \\? FOR UPDATE").
+		WithArgs("flag-uuid").
+		WillReturnRows(flagRows())
+	mockSql.ExpectRollback()
+
+	_, err = repo.ReviewFlag(fraudmodel.ReviewInput{FlagUuid: "flag-uuid", ActorUuid: "admin-uuid", Status: fraudmodel.Dismissed})
+
+	assert.ErrorIs(t, err, fraudmodel.ErrNotFound)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_Flagged(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT COUNT(.+) FROM fraud_flags WHERE subject = \\? AND subject_uuid = \\? AND status = \\?").
+		WithArgs(fraudmodel.BidderSubject, "bidder-uuid", fraudmodel.Confirmed).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	result, err := repo.Flagged("bidder-uuid")
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+)
+
+var incrementTableColumns = []string{
+	"id",
+	"uuid",
+	"name",
+	"category",
+	"created_at",
+}
+
+// CreateIncrementTable - inserts the table and its bands in band order
+func (r *AuctionRepository) CreateIncrementTable(input model.IncrementTableInput) (string, error) {
+	id := uuid.New().String()
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.InsertInto(dbmodel.IncrementTables).
+			ValueMap(map[string]interface{}{
+				"uuid":       id,
+				"name":       input.Name,
+				"category":   input.Category,
+				"created_at": time.Now(),
+			})
+
+		utils.New().DebugInsert(q, "insert increment table")
+
+		if _, err := q.Exec(); err != nil {
+			return err
+		}
+
+		bands := make([][]interface{}, 0, len(input.Bands))
+		for i, band := range input.Bands {
+			bands = append(bands, []interface{}{id, i, band.Below, band.Increment})
+		}
+
+		insert := tx.InsertInto(dbmodel.IncrementBands).
+			Columns("table_uuid", "position", "below", "increment").
+			ValueMultiple(bands)
+
+		utils.New().DebugInsert(insert, "insert increment bands")
+
+		_, err := insert.Exec()
+
+		return err
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.CreateIncrementTable failed creating increment table", zap.Error(err))
+		return "", err
+	}
+
+	return id, nil
+}
+
+// IncrementTable - this method queries an increment table with its bands
+func (r *AuctionRepository) IncrementTable(uuid string) (model.IncrementTable, error) {
+	q := r.db.
+		Select(incrementTableColumns...).
+		From(dbmodel.IncrementTables).
+		Where(sqlz.Eq("uuid", uuid))
+
+	return r.incrementTable(q)
+}
+
+// CategoryIncrementTable - this method queries the latest increment table of a category with its bands
+func (r *AuctionRepository) CategoryIncrementTable(category string) (model.IncrementTable, error) {
+	q := r.db.
+		Select(incrementTableColumns...).
+		From(dbmodel.IncrementTables).
+		Where(sqlz.Eq("category", category)).
+		OrderBy(sqlz.Desc("id")).
+		Limit(1)
+
+	return r.incrementTable(q)
+}
+
+func (r *AuctionRepository) incrementTable(q *sqlz.SelectStmt) (model.IncrementTable, error) {
+	var result model.IncrementTable
+
+	utils.New().DebugSelect(q, "get increment table")
+
+	if err := q.GetRow(&result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.IncrementTable{}, model.ErrNotFound
+		}
+		r.logger.Error("AuctionRepository.incrementTable failed finding increment table", zap.Error(err))
+		return model.IncrementTable{}, err
+	}
+
+	bands := r.db.
+		Select("below", "increment").
+		From(dbmodel.IncrementBands).
+		Where(sqlz.Eq("table_uuid", result.Uuid)).
+		OrderBy(sqlz.Asc("position"))
+
+	utils.New().DebugSelect(bands, "list increment bands")
+
+	if err := bands.GetAll(&result.Bands); err != nil {
+		r.logger.Error("AuctionRepository.incrementTable failed listing increment bands", zap.Error(err))
+		return model.IncrementTable{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+)
+
+var auctionEventColumns = []string{
+	"id",
+	"uuid",
+	"title",
+	"user_uuid",
+	"first_lot_closes_at",
+	"stagger_seconds",
+	"lots_count",
+	"created_at",
+	"updated_at",
+}
+
+// CreateEvent - inserts the auction event together with an auction for each of its lots in a single transaction
+func (r *AuctionRepository) CreateEvent(input model.AuctionEventInput, lots []model.AuctionInput) (string, error) {
+	id := uuid.New().String()
+	now := time.Now()
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.InsertInto(dbmodel.AuctionEvents).
+			ValueMap(map[string]interface{}{
+				"uuid":                id,
+				"title":               input.Title,
+				"user_uuid":           input.UserUuid,
+				"first_lot_closes_at": input.FirstLotClosesAt,
+				"stagger_seconds":     input.StaggerSeconds,
+				"lots_count":          len(lots),
+				"created_at":          now,
+				"updated_at":          now,
+			})
+
+		utils.New().DebugInsert(q, "insert auction event")
+
+		if _, err := q.Exec(); err != nil {
+			return err
+		}
+
+		for _, lot := range lots {
+			lot.EventUuid = id
+
+			insert := tx.InsertInto(dbmodel.Auctions).
+				ValueMap(auctionValues(uuid.New().String(), lot, now))
+
+			utils.New().DebugInsert(insert, "insert lot")
+
+			if _, err := insert.Exec(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.CreateEvent failed creating auction event", zap.Error(err))
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Catalogue - this method queries an auction event and all of its lots in lot order
+func (r *AuctionRepository) Catalogue(uuid string) (model.Catalogue, error) {
+	var result model.Catalogue
+
+	q := r.db.
+		Select(auctionEventColumns...).
+		From(dbmodel.AuctionEvents).
+		Where(sqlz.Eq("uuid", uuid))
+
+	utils.New().DebugSelect(q, "get auction event")
+
+	if err := q.GetRow(&result.Event); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Catalogue{}, model.ErrNotFound
+		}
+		r.logger.Error("AuctionRepository.Catalogue failed finding auction event", zap.Error(err))
+		return model.Catalogue{}, err
+	}
+
+	lots := r.db.
+		Select(auctionColumns...).
+		From(dbmodel.Auctions).
+		Where(sqlz.Eq("event_uuid", uuid)).
+		OrderBy(sqlz.Asc("lot_number"))
+
+	utils.New().DebugSelect(lots, "list lots")
+
+	if err := lots.GetAll(&result.Lots); err != nil {
+		r.logger.Error("AuctionRepository.Catalogue failed listing lots", zap.Error(err))
+		return model.Catalogue{}, err
+	}
+
+	return result, nil
+}
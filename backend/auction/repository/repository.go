@@ -0,0 +1,786 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/bider"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// auctionColumns - the prices of an auction share its currency column
+var auctionColumns = slices.Concat(
+	[]string{"id", "uuid", "item"},
+	money.ColumnsIn("price", "currency"),
+	money.ColumnsIn("winning_price", "currency"),
+	[]string{
+		"winner_uuid",
+		"user_uuid",
+		"bidders_count",
+		"bidders_threshold",
+		"created_at",
+		"updated_at",
+		"expired_at",
+		"original_expired_at",
+		"sold_at",
+		"status",
+		"type",
+	},
+	money.ColumnsIn("floor_price", "currency"),
+	money.ColumnsIn("price_step", "currency"),
+	[]string{"step_interval_seconds"},
+	money.ColumnsIn("reserve_price", "currency"),
+	money.ColumnsIn("buy_now_price", "currency"),
+	[]string{
+		"event_uuid",
+		"lot_number",
+		"item_uuid",
+		"parent_uuid",
+		"origin",
+		"relist_count",
+		"category",
+		"increment_table_uuid",
+	},
+)
+
+var historyColumns = []string{
+	"b.id",
+	"b.uuid",
+	"b.auction_uuid",
+	"b.bidder_uuid",
+	"b.amount",
+	"b.proxy",
+	"r.id IS NOT NULL AS retracted",
+	"b.created_at",
+}
+
+var proxyBidColumns = []string{
+	"id",
+	"auction_uuid",
+	"bidder_uuid",
+	"max_amount",
+	"created_at",
+	"updated_at",
+}
+
+type AuctionRepository struct {
+	db     *sqlz.DB
+	logger *zap.Logger
+}
+
+func New(db *sqlz.DB, logger *zap.Logger) *AuctionRepository {
+
+	return &AuctionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// List - this method queries auctions from DB
+func (r *AuctionRepository) List(input model.ListInput) ([]model.Auction, error) {
+	var result []model.Auction
+	var where []sqlz.WhereCondition
+
+	if input.Item != "" {
+		where = append(where, sqlz.Eq("item", input.Item))
+	}
+
+	if input.ItemUuid != "" {
+		where = append(where, sqlz.Eq("item_uuid", input.ItemUuid))
+	}
+
+	if input.UserUuid != "" {
+		where = append(where, sqlz.Eq("user_uuid", input.UserUuid))
+	}
+
+	if input.Status != nil {
+		where = append(where, sqlz.Eq("status", *input.Status))
+	}
+
+	q := r.db.
+		Select(auctionColumns...).
+		From(dbmodel.Auctions).
+		Where(where...).
+		OrderBy(sqlz.Desc("created_at")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list auctions")
+
+	if err := q.GetAll(&result); err != nil {
+		r.logger.Error("AuctionRepository.List failed listing auctions", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Single - this method queries a single auction from DB
+func (r *AuctionRepository) Single(uuid string) (model.Auction, error) {
+	var result model.Auction
+
+	q := r.db.
+		Select(auctionColumns...).
+		From(dbmodel.Auctions).
+		Where(sqlz.Eq("uuid", uuid))
+
+	utils.New().DebugSelect(q, "single auction")
+
+	if err := q.GetRow(&result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Auction{}, model.ErrNotFound
+		}
+		r.logger.Error("AuctionRepository.Single failed finding auction", zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	return result, nil
+}
+
+// Create - this method inserts a new auction and returns its uuid, an auction is relisted once
+// so a second relist of the same parent hits the unique key on relisted_from
+func (r *AuctionRepository) Create(input model.AuctionInput) (string, error) {
+	id := uuid.New().String()
+
+	q := r.db.InsertInto(dbmodel.Auctions).
+		ValueMap(auctionValues(id, input, time.Now()))
+
+	utils.New().DebugInsert(q, "insert auction")
+
+	if _, err := q.Exec(); err != nil {
+		if input.Origin == model.OriginRelist && isDuplicate(err) {
+			return "", model.ErrRelisted
+		}
+		r.logger.Error("AuctionRepository.Create failed creating auction", zap.Error(err))
+		return "", err
+	}
+
+	return id, nil
+}
+
+func auctionValues(id string, input model.AuctionInput, now time.Time) map[string]interface{} {
+
+	return map[string]interface{}{
+		"uuid":                  id,
+		"item":                  input.Item,
+		"price":                 input.Price.Amount,
+		"user_uuid":             input.UserUuid,
+		"bidders_threshold":     input.BiddersThreshold,
+		"expired_at":            input.ExpiredAt,
+		"original_expired_at":   input.ExpiredAt,
+		"status":                model.InProgress,
+		"type":                  input.Type,
+		"floor_price":           input.FloorPrice.Amount,
+		"price_step":            input.PriceStep.Amount,
+		"step_interval_seconds": input.StepIntervalSeconds,
+		"reserve_price":         input.ReservePrice.Amount,
+		"buy_now_price":         input.BuyNowPrice.Amount,
+		"event_uuid":            input.EventUuid,
+		"lot_number":            input.LotNumber,
+		"item_uuid":             input.ItemUuid,
+		"parent_uuid":           input.ParentUuid,
+		"origin":                input.Origin,
+		"relist_count":          input.RelistCount,
+		"category":              input.Category,
+		"increment_table_uuid":  input.IncrementTableUuid,
+		"currency":              input.Currency(),
+		"created_at":            now,
+		"updated_at":            now,
+	}
+}
+
+// Update - this method updates an auction which is still in progress, its prices only while it has no bids
+func (r *AuctionRepository) Update(input model.AuctionInput) error {
+	valuesMap := setValuesMap(input)
+	valuesMap["updated_at"] = time.Now()
+
+	conditions := []sqlz.WhereCondition{sqlz.Eq("uuid", input.Uuid), sqlz.Eq("status", model.InProgress)}
+	if input.ChangesPrices() {
+		conditions = append(conditions, sqlz.Eq("bidders_count", 0))
+	}
+
+	q := r.db.
+		Update(dbmodel.Auctions).
+		SetMap(valuesMap).
+		Where(conditions...)
+
+	utils.New().DebugUpdate(q, "update auction")
+
+	res, err := q.Exec()
+	if err != nil {
+		r.logger.Error("AuctionRepository.Update failed updating auction", zap.Error(err))
+		return err
+	}
+
+	return r.checkAffected(res, input.Uuid)
+}
+
+// Cancel - this method moves an auction in progress to the cancelled status
+func (r *AuctionRepository) Cancel(uuid string) error {
+	q := r.db.
+		Update(dbmodel.Auctions).
+		SetMap(map[string]interface{}{
+			"status":     model.Cancelled,
+			"updated_at": time.Now(),
+		}).
+		Where(sqlz.Eq("uuid", uuid), sqlz.Eq("status", model.InProgress))
+
+	utils.New().DebugUpdate(q, "cancel auction")
+
+	res, err := q.Exec()
+	if err != nil {
+		r.logger.Error("AuctionRepository.Cancel failed cancelling auction", zap.Error(err))
+		return err
+	}
+
+	return r.checkAffected(res, uuid)
+}
+
+// PlaceBid - locks the auction row and the credit of the bidder, lets place apply the bid against the auction
+// and its proxy bids, checks the bidder's commitment against its credit, then persists the auction, the new bids
+// and the proxy maximum in the same transaction.
+// the auction is locked first and the credit second, every transaction taking both locks takes them in that order.
+// the credit is locked before the exposure is read so a bid of the same bidder on another auction, which holds
+// the lock, is committed before this transaction reads the bidder's exposure
+func (r *AuctionRepository) PlaceBid(auctionUuid string, bidderUuid string, place func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error)) (model.Auction, error) {
+	var result model.Auction
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		if err := lockAuction(tx, auctionUuid, &result); err != nil {
+			return err
+		}
+
+		credit, err := loadCredit(tx, bidderUuid)
+		if err != nil {
+			return err
+		}
+
+		proxies, err := loadProxies(tx, auctionUuid)
+		if err != nil {
+			return err
+		}
+
+		placement, err := place(&result, proxies)
+		if err != nil {
+			return err
+		}
+
+		if placement.Commitment != nil && credit.Limited() {
+			committed, err := exposure(tx, bidderUuid, credit.Currency(), auctionUuid)
+			if err != nil {
+				return err
+			}
+
+			if err = bider.CheckCredit(credit, committed, *placement.Commitment); err != nil {
+				return err
+			}
+		}
+
+		return savePlacement(tx, &result, placement, time.Now())
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.PlaceBid failed placing bid", zap.String("uuid", auctionUuid), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	return result, nil
+}
+
+// RetractBids - locks the auction row, lets retract pick the bids to retract out of the active bid history
+// and recompute the auction, then persists the retractions and the recomputed auction in the same transaction
+func (r *AuctionRepository) RetractBids(auctionUuid string, retract func(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid) (model.Placement, error)) (model.Auction, error) {
+	var result model.Auction
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		if err := lockAuction(tx, auctionUuid, &result); err != nil {
+			return err
+		}
+
+		bids, err := activeBids(tx, auctionUuid)
+		if err != nil {
+			return err
+		}
+
+		proxies, err := loadProxies(tx, auctionUuid)
+		if err != nil {
+			return err
+		}
+
+		placement, err := retract(&result, bids, proxies)
+		if err != nil {
+			return err
+		}
+
+		return savePlacement(tx, &result, placement, time.Now())
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.RetractBids failed retracting bids", zap.String("uuid", auctionUuid), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	return result, nil
+}
+
+func lockAuction(tx *sqlz.Tx, auctionUuid string, into *model.Auction) error {
+	q := tx.
+		Select(auctionColumns...).
+		From(dbmodel.Auctions).
+		Where(sqlz.Eq("uuid", auctionUuid)).
+		Lock(sqlz.ForUpdate())
+
+	utils.New().DebugSelect(q, "lock auction")
+
+	if err := q.GetRow(into); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func loadProxies(tx *sqlz.Tx, auctionUuid string) ([]bidermodel.ProxyBid, error) {
+	var proxies []bidermodel.ProxyBid
+
+	q := tx.
+		Select(proxyBidColumns...).
+		From(dbmodel.ProxyBids).
+		Where(sqlz.Eq("auction_uuid", auctionUuid))
+
+	utils.New().DebugSelect(q, "auction proxy bids")
+
+	if err := q.GetAll(&proxies); err != nil {
+		return nil, err
+	}
+
+	return proxies, nil
+}
+
+// activeBids - the bids of the auction which were not retracted, oldest first
+func activeBids(tx *sqlz.Tx, auctionUuid string) ([]model.Bid, error) {
+	var bids []model.Bid
+
+	q := tx.
+		Select(historyColumns...).
+		From(dbmodel.Bids+" b").
+		LeftJoin(dbmodel.BidRetractions+" r", sqlz.Eq("r.bid_uuid", sqlz.Indirect("b.uuid"))).
+		Where(sqlz.Eq("b.auction_uuid", auctionUuid), sqlz.IsNull("r.id")).
+		OrderBy(sqlz.Asc("b.id"))
+
+	utils.New().DebugSelect(q, "active bids")
+
+	if err := q.GetAll(&bids); err != nil {
+		return nil, err
+	}
+
+	return bids, nil
+}
+
+// savePlacement - persists what a placement changed and the auction row, the caller holds the auction lock
+func savePlacement(tx *sqlz.Tx, auction *model.Auction, placement model.Placement, now time.Time) error {
+	if placement.Proxy != nil {
+		if err := saveProxyBid(tx, *placement.Proxy, now); err != nil {
+			return err
+		}
+	}
+
+	if placement.DropProxyOf != "" {
+		drop := tx.
+			DeleteFrom(dbmodel.ProxyBids).
+			Where(sqlz.Eq("auction_uuid", auction.Uuid), sqlz.Eq("bidder_uuid", placement.DropProxyOf))
+
+		if _, err := drop.Exec(); err != nil {
+			return err
+		}
+	}
+
+	for _, bidUuid := range placement.Retracted {
+		insert := tx.InsertInto(dbmodel.BidRetractions).
+			ValueMap(map[string]interface{}{
+				"bid_uuid":   bidUuid,
+				"reason":     placement.Reason,
+				"created_at": now,
+			})
+
+		utils.New().DebugInsert(insert, "insert bid retraction")
+
+		if _, err := insert.Exec(); err != nil {
+			return err
+		}
+	}
+
+	for _, bid := range placement.Bids {
+		insert := tx.InsertInto(dbmodel.Bids).
+			ValueMap(map[string]interface{}{
+				"uuid":         uuid.New().String(),
+				"auction_uuid": auction.Uuid,
+				"bidder_uuid":  bid.BidderUuid,
+				"amount":       bid.Amount,
+				"proxy":        bid.Proxy,
+				"ip":           bid.IP,
+				"created_at":   now,
+			})
+
+		utils.New().DebugInsert(insert, "insert bid")
+
+		if _, err := insert.Exec(); err != nil {
+			return err
+		}
+	}
+
+	count := tx.
+		Select("COUNT(DISTINCT b.bidder_uuid)").
+		From(dbmodel.Bids+" b").
+		LeftJoin(dbmodel.BidRetractions+" r", sqlz.Eq("r.bid_uuid", sqlz.Indirect("b.uuid"))).
+		Where(sqlz.Eq("b.auction_uuid", auction.Uuid), sqlz.IsNull("r.id"))
+
+	if err := count.GetRow(&auction.BiddersCount); err != nil {
+		return err
+	}
+
+	auction.UpdatedAt = now
+	values := map[string]interface{}{
+		"winning_price": auction.WinningPrice.Amount,
+		"winner_uuid":   auction.WinnerUuid,
+		"bidders_count": auction.BiddersCount,
+		"expired_at":    auction.ExpiredAt,
+		"status":        auction.Status,
+		"updated_at":    auction.UpdatedAt,
+	}
+	sold(auction, values, now)
+
+	update := tx.
+		Update(dbmodel.Auctions).
+		SetMap(values).
+		Where(sqlz.Eq("id", auction.ID))
+
+	utils.New().DebugUpdate(update, "update auction bid")
+
+	_, err := update.Exec()
+
+	return err
+}
+
+// saveProxyBid - updates the bidder's maximum on the auction or creates it, the caller holds the auction lock
+func saveProxyBid(tx *sqlz.Tx, proxy bidermodel.ProxyBid, now time.Time) error {
+	update := tx.
+		Update(dbmodel.ProxyBids).
+		SetMap(map[string]interface{}{
+			"max_amount": proxy.MaxAmount,
+			"updated_at": now,
+		}).
+		Where(sqlz.Eq("auction_uuid", proxy.AuctionUuid), sqlz.Eq("bidder_uuid", proxy.BidderUuid))
+
+	utils.New().DebugUpdate(update, "update proxy bid")
+
+	res, err := update.Exec()
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected > 0 {
+		return err
+	}
+
+	insert := tx.InsertInto(dbmodel.ProxyBids).
+		ValueMap(map[string]interface{}{
+			"auction_uuid": proxy.AuctionUuid,
+			"bidder_uuid":  proxy.BidderUuid,
+			"max_amount":   proxy.MaxAmount,
+			"created_at":   now,
+			"updated_at":   now,
+		})
+
+	utils.New().DebugInsert(insert, "insert proxy bid")
+
+	_, err = insert.Exec()
+
+	return err
+}
+
+// ListBids - this method queries a page of the bid history of an auction, oldest bid first,
+// flagging the bids which were retracted
+func (r *AuctionRepository) ListBids(input model.BidListInput) (model.BidHistory, error) {
+	var result model.BidHistory
+
+	q := r.db.
+		Select(historyColumns...).
+		From(dbmodel.Bids+" b").
+		LeftJoin(dbmodel.BidRetractions+" r", sqlz.Eq("r.bid_uuid", sqlz.Indirect("b.uuid"))).
+		Where(sqlz.Eq("b.auction_uuid", input.AuctionUuid)).
+		OrderBy(sqlz.Asc("b.id")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list bids")
+
+	if err := q.GetAll(&result.Bids); err != nil {
+		r.logger.Error("AuctionRepository.ListBids failed listing bids", zap.Error(err))
+		return model.BidHistory{}, err
+	}
+
+	total, err := r.db.
+		Select("*").
+		From(dbmodel.Bids).
+		Where(sqlz.Eq("auction_uuid", input.AuctionUuid)).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.ListBids failed counting bids", zap.Error(err))
+		return model.BidHistory{}, err
+	}
+	result.Total = total
+
+	return result, nil
+}
+
+// CloseExpired - claims auctions in progress which passed their expiry and moves each one to the status chosen by decide,
+// sealed auctions are handed their bids so decide can pick the winner and the price.
+// rows locked by another replica are skipped and the status update is guarded, so every auction is closed exactly once
+func (r *AuctionRepository) CloseExpired(now time.Time, limit int64, decide func(auction *model.Auction, bids []model.Bid) model.Status) ([]model.Auction, error) {
+	var claimed []model.Auction
+	var closed []model.Auction
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.
+			Select(auctionColumns...).
+			From(dbmodel.Auctions).
+			Where(sqlz.Eq("status", model.InProgress), sqlz.Lte("expired_at", now)).
+			OrderBy(sqlz.Asc("expired_at")).
+			Limit(limit).
+			Lock(sqlz.ForUpdate().SkipLocked())
+
+		utils.New().DebugSelect(q, "claim expired auctions")
+
+		if err := q.GetAll(&claimed); err != nil {
+			return err
+		}
+
+		for _, auction := range claimed {
+			var bids []model.Bid
+
+			if auction.Type.Sealed() {
+				var err error
+				if bids, err = activeBids(tx, auction.Uuid); err != nil {
+					return err
+				}
+			}
+
+			auction.Status = decide(&auction, bids)
+			auction.UpdatedAt = now
+
+			values := map[string]interface{}{
+				"status":        auction.Status,
+				"winning_price": auction.WinningPrice.Amount,
+				"winner_uuid":   auction.WinnerUuid,
+				"updated_at":    auction.UpdatedAt,
+			}
+			sold(&auction, values, now)
+
+			update := tx.
+				Update(dbmodel.Auctions).
+				SetMap(values).
+				Where(sqlz.Eq("id", auction.ID), sqlz.Eq("status", model.InProgress))
+
+			utils.New().DebugUpdate(update, "close auction")
+
+			res, err := update.Exec()
+			if err != nil {
+				return err
+			}
+
+			if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+				continue
+			}
+
+			closed = append(closed, auction)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.CloseExpired failed closing auctions", zap.Error(err))
+		return nil, err
+	}
+
+	return closed, nil
+}
+
+// ClaimEndingSoon - claims auctions in progress which end before until and were not announced yet, and marks them
+// as announced so each auction is returned once
+func (r *AuctionRepository) ClaimEndingSoon(now, until time.Time, limit int64) ([]model.Auction, error) {
+	var claimed []model.Auction
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.
+			Select(auctionColumns...).
+			From(dbmodel.Auctions).
+			Where(
+				sqlz.Eq("status", model.InProgress),
+				sqlz.Gt("expired_at", now),
+				sqlz.Lte("expired_at", until),
+				sqlz.IsNull("ending_notified_at"),
+			).
+			OrderBy(sqlz.Asc("expired_at")).
+			Limit(limit).
+			Lock(sqlz.ForUpdate().SkipLocked())
+
+		utils.New().DebugSelect(q, "claim auctions ending soon")
+
+		if err := q.GetAll(&claimed); err != nil {
+			return err
+		}
+
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		ids := make([]interface{}, 0, len(claimed))
+		for _, auction := range claimed {
+			ids = append(ids, auction.ID)
+		}
+
+		update := tx.
+			Update(dbmodel.Auctions).
+			Set("ending_notified_at", now).
+			Where(sqlz.In("id", ids...))
+
+		utils.New().DebugUpdate(update, "mark auctions ending soon")
+
+		_, err := update.Exec()
+
+		return err
+	})
+
+	if err != nil {
+		r.logger.Error("AuctionRepository.ClaimEndingSoon failed claiming auctions", zap.Error(err))
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// checkAffected - tells apart a missing auction from one that is no longer in progress or already has bids
+func (r *AuctionRepository) checkAffected(res sql.Result, uuid string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected > 0 {
+		return nil
+	}
+
+	auction, err := r.Single(uuid)
+	if err != nil {
+		return err
+	}
+
+	if auction.Status == model.InProgress && auction.BiddersCount > 0 {
+		return model.ErrHasBids
+	}
+
+	return model.ErrNotInProgress
+}
+
+func setValuesMap(input model.AuctionInput) map[string]interface{} {
+	valuesMap := map[string]interface{}{}
+
+	if input.Item != "" {
+		valuesMap["item"] = input.Item
+	}
+
+	if input.Price.Amount != 0 {
+		valuesMap["price"] = input.Price.Amount
+	}
+
+	if input.BiddersThreshold != 0 {
+		valuesMap["bidders_threshold"] = input.BiddersThreshold
+	}
+
+	if input.FloorPrice.Amount != 0 {
+		valuesMap["floor_price"] = input.FloorPrice.Amount
+	}
+
+	if input.PriceStep.Amount != 0 {
+		valuesMap["price_step"] = input.PriceStep.Amount
+	}
+
+	if input.StepIntervalSeconds != 0 {
+		valuesMap["step_interval_seconds"] = input.StepIntervalSeconds
+	}
+
+	if input.ReservePrice.Amount != 0 {
+		valuesMap["reserve_price"] = input.ReservePrice.Amount
+	}
+
+	if input.Category != "" {
+		valuesMap["category"] = input.Category
+	}
+
+	if input.IncrementTableUuid != "" {
+		valuesMap["increment_table_uuid"] = input.IncrementTableUuid
+	}
+
+	if input.BuyNowPrice.Amount != 0 {
+		valuesMap["buy_now_price"] = input.BuyNowPrice.Amount
+	}
+
+	if !input.ExpiredAt.IsZero() {
+		valuesMap["expired_at"] = input.ExpiredAt
+		valuesMap["original_expired_at"] = input.ExpiredAt
+	}
+
+	return valuesMap
+}
+
+// sold - stamps the time of the sale on an auction the update sells, the feedback window starts then
+func sold(auction *model.Auction, values map[string]interface{}, now time.Time) {
+	if auction.Status != model.Sold || auction.SoldAt != nil {
+		return
+	}
+
+	auction.SoldAt = &now
+	values["sold_at"] = now
+}
+
+// execer - a db or a transaction to run a raw statement on
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// upsert - runs the insert, a row whose key already exists gets the updates instead. sqlz only writes the
+// ON CONFLICT clause of postgres so the mysql clause is added to the built statement
+func upsert(db execer, insert *sqlz.InsertStmt, updates ...string) error {
+	query, bindings := insert.ToSQL(true)
+
+	_, err := db.Exec(query+" ON DUPLICATE KEY UPDATE "+strings.Join(updates, ", "), bindings...)
+
+	return err
+}
+
+// replace - the updates of an upsert setting the columns to the inserted values
+func replace(columns ...string) []string {
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", column, column))
+	}
+
+	return updates
+}
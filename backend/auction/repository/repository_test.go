@@ -0,0 +1,522 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// auctionRows - the rows of auctionColumns named as they scan, the prices by their aliases
+func auctionRows() *sqlmock.Rows {
+	names := make([]string, 0, len(auctionColumns))
+	for _, column := range auctionColumns {
+		if _, alias, ok := strings.Cut(column, " AS "); ok {
+			column = strings.Trim(alias, "`")
+		}
+		names = append(names, column)
+	}
+
+	return sqlmock.NewRows(names)
+}
+
+func TestAuctionRepository_Single(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	rows := auctionRows().
+		AddRow(1, "mock-uuid", "item", 100, "USD", 0, "USD", "", "user-uuid", 0, 2, now, now, now, now, nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", "")
+	mockSql.ExpectQuery(regexp.QuoteMeta("SELECT id, uuid, item, price AS `price.amount`, currency AS `price.currency`, winning_price AS `winning_price.amount`, currency AS `winning_price.currency`, winner_uuid, user_uuid, bidders_count, bidders_threshold, created_at, updated_at, expired_at, original_expired_at, sold_at, status, type, floor_price AS `floor_price.amount`, currency AS `floor_price.currency`, price_step AS `price_step.amount`, currency AS `price_step.currency`, step_interval_seconds, reserve_price AS `reserve_price.amount`, currency AS `reserve_price.currency`, buy_now_price AS `buy_now_price.amount`, currency AS `buy_now_price.currency`, event_uuid, lot_number, item_uuid, parent_uuid, origin, relist_count, category, increment_table_uuid FROM auctions WHERE uuid = ?")).
+		WithArgs("mock-uuid").
+		WillReturnRows(rows)
+
+	res, err := repo.Single("mock-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mock-uuid", res.Uuid)
+	assert.Equal(t, int64(100), res.Price.Amount)
+	assert.Equal(t, money.USD, res.ReservePrice.Currency)
+	assert.Equal(t, int64(2), res.BiddersThreshold)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_SingleNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions").
+		WithArgs("missing").
+		WillReturnRows(auctionRows())
+
+	_, err = repo.Single("missing")
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CancelNotInProgress(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectExec("UPDATE auctions SET").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions").
+		WithArgs("sold-uuid").
+		WillReturnRows(auctionRows().
+			AddRow(1, "sold-uuid", "item", 100, "USD", 150, "USD", "bidder-uuid", "user-uuid", 3, 2, now, now, now, now, nil, model.Sold, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+
+	err = repo.Cancel("sold-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotInProgress)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_UpdatePricesWithBids(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectExec("UPDATE auctions SET (.+) WHERE uuid = \\? AND status = \\? AND bidders_count = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions").
+		WithArgs("mock-uuid").
+		WillReturnRows(auctionRows().
+			AddRow(1, "mock-uuid", "item", 100, "USD", 150, "USD", "bidder-uuid", "user-uuid", 1, 0, now, now, now, now, nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+
+	err = repo.Update(model.AuctionInput{Uuid: "mock-uuid", ReservePrice: money.Money{Amount: 500}})
+
+	assert.ErrorIs(t, err, model.ErrHasBids)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_PlaceBid(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE uuid = \\? FOR UPDATE").
+		WithArgs("mock-uuid").
+		WillReturnRows(auctionRows().
+			AddRow(7, "mock-uuid", "item", 100, "USD", 0, "USD", "", "user-uuid", 0, 0, now, now, now.Add(time.Hour), now.Add(time.Hour), nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+	mockSql.ExpectQuery("SELECT (.+) FROM bidder_credit WHERE bidder_uuid = \\? FOR UPDATE").
+		WithArgs("bidder-uuid").
+		WillReturnRows(creditRows())
+	mockSql.ExpectQuery("SELECT (.+) FROM proxy_bids WHERE auction_uuid = \\?").
+		WithArgs("mock-uuid").
+		WillReturnRows(sqlmock.NewRows(proxyBidColumns).
+			AddRow(1, "mock-uuid", "proxy-uuid", 300, now, now))
+	mockSql.ExpectExec("INSERT INTO bids").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("INSERT INTO bids").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mockSql.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(DISTINCT b.bidder_uuid) FROM bids b LEFT JOIN bid_retractions r ON r.bid_uuid = b.uuid WHERE b.auction_uuid = ? AND r.id IS NULL")).
+		WithArgs("mock-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mockSql.ExpectExec("UPDATE auctions SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	res, err := repo.PlaceBid("mock-uuid", "bidder-uuid", func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error) {
+		assert.Len(t, proxies, 1)
+		auction.WinningPrice = auction.Money(155)
+		auction.WinnerUuid = "proxy-uuid"
+		return model.Placement{Bids: []model.Bid{
+			{BidderUuid: "bidder-uuid", Amount: 150},
+			{BidderUuid: "proxy-uuid", Amount: 155, Proxy: true},
+		}}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(155), res.WinningPrice.Amount)
+	assert.Equal(t, int64(2), res.BiddersCount)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_PlaceBidRejected(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE uuid = \\? FOR UPDATE").
+		WithArgs("mock-uuid").
+		WillReturnRows(auctionRows().
+			AddRow(7, "mock-uuid", "item", 100, "USD", 0, "USD", "", "user-uuid", 0, 0, now, now, now.Add(time.Hour), now.Add(time.Hour), nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+	mockSql.ExpectQuery("SELECT (.+) FROM bidder_credit WHERE bidder_uuid = \\? FOR UPDATE").
+		WithArgs("bidder-uuid").
+		WillReturnRows(creditRows())
+	mockSql.ExpectQuery("SELECT (.+) FROM proxy_bids").
+		WillReturnRows(sqlmock.NewRows(proxyBidColumns))
+	mockSql.ExpectRollback()
+
+	_, err = repo.PlaceBid("mock-uuid", "bidder-uuid", func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error) {
+		return model.Placement{}, model.ErrBidTooLow
+	})
+
+	assert.ErrorIs(t, err, model.ErrBidTooLow)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_PlaceProxyBidInsertsMaximum(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE uuid = \\? FOR UPDATE").
+		WithArgs("mock-uuid").
+		WillReturnRows(auctionRows().
+			AddRow(7, "mock-uuid", "item", 100, "USD", 0, "USD", "", "user-uuid", 0, 0, now, now, now.Add(time.Hour), now.Add(time.Hour), nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+	mockSql.ExpectQuery("SELECT (.+) FROM bidder_credit WHERE bidder_uuid = \\? FOR UPDATE").
+		WithArgs("bidder-uuid").
+		WillReturnRows(creditRows())
+	mockSql.ExpectQuery("SELECT (.+) FROM proxy_bids").
+		WillReturnRows(sqlmock.NewRows(proxyBidColumns))
+	mockSql.ExpectExec("UPDATE proxy_bids SET").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectExec("INSERT INTO proxy_bids").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("INSERT INTO bids").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectQuery("SELECT COUNT(.+) FROM bids").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mockSql.ExpectExec("UPDATE auctions SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	_, err = repo.PlaceBid("mock-uuid", "bidder-uuid", func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error) {
+		auction.WinningPrice = auction.Money(100)
+		auction.WinnerUuid = "bidder-uuid"
+		return model.Placement{
+			Bids:  []model.Bid{{BidderUuid: "bidder-uuid", Amount: 100, Proxy: true}},
+			Proxy: &bidermodel.ProxyBid{AuctionUuid: "mock-uuid", BidderUuid: "bidder-uuid", MaxAmount: 300},
+		}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CloseExpired(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE status = \\? AND expired_at <= \\? ORDER BY expired_at ASC LIMIT 10 FOR UPDATE SKIP LOCKED").
+		WithArgs(model.InProgress, now).
+		WillReturnRows(auctionRows().
+			AddRow(1, "sold-uuid", "item", 100, "USD", 150, "USD", "bidder-uuid", "user-uuid", 1, 0, now, now, now, now, nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", "").
+			AddRow(2, "raced-uuid", "item", 100, "USD", 0, "USD", "", "user-uuid", 0, 0, now, now, now, now, nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+	mockSql.ExpectExec("UPDATE auctions SET sold_at = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectExec("UPDATE auctions SET").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectCommit()
+
+	closed, err := repo.CloseExpired(now, 10, func(auction *model.Auction, bids []model.Bid) model.Status {
+		return model.Sold
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, closed, 1)
+	assert.Equal(t, "sold-uuid", closed[0].Uuid)
+	assert.Equal(t, model.Sold, closed[0].Status)
+	assert.Equal(t, &now, closed[0].SoldAt)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CloseExpiredSealed(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE status = \\? AND expired_at <= \\?").
+		WillReturnRows(auctionRows().
+			AddRow(1, "sealed-uuid", "item", 100, "USD", 0, "USD", "", "user-uuid", 2, 0, now, now, now, now, nil, model.InProgress, model.SealedFirstPrice, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+	mockSql.ExpectQuery("SELECT (.+) FROM bids b LEFT JOIN bid_retractions r ON r.bid_uuid = b.uuid WHERE b.auction_uuid = \\? AND r.id IS NULL ORDER BY b.id ASC").
+		WithArgs("sealed-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "auction_uuid", "bidder_uuid", "amount", "proxy", "retracted", "created_at"}).
+			AddRow(1, "bid-a", "sealed-uuid", "bidder-a", 150, false, false, now).
+			AddRow(2, "bid-b", "sealed-uuid", "bidder-b", 200, false, false, now))
+	mockSql.ExpectExec("UPDATE auctions SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	closed, err := repo.CloseExpired(now, 10, func(auction *model.Auction, bids []model.Bid) model.Status {
+		assert.Len(t, bids, 2)
+		auction.WinnerUuid = bids[1].BidderUuid
+		auction.WinningPrice = auction.Money(bids[1].Amount)
+		return model.Sold
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, closed, 1)
+	assert.Equal(t, "bidder-b", closed[0].WinnerUuid)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_ClaimEndingSoon(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+	until := now.Add(time.Hour)
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE status = \\? AND expired_at > \\? AND expired_at <= \\? AND ending_notified_at IS NULL ORDER BY expired_at ASC LIMIT 10 FOR UPDATE SKIP LOCKED").
+		WithArgs(model.InProgress, now, until).
+		WillReturnRows(auctionRows().
+			AddRow(1, "ending-uuid", "item", 100, "USD", 150, "USD", "bidder-uuid", "user-uuid", 1, 0, now, now, now, now, nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+	mockSql.ExpectExec("UPDATE auctions SET ending_notified_at = \\? WHERE id IN \\(\\?\\)").
+		WithArgs(now, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	ending, err := repo.ClaimEndingSoon(now, until, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, ending, 1)
+	assert.Equal(t, "ending-uuid", ending[0].Uuid)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_ClaimEndingSoonNothingToClaim(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE").
+		WillReturnRows(auctionRows())
+	mockSql.ExpectCommit()
+
+	ending, err := repo.ClaimEndingSoon(now, now.Add(time.Hour), 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, ending)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_ListBids(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectQuery(regexp.QuoteMeta("SELECT b.id, b.uuid, b.auction_uuid, b.bidder_uuid, b.amount, b.proxy, r.id IS NOT NULL AS retracted, b.created_at FROM bids b LEFT JOIN bid_retractions r ON r.bid_uuid = b.uuid WHERE b.auction_uuid = ? ORDER BY b.id ASC LIMIT 2 OFFSET 2")).
+		WithArgs("auction-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "auction_uuid", "bidder_uuid", "amount", "proxy", "retracted", "created_at"}).
+			AddRow(3, "bid-3", "auction-uuid", "bidder-a", 120, false, true, now).
+			AddRow(4, "bid-4", "auction-uuid", "bidder-b", 130, true, false, now))
+	mockSql.ExpectQuery("SELECT COUNT\\(\\*\\) FROM bids WHERE auction_uuid = \\?").
+		WithArgs("auction-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	res, err := repo.ListBids(model.BidListInput{AuctionUuid: "auction-uuid", Page: model.PageRequest{Offset: 2, Limit: 2}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), res.Total)
+	assert.Len(t, res.Bids, 2)
+	assert.True(t, res.Bids[0].Retracted)
+	assert.True(t, res.Bids[1].Proxy)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_RetractBids(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE uuid = \\? FOR UPDATE").
+		WithArgs("mock-uuid").
+		WillReturnRows(auctionRows().
+			AddRow(7, "mock-uuid", "item", 100, "USD", 150, "USD", "bidder-b", "user-uuid", 2, 0, now, now, now.Add(time.Hour), now.Add(time.Hour), nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "", 0, "", "", "", 0, "", ""))
+	mockSql.ExpectQuery("SELECT (.+) FROM bids b LEFT JOIN bid_retractions r (.+) AND r.id IS NULL").
+		WithArgs("mock-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "auction_uuid", "bidder_uuid", "amount", "proxy", "retracted", "created_at"}).
+			AddRow(1, "bid-1", "mock-uuid", "bidder-a", 100, false, false, now).
+			AddRow(2, "bid-2", "mock-uuid", "bidder-b", 150, false, false, now))
+	mockSql.ExpectQuery("SELECT (.+) FROM proxy_bids").
+		WillReturnRows(sqlmock.NewRows(proxyBidColumns))
+	mockSql.ExpectExec("DELETE FROM proxy_bids WHERE auction_uuid = \\? AND bidder_uuid = \\?").
+		WithArgs("mock-uuid", "bidder-b").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectExec("INSERT INTO bid_retractions").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectQuery("SELECT COUNT(.+) FROM bids b").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mockSql.ExpectExec("UPDATE auctions SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	res, err := repo.RetractBids("mock-uuid", func(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid) (model.Placement, error) {
+		assert.Len(t, bids, 2)
+		auction.WinnerUuid = "bidder-a"
+		auction.WinningPrice = auction.Money(100)
+		return model.Placement{Retracted: []string{"bid-2"}, DropProxyOf: "bidder-b", Reason: "typo"}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bidder-a", res.WinnerUuid)
+	assert.Equal(t, int64(1), res.BiddersCount)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CreateEvent(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("INSERT INTO auction_events").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("INSERT INTO auctions").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("INSERT INTO auctions").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mockSql.ExpectCommit()
+
+	id, err := repo.CreateEvent(model.AuctionEventInput{Title: "estate sale", UserUuid: "seller-uuid"}, []model.AuctionInput{
+		{Item: "lot 1", ItemUuid: "item-a", LotNumber: 1},
+		{Item: "lot 2", ItemUuid: "item-b", LotNumber: 2},
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CreateRelisted(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("INSERT INTO auctions").
+		WillReturnError(&mysql.MySQLError{Number: duplicateEntry, Message: "Duplicate entry for key 'auctions_relisted_from'"})
+
+	_, err = repo.Create(model.AuctionInput{Item: "item", ParentUuid: "parent-uuid", Origin: model.OriginRelist})
+
+	assert.ErrorIs(t, err, model.ErrRelisted)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_Catalogue(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectQuery("SELECT (.+) FROM auction_events WHERE uuid = \\?").
+		WithArgs("event-uuid").
+		WillReturnRows(sqlmock.NewRows(auctionEventColumns).
+			AddRow(1, "event-uuid", "estate sale", "seller-uuid", now, 60, 2, now, now))
+	mockSql.ExpectQuery("SELECT (.+) FROM auctions WHERE event_uuid = \\? ORDER BY lot_number ASC").
+		WithArgs("event-uuid").
+		WillReturnRows(auctionRows().
+			AddRow(1, "lot-a", "lot 1", 100, "USD", 0, "USD", "", "seller-uuid", 0, 0, now, now, now, now, nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "event-uuid", 1, "item-a", "", "", 0, "", "").
+			AddRow(2, "lot-b", "lot 2", 50, "USD", 0, "USD", "", "seller-uuid", 0, 0, now, now, now, now, nil, model.InProgress, model.English, 0, "USD", 0, "USD", 0, 0, "USD", 0, "USD", "event-uuid", 2, "item-b", "", "", 0, "", ""))
+
+	result, err := repo.Catalogue("event-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "estate sale", result.Event.Title)
+	assert.Len(t, result.Lots, 2)
+	assert.Equal(t, "item-b", result.Lots[1].ItemUuid)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CatalogueNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT (.+) FROM auction_events WHERE uuid = \\?").
+		WillReturnRows(sqlmock.NewRows(auctionEventColumns))
+
+	_, err = repo.Catalogue("missing-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestAuctionRepository_CreateIncrementTable(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("INSERT INTO increment_tables").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("INSERT INTO increment_bands \\(table_uuid, position, below, increment\\) VALUES \\(.+\\), \\(.+\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockSql.ExpectCommit()
+
+	id, err := repo.CreateIncrementTable(model.IncrementTableInput{
+		Name:     "art",
+		Category: "art",
+		Bands:    []model.IncrementBand{{Below: 50, Increment: 1}, {Increment: 5}},
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_CategoryIncrementTable(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectQuery("SELECT (.+) FROM increment_tables WHERE category = \\? ORDER BY id DESC LIMIT 1").
+		WithArgs("art").
+		WillReturnRows(sqlmock.NewRows(incrementTableColumns).AddRow(2, "table-uuid", "art", "art", now))
+	mockSql.ExpectQuery("SELECT below, increment FROM increment_bands WHERE table_uuid = \\? ORDER BY position ASC").
+		WithArgs("table-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"below", "increment"}).AddRow(50, 1).AddRow(0, 5))
+
+	table, err := repo.CategoryIncrementTable("art")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.IncrementBand{{Below: 50, Increment: 1}, {Below: 0, Increment: 5}}, table.Bands)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestAuctionRepository_IncrementTableNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT (.+) FROM increment_tables WHERE uuid = \\?").
+		WillReturnRows(sqlmock.NewRows(incrementTableColumns))
+
+	_, err = repo.IncrementTable("missing-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
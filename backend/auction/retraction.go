@@ -0,0 +1,287 @@
+package auction
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+// RetractBid - lets the user a bidder bids for take back a bid of the bidder under the retraction rules,
+// the auction price and leader are recomputed from the bids left
+func (s *ServiceAuction) RetractBid(input model.RetractInput) (model.Auction, error) {
+	if input.AuctionUuid == "" || input.BidUuid == "" || input.ActorUuid == "" {
+		return model.Auction{}, fmt.Errorf("%w: auctionUuid, bidUuid and the user are required", model.ErrInvalidInput)
+	}
+
+	result, err := s.retract(input.AuctionUuid, func(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid, now time.Time) (model.Placement, error) {
+		bidderUuid, err := s.retractingBidder(bids, input)
+		if err != nil {
+			return model.Placement{}, err
+		}
+
+		retracted, err := bidderRetraction(*auction, bids, input.BidUuid, bidderUuid, s.config.Retraction, now)
+		if err != nil {
+			return model.Placement{}, err
+		}
+
+		increments, err := s.increments(*auction)
+		if err != nil {
+			return model.Placement{}, err
+		}
+
+		return recompute(auction, bids, proxies, retracted, bidderUuid, input.Reason, increments.At), nil
+	})
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.RetractBid failed retracting bid", zap.Any("input", input), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	return result, nil
+}
+
+// CancelBids - lets the seller or an admin cancel all the bids of a bidder, the auction price and leader
+// are recomputed from the bids left
+func (s *ServiceAuction) CancelBids(input model.CancelBidsInput) (model.Auction, error) {
+	if input.AuctionUuid == "" || input.BidderUuid == "" || input.ActorUuid == "" {
+		return model.Auction{}, fmt.Errorf("%w: auctionUuid, bidderUuid and the user are required", model.ErrInvalidInput)
+	}
+
+	result, err := s.retract(input.AuctionUuid, func(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid, now time.Time) (model.Placement, error) {
+		if input.ActorUuid != auction.UserUuid && !s.isAdmin(input.ActorUuid) {
+			return model.Placement{}, model.ErrForbidden
+		}
+
+		if err := checkRetractable(*auction, now); err != nil {
+			return model.Placement{}, err
+		}
+
+		retracted := map[string]bool{}
+		for _, bid := range bids {
+			if bid.BidderUuid == input.BidderUuid {
+				retracted[bid.Uuid] = true
+			}
+		}
+
+		if len(retracted) == 0 {
+			return model.Placement{}, fmt.Errorf("%w: the bidder has no active bids", model.ErrNotFound)
+		}
+
+		increments, err := s.increments(*auction)
+		if err != nil {
+			return model.Placement{}, err
+		}
+
+		return recompute(auction, bids, proxies, retracted, input.BidderUuid, input.Reason, increments.At), nil
+	})
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.CancelBids failed cancelling bids", zap.Any("input", input), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	return result, nil
+}
+
+// retract - runs apply on the locked auction and its active bids and publishes the recomputed auction
+// once the transaction is committed, sealed auctions publish nothing so their bids stay hidden
+func (s *ServiceAuction) retract(auctionUuid string, apply func(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid, now time.Time) (model.Placement, error)) (model.Auction, error) {
+	now := time.Now()
+
+	result, err := s.repo.RetractBids(auctionUuid, func(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid) (model.Placement, error) {
+		return apply(auction, bids, proxies, now)
+	})
+
+	if err != nil {
+		return model.Auction{}, err
+	}
+
+	if !result.Type.Sealed() {
+		s.publish(model.Event{
+			Type:         model.EventRetract,
+			AuctionUuid:  result.Uuid,
+			Status:       result.Status,
+			WinnerUuid:   result.WinnerUuid,
+			WinningPrice: result.WinningPrice.Amount,
+			Currency:     result.Currency(),
+			ExpiredAt:    result.ExpiredAt,
+			OccurredAt:   now,
+		})
+	}
+
+	return result, nil
+}
+
+// retractingBidder - the bidder of the bid to retract, which must bid for the user of the request
+func (s *ServiceAuction) retractingBidder(bids []model.Bid, input model.RetractInput) (string, error) {
+	for _, bid := range bids {
+		if bid.Uuid != input.BidUuid {
+			continue
+		}
+
+		bidder, err := s.bidders.FindOne(bid.BidderUuid)
+		if err != nil && !errors.Is(err, bidermodel.ErrNotFound) {
+			return "", err
+		}
+
+		if err != nil || bidder.UserUuid != input.ActorUuid {
+			return "", model.ErrForbidden
+		}
+
+		return bid.BidderUuid, nil
+	}
+
+	return "", fmt.Errorf("%w: no active bid %s", model.ErrNotFound, input.BidUuid)
+}
+
+func (s *ServiceAuction) isAdmin(actorUuid string) bool {
+	for _, admin := range s.config.Retraction.AdminUuids {
+		if admin == actorUuid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkRetractable - bids are retracted only while the auction is still running
+func checkRetractable(auction model.Auction, now time.Time) error {
+	if auction.Status != model.InProgress || !now.Before(auction.ExpiredAt) {
+		return model.ErrNotInProgress
+	}
+
+	return nil
+}
+
+// bidderRetraction - checks the retraction rules and returns the retracted bid together with
+// the later bids of the same bidder, which were placed on top of it
+func bidderRetraction(auction model.Auction, bids []model.Bid, bidUuid string, bidderUuid string, config RetractionConfig, now time.Time) (map[string]bool, error) {
+	if err := checkRetractable(auction, now); err != nil {
+		return nil, err
+	}
+
+	var target *model.Bid
+	for i := range bids {
+		if bids[i].Uuid == bidUuid && bids[i].BidderUuid == bidderUuid {
+			target = &bids[i]
+			break
+		}
+	}
+
+	if target == nil {
+		return nil, fmt.Errorf("%w: no active bid %s of the bidder", model.ErrNotFound, bidUuid)
+	}
+
+	window := time.Duration(config.WindowSeconds) * time.Second
+	if window > 0 && now.Sub(target.CreatedAt) > window {
+		return nil, fmt.Errorf("%w: bids may be retracted within %s of placing them", model.ErrRetractClosed, window)
+	}
+
+	cutoff := time.Duration(config.CutoffSeconds) * time.Second
+	if auction.ExpiredAt.Sub(now) < cutoff {
+		return nil, fmt.Errorf("%w: bids may not be retracted in the last %s of the auction", model.ErrRetractClosed, cutoff)
+	}
+
+	retracted := map[string]bool{}
+	for _, bid := range bids {
+		if bid.BidderUuid == bidderUuid && bid.ID >= target.ID {
+			retracted[bid.Uuid] = true
+		}
+	}
+
+	return retracted, nil
+}
+
+// recompute - drops the retracted bids and the bidder's proxy maximum, then rebuilds the high bid out of the bids left.
+// the auto bids the remaining proxies placed after the first retracted bid answered bids which are now gone, so they
+// are retracted with them and the remaining proxies are replayed from the best bid left instead: the highest remaining
+// bid leads, the earliest one on ties, and the proxies compete against it again.
+// sealed auctions keep no high bid while running, so only the retraction is recorded
+func recompute(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid, retracted map[string]bool, bidderUuid string, reason string, increment bidermodel.Increment) model.Placement {
+	placement := model.Placement{
+		DropProxyOf: bidderUuid,
+		Reason:      reason,
+	}
+
+	if auction.Type.Sealed() {
+		for _, bid := range bids {
+			if retracted[bid.Uuid] {
+				placement.Retracted = append(placement.Retracted, bid.Uuid)
+			}
+		}
+		return placement
+	}
+
+	var remaining []bidermodel.ProxyBid
+	replayed := map[string]bool{}
+	for _, proxy := range proxies {
+		if proxy.BidderUuid != bidderUuid {
+			remaining = append(remaining, proxy)
+			replayed[proxy.BidderUuid] = true
+		}
+	}
+
+	var first int64
+	for _, bid := range bids {
+		if retracted[bid.Uuid] && (first == 0 || bid.ID < first) {
+			first = bid.ID
+		}
+	}
+
+	auction.WinnerUuid = ""
+	auction.WinningPrice = auction.Money(0)
+
+	for _, bid := range bids {
+		switch {
+		case retracted[bid.Uuid], bid.Proxy && replayed[bid.BidderUuid] && bid.ID > first:
+			placement.Retracted = append(placement.Retracted, bid.Uuid)
+		case bid.Amount > auction.WinningPrice.Amount:
+			auction.WinnerUuid = bid.BidderUuid
+			auction.WinningPrice = auction.Money(bid.Amount)
+		}
+	}
+
+	if auction.WinnerUuid == "" {
+		placement.Bids = openProxy(auction, remaining)
+	}
+
+	if auction.WinnerUuid == "" {
+		return placement
+	}
+
+	placement.Bids = append(placement.Bids, resolveProxies(auction, remaining, increment)...)
+	placement.Bids = append(placement.Bids, meetReserve(auction, remaining)...)
+
+	return placement
+}
+
+// openProxy - with no bid left the strongest proxy opens the auction at its price, like a new proxy bid does
+func openProxy(auction *model.Auction, proxies []bidermodel.ProxyBid) []model.Bid {
+	var opener *bidermodel.ProxyBid
+
+	for i := range proxies {
+		proxy := &proxies[i]
+		if proxy.MaxAmount < auction.Price.Amount {
+			continue
+		}
+
+		if opener == nil || proxy.MaxAmount > opener.MaxAmount ||
+			(proxy.MaxAmount == opener.MaxAmount && proxy.CreatedAt.Before(opener.CreatedAt)) {
+			opener = proxy
+		}
+	}
+
+	if opener == nil {
+		return nil
+	}
+
+	auction.WinnerUuid = opener.BidderUuid
+	auction.WinningPrice = auction.Price
+
+	return []model.Bid{{BidderUuid: opener.BidderUuid, Amount: auction.Price.Amount, Proxy: true}}
+}
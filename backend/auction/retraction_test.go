@@ -0,0 +1,283 @@
+package auction
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/bider"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+func retractionFixture(now time.Time) (model.Auction, []model.Bid, []bidermodel.ProxyBid) {
+	auction := model.Auction{
+		Uuid:         "auction-uuid",
+		UserUuid:     "seller-uuid",
+		Price:        usd(100),
+		WinningPrice: usd(1000),
+		WinnerUuid:   "bidder-b",
+		ExpiredAt:    now.Add(48 * time.Hour),
+		Status:       model.InProgress,
+	}
+	bids := []model.Bid{
+		{ID: 1, Uuid: "bid-1", BidderUuid: "bidder-a", Amount: 100, CreatedAt: now.Add(-time.Hour)},
+		{ID: 2, Uuid: "bid-2", BidderUuid: "bidder-c", Amount: 110, Proxy: true, CreatedAt: now.Add(-50 * time.Minute)},
+		{ID: 3, Uuid: "bid-3", BidderUuid: "bidder-b", Amount: 150, CreatedAt: now.Add(-10 * time.Minute)},
+		{ID: 4, Uuid: "bid-4", BidderUuid: "bidder-b", Amount: 1000, CreatedAt: now.Add(-5 * time.Minute)},
+	}
+	proxies := []bidermodel.ProxyBid{
+		{AuctionUuid: "auction-uuid", BidderUuid: "bidder-c", MaxAmount: 140, CreatedAt: now.Add(-50 * time.Minute)},
+	}
+
+	return auction, bids, proxies
+}
+
+func TestRecompute(t *testing.T) {
+	now := time.Now()
+
+	t.Run("high bid falls back to the best remaining bid", func(t *testing.T) {
+		auction, bids, proxies := retractionFixture(now)
+
+		placement := recompute(&auction, bids, proxies, map[string]bool{"bid-4": true}, "bidder-b", "typo", flatIncrement(10))
+
+		assert.Equal(t, "bidder-b", auction.WinnerUuid)
+		assert.Equal(t, int64(150), auction.WinningPrice.Amount)
+		assert.Equal(t, []string{"bid-4"}, placement.Retracted)
+		assert.Equal(t, "bidder-b", placement.DropProxyOf)
+		assert.Empty(t, placement.Bids)
+	})
+
+	t.Run("remaining proxies compete again", func(t *testing.T) {
+		auction, bids, proxies := retractionFixture(now)
+
+		placement := recompute(&auction, bids, proxies, map[string]bool{"bid-3": true, "bid-4": true}, "bidder-b", "", flatIncrement(10))
+
+		assert.Equal(t, "bidder-c", auction.WinnerUuid)
+		assert.Equal(t, int64(110), auction.WinningPrice.Amount)
+		assert.Empty(t, placement.Bids)
+	})
+
+	t.Run("auto bids answering the retracted bid are replayed", func(t *testing.T) {
+		auction, bids, proxies := retractionFixture(now)
+		auction.WinnerUuid = "bidder-c"
+		auction.WinningPrice = usd(410)
+		bids = append(bids[:2],
+			model.Bid{ID: 3, Uuid: "bid-3", BidderUuid: "bidder-b", Amount: 400, CreatedAt: now.Add(-10 * time.Minute)},
+			model.Bid{ID: 4, Uuid: "bid-4", BidderUuid: "bidder-c", Amount: 410, Proxy: true, CreatedAt: now.Add(-10 * time.Minute)})
+		proxies[0].MaxAmount = 500
+
+		placement := recompute(&auction, bids, proxies, map[string]bool{"bid-3": true}, "bidder-b", "", flatIncrement(10))
+
+		assert.Equal(t, "bidder-c", auction.WinnerUuid)
+		assert.Equal(t, int64(110), auction.WinningPrice.Amount)
+		assert.Equal(t, []string{"bid-3", "bid-4"}, placement.Retracted)
+		assert.Empty(t, placement.Bids)
+	})
+
+	t.Run("a remaining proxy opens when no bid is left", func(t *testing.T) {
+		auction, bids, proxies := retractionFixture(now)
+		all := map[string]bool{"bid-1": true, "bid-3": true, "bid-4": true}
+
+		placement := recompute(&auction, bids[:1], proxies, all, "bidder-a", "", flatIncrement(10))
+
+		assert.Equal(t, "bidder-c", auction.WinnerUuid)
+		assert.Equal(t, int64(100), auction.WinningPrice.Amount)
+		assert.Equal(t, []model.Bid{{BidderUuid: "bidder-c", Amount: 100, Proxy: true}}, placement.Bids)
+	})
+
+	t.Run("no bids left", func(t *testing.T) {
+		auction, bids, _ := retractionFixture(now)
+		all := map[string]bool{"bid-1": true, "bid-2": true, "bid-3": true, "bid-4": true}
+
+		recompute(&auction, bids, nil, all, "bidder-b", "", flatIncrement(10))
+
+		assert.Empty(t, auction.WinnerUuid)
+		assert.Zero(t, auction.WinningPrice.Amount)
+	})
+
+	t.Run("sealed auctions only record the retraction", func(t *testing.T) {
+		auction, bids, proxies := retractionFixture(now)
+		auction.Type = model.SealedFirstPrice
+		auction.WinnerUuid = ""
+		auction.WinningPrice = usd(0)
+
+		placement := recompute(&auction, bids, proxies, map[string]bool{"bid-4": true}, "bidder-b", "", flatIncrement(10))
+
+		assert.Empty(t, auction.WinnerUuid)
+		assert.Equal(t, []string{"bid-4"}, placement.Retracted)
+	})
+}
+
+func TestBidderRetraction(t *testing.T) {
+	now := time.Now()
+	config := RetractionConfig{WindowSeconds: 1800, CutoffSeconds: 12 * 3600}
+
+	tests := []struct {
+		name     string
+		bidUuid  string
+		bidder   string
+		expireIn time.Duration
+		want     map[string]bool
+		wantErr  error
+	}{
+		{
+			name:     "takes the later bids of the bidder with it",
+			bidUuid:  "bid-3",
+			bidder:   "bidder-b",
+			expireIn: 48 * time.Hour,
+			want:     map[string]bool{"bid-3": true, "bid-4": true},
+		},
+		{
+			name:     "someone else's bid",
+			bidUuid:  "bid-3",
+			bidder:   "bidder-a",
+			expireIn: 48 * time.Hour,
+			wantErr:  model.ErrNotFound,
+		},
+		{
+			name:     "outside the retraction window",
+			bidUuid:  "bid-1",
+			bidder:   "bidder-a",
+			expireIn: 48 * time.Hour,
+			wantErr:  model.ErrRetractClosed,
+		},
+		{
+			name:     "too close to the end",
+			bidUuid:  "bid-4",
+			bidder:   "bidder-b",
+			expireIn: time.Hour,
+			wantErr:  model.ErrRetractClosed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			auction, bids, _ := retractionFixture(now)
+			auction.ExpiredAt = now.Add(test.expireIn)
+
+			retracted, err := bidderRetraction(auction, bids, test.bidUuid, test.bidder, config, now)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, retracted)
+		})
+	}
+}
+
+func TestServiceAuction_CancelBids(t *testing.T) {
+	now := time.Now()
+	auction, bids, proxies := retractionFixture(now)
+
+	t.Run("seller cancels a bidder and the change is published", func(t *testing.T) {
+		repo := &MockRepository{}
+		pub := acceptingPublisher()
+		repo.mock.On("RetractBids", "auction-uuid").Return(auction, bids, proxies, nil)
+		service := New(repo, pub, nil, Config{Bidding: BiddingConfig{MinIncrement: 10}}, zap.NewNop())
+
+		result, err := service.CancelBids(model.CancelBidsInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-b", ActorUuid: "seller-uuid", Reason: "fraud"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "bidder-c", result.WinnerUuid)
+		assert.Equal(t, []string{"bid-3", "bid-4"}, repo.placements[0].Retracted)
+		assert.Equal(t, "fraud", repo.placements[0].Reason)
+
+		var event model.Event
+		assert.NoError(t, json.Unmarshal(pub.mock.Calls[0].Arguments.Get(0).([]byte), &event))
+		assert.Equal(t, model.EventRetract, event.Type)
+		assert.Equal(t, int64(110), event.WinningPrice)
+	})
+
+	t.Run("admin may cancel", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("RetractBids", "auction-uuid").Return(auction, bids, proxies, nil)
+		config := Config{Bidding: BiddingConfig{MinIncrement: 10}, Retraction: RetractionConfig{AdminUuids: []string{"admin-uuid"}}}
+		service := New(repo, acceptingPublisher(), nil, config, zap.NewNop())
+
+		_, err := service.CancelBids(model.CancelBidsInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-b", ActorUuid: "admin-uuid"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("other bidders may not cancel", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("RetractBids", "auction-uuid").Return(auction, bids, proxies, nil)
+		service := New(repo, &MockPublisher{}, nil, Config{}, zap.NewNop())
+
+		_, err := service.CancelBids(model.CancelBidsInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-b", ActorUuid: "bidder-a"})
+
+		assert.ErrorIs(t, err, model.ErrForbidden)
+	})
+}
+
+type MockBidders struct {
+	mock mock.Mock
+}
+
+func (m *MockBidders) FindOne(uuid string) (bidermodel.Bidder, error) {
+	args := m.mock.Called(uuid)
+
+	return args.Get(0).(bidermodel.Bidder), args.Error(1)
+}
+
+func TestServiceAuction_RetractBid(t *testing.T) {
+	now := time.Now()
+	auction, bids, proxies := retractionFixture(now)
+	bidders := &MockBidders{}
+	bidders.mock.On("FindOne", "bidder-b").Return(bidermodel.Bidder{Uuid: "bidder-b", UserUuid: "user-b"}, nil)
+	config := Config{Bidding: BiddingConfig{MinIncrement: 10}, Retraction: RetractionConfig{WindowSeconds: 1800}}
+
+	t.Run("the user of the bidder retracts", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("RetractBids", "auction-uuid").Return(auction, bids, proxies, nil)
+		service := New(repo, acceptingPublisher(), bidders, config, zap.NewNop())
+
+		result, err := service.RetractBid(model.RetractInput{AuctionUuid: "auction-uuid", BidUuid: "bid-4", ActorUuid: "user-b"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(150), result.WinningPrice.Amount)
+		assert.Equal(t, []string{"bid-4"}, repo.placements[0].Retracted)
+	})
+
+	t.Run("another user may not retract the bid", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.mock.On("RetractBids", "auction-uuid").Return(auction, bids, proxies, nil)
+		service := New(repo, &MockPublisher{}, bidders, config, zap.NewNop())
+
+		_, err := service.RetractBid(model.RetractInput{AuctionUuid: "auction-uuid", BidUuid: "bid-4", ActorUuid: "user-a"})
+
+		assert.ErrorIs(t, err, model.ErrForbidden)
+		assert.Empty(t, repo.placements)
+	})
+}
+
+func TestServiceAuction_RetractBidStoredBidder(t *testing.T) {
+	now := time.Now()
+	auction, bids, proxies := retractionFixture(now)
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	rows := sqlmock.NewRows([]string{"uuid", "user_uuid", "name", "item", "price.amount", "price.currency", "created_at", "updated_at", "rating_count", "rating_score"}).
+		AddRow("bidder-b", "user-b", "name", "item", 1000, "USD", now, now, 0, 0)
+	mockSql.ExpectQuery("SELECT (.+) FROM bidders").WithArgs("bidder-b").WillReturnRows(rows)
+	bidders := bider.NewService(bider.New(sqlz.New(mockDB, "mysql"), zap.NewNop()), zap.NewNop())
+	repo := &MockRepository{}
+	repo.mock.On("RetractBids", "auction-uuid").Return(auction, bids, proxies, nil)
+	config := Config{Bidding: BiddingConfig{MinIncrement: 10}, Retraction: RetractionConfig{WindowSeconds: 1800}}
+	service := New(repo, acceptingPublisher(), bidders, config, zap.NewNop())
+
+	result, err := service.RetractBid(model.RetractInput{AuctionUuid: "auction-uuid", BidUuid: "bid-4", ActorUuid: "user-b"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), result.WinningPrice.Amount)
+	assert.Equal(t, []string{"bid-4"}, repo.placements[0].Retracted)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
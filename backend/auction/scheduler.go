@@ -0,0 +1,141 @@
+package auction
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+type Publisher interface {
+	Publish(message []byte) error
+}
+
+type SchedulerRepository interface {
+	CloseExpired(now time.Time, limit int64, decide func(auction *model.Auction, bids []model.Bid) model.Status) ([]model.Auction, error)
+	ClaimEndingSoon(now, until time.Time, limit int64) ([]model.Auction, error)
+}
+
+// Scheduler - closes auctions which passed their expiry and publishes the lifecycle events
+type Scheduler struct {
+	repo       SchedulerRepository
+	pub        Publisher
+	interval   time.Duration
+	batch      int64
+	endingSoon time.Duration
+	logger     *zap.Logger
+}
+
+func NewScheduler(repo SchedulerRepository, pub Publisher, config SchedulerConfig, logger *zap.Logger) *Scheduler {
+
+	return &Scheduler{
+		repo:       repo,
+		pub:        pub,
+		interval:   time.Duration(config.IntervalSeconds) * time.Second,
+		batch:      config.BatchSize,
+		endingSoon: time.Duration(config.EndingSoonSeconds) * time.Second,
+		logger:     logger,
+	}
+}
+
+// Run - closes expired auctions and announces the ones ending soon on every tick until stop is closed
+func (s *Scheduler) Run(stop chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("auction scheduler started", zap.Duration("interval", s.interval))
+
+	for {
+		select {
+		case <-stop:
+			s.logger.Info("auction scheduler stopped")
+			return
+		case now := <-ticker.C:
+			if err := s.CloseExpired(now); err != nil {
+				s.logger.Error("Scheduler.Run failed closing expired auctions", zap.Error(err))
+			}
+
+			if err := s.NotifyEndingSoon(now); err != nil {
+				s.logger.Error("Scheduler.Run failed notifying auctions ending soon", zap.Error(err))
+			}
+		}
+	}
+}
+
+// CloseExpired - closes a batch of expired auctions and publishes a closed event for each of them
+func (s *Scheduler) CloseExpired(now time.Time) error {
+	closed, err := s.repo.CloseExpired(now, s.batch, closeAuction)
+	if err != nil {
+		return err
+	}
+
+	for _, auction := range closed {
+		s.publish(model.Event{
+			Type:         model.EventClosed,
+			AuctionUuid:  auction.Uuid,
+			Status:       auction.Status,
+			WinnerUuid:   auction.WinnerUuid,
+			WinningPrice: auction.WinningPrice.Amount,
+			Currency:     auction.Currency(),
+			SellerUuid:   auction.UserUuid,
+			Category:     auction.Category,
+			ExpiredAt:    auction.ExpiredAt,
+			OccurredAt:   now,
+		})
+	}
+
+	return nil
+}
+
+// NotifyEndingSoon - publishes an ending soon event once for each auction which ends within the ending soon window,
+// the leader is left out so sealed auctions do not leak it
+func (s *Scheduler) NotifyEndingSoon(now time.Time) error {
+	ending, err := s.repo.ClaimEndingSoon(now, now.Add(s.endingSoon), s.batch)
+	if err != nil {
+		return err
+	}
+
+	for _, auction := range ending {
+		s.publish(model.Event{
+			Type:        model.EventEndingSoon,
+			AuctionUuid: auction.Uuid,
+			Status:      auction.Status,
+			ExpiredAt:   auction.ExpiredAt,
+			OccurredAt:  now,
+		})
+	}
+
+	return nil
+}
+
+func (s *Scheduler) publish(event model.Event) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Scheduler.publish failed encoding event", zap.Any("event", event), zap.Error(err))
+		return
+	}
+
+	if err = s.pub.Publish(message); err != nil {
+		s.logger.Error("Scheduler.publish failed publishing event", zap.Any("event", event), zap.Error(err))
+	}
+}
+
+// closeAuction - computes the winner of a sealed auction from its bids and decides the closing status
+func closeAuction(auction *model.Auction, bids []model.Bid) model.Status {
+	if auction.Type.Sealed() {
+		resolveSealed(auction, bids)
+	}
+
+	return closingStatus(*auction)
+}
+
+// closingStatus - an auction is sold when it has a winning bid which met the reserve and reached its bidders threshold
+func closingStatus(auction model.Auction) model.Status {
+	if auction.WinnerUuid != "" && auction.HasReserveMet() && auction.BiddersCount >= auction.BiddersThreshold {
+		return model.Sold
+	}
+
+	return model.Expired
+}
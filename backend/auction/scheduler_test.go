@@ -0,0 +1,207 @@
+package auction
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+type MockSchedulerRepository struct {
+	mock mock.Mock
+}
+
+func (m *MockSchedulerRepository) CloseExpired(now time.Time, limit int64, decide func(auction *model.Auction, bids []model.Bid) model.Status) ([]model.Auction, error) {
+	args := m.mock.Called(now, limit)
+	claimed, _ := args.Get(0).([]model.Auction)
+	bids, _ := args.Get(1).(map[string][]model.Bid)
+
+	var closed []model.Auction
+	for _, auction := range claimed {
+		auction.Status = decide(&auction, bids[auction.Uuid])
+		closed = append(closed, auction)
+	}
+
+	return closed, args.Error(2)
+}
+
+func (m *MockSchedulerRepository) ClaimEndingSoon(now, until time.Time, limit int64) ([]model.Auction, error) {
+	args := m.mock.Called(now, until, limit)
+	ending, _ := args.Get(0).([]model.Auction)
+
+	return ending, args.Error(1)
+}
+
+type MockPublisher struct {
+	mock mock.Mock
+}
+
+func (m *MockPublisher) Publish(message []byte) error {
+	args := m.mock.Called(message)
+
+	return args.Error(0)
+}
+
+func acceptingPublisher() *MockPublisher {
+	pub := &MockPublisher{}
+	pub.mock.On("Publish", mock.Anything).Return(nil)
+
+	return pub
+}
+
+func TestClosingStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		auction model.Auction
+		want    model.Status
+	}{
+		{
+			name:    "no bids",
+			auction: model.Auction{BiddersThreshold: 0},
+			want:    model.Expired,
+		},
+		{
+			name:    "winning bid without threshold",
+			auction: model.Auction{WinnerUuid: "bidder-a", BiddersCount: 1},
+			want:    model.Sold,
+		},
+		{
+			name:    "threshold not reached",
+			auction: model.Auction{WinnerUuid: "bidder-a", BiddersCount: 2, BiddersThreshold: 3},
+			want:    model.Expired,
+		},
+		{
+			name:    "reserve not met",
+			auction: model.Auction{WinnerUuid: "bidder-a", WinningPrice: usd(150), BiddersCount: 1, ReservePrice: usd(200)},
+			want:    model.Expired,
+		},
+		{
+			name:    "reserve met",
+			auction: model.Auction{WinnerUuid: "bidder-a", WinningPrice: usd(200), BiddersCount: 1, ReservePrice: usd(200)},
+			want:    model.Sold,
+		},
+		{
+			name:    "threshold reached",
+			auction: model.Auction{WinnerUuid: "bidder-a", BiddersCount: 3, BiddersThreshold: 3},
+			want:    model.Sold,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, closingStatus(test.auction))
+		})
+	}
+}
+
+func TestScheduler_CloseExpired(t *testing.T) {
+	now := time.Now()
+	repo := &MockSchedulerRepository{}
+	pub := &MockPublisher{}
+	scheduler := NewScheduler(repo, pub, SchedulerConfig{IntervalSeconds: 1, BatchSize: 10}, zap.NewNop())
+
+	repo.mock.On("CloseExpired", now, int64(10)).Return([]model.Auction{
+		{Uuid: "sold-uuid", WinnerUuid: "bidder-a", WinningPrice: usd(150), BiddersCount: 1},
+		{Uuid: "expired-uuid"},
+	}, nil, nil)
+	pub.mock.On("Publish", mock.Anything).Return(nil)
+
+	err := scheduler.CloseExpired(now)
+
+	assert.NoError(t, err)
+	pub.mock.AssertNumberOfCalls(t, "Publish", 2)
+
+	var event model.Event
+	assert.NoError(t, json.Unmarshal(pub.mock.Calls[0].Arguments.Get(0).([]byte), &event))
+	assert.Equal(t, model.EventClosed, event.Type)
+	assert.Equal(t, "sold-uuid", event.AuctionUuid)
+	assert.Equal(t, model.Sold, event.Status)
+	assert.Equal(t, int64(150), event.WinningPrice)
+
+	assert.NoError(t, json.Unmarshal(pub.mock.Calls[1].Arguments.Get(0).([]byte), &event))
+	assert.Equal(t, model.Expired, event.Status)
+}
+
+func TestScheduler_CloseExpiredSealed(t *testing.T) {
+	now := time.Now()
+	repo := &MockSchedulerRepository{}
+	pub := &MockPublisher{}
+	scheduler := NewScheduler(repo, pub, SchedulerConfig{IntervalSeconds: 1, BatchSize: 10}, zap.NewNop())
+
+	repo.mock.On("CloseExpired", now, int64(10)).Return([]model.Auction{
+		{Uuid: "vickrey-uuid", Price: usd(100), BiddersCount: 2, Type: model.SealedSecondPrice},
+	}, map[string][]model.Bid{
+		"vickrey-uuid": {
+			{ID: 1, BidderUuid: "bidder-a", Amount: 300},
+			{ID: 2, BidderUuid: "bidder-b", Amount: 250},
+		},
+	}, nil)
+	pub.mock.On("Publish", mock.Anything).Return(nil)
+
+	err := scheduler.CloseExpired(now)
+
+	assert.NoError(t, err)
+
+	var event model.Event
+	assert.NoError(t, json.Unmarshal(pub.mock.Calls[0].Arguments.Get(0).([]byte), &event))
+	assert.Equal(t, model.Sold, event.Status)
+	assert.Equal(t, "bidder-a", event.WinnerUuid)
+	assert.Equal(t, int64(250), event.WinningPrice)
+}
+
+func TestScheduler_CloseExpiredFailure(t *testing.T) {
+	now := time.Now()
+	repo := &MockSchedulerRepository{}
+	pub := &MockPublisher{}
+	scheduler := NewScheduler(repo, pub, SchedulerConfig{IntervalSeconds: 1, BatchSize: 10}, zap.NewNop())
+
+	repo.mock.On("CloseExpired", now, int64(10)).Return(nil, nil, errors.New("db is down"))
+
+	err := scheduler.CloseExpired(now)
+
+	assert.Error(t, err)
+	pub.mock.AssertNotCalled(t, "Publish", mock.Anything)
+}
+
+func TestScheduler_NotifyEndingSoon(t *testing.T) {
+	now := time.Now()
+	repo := &MockSchedulerRepository{}
+	pub := &MockPublisher{}
+	scheduler := NewScheduler(repo, pub, SchedulerConfig{IntervalSeconds: 1, BatchSize: 10, EndingSoonSeconds: 3600}, zap.NewNop())
+
+	repo.mock.On("ClaimEndingSoon", now, now.Add(time.Hour), int64(10)).Return([]model.Auction{
+		{Uuid: "ending-uuid", Status: model.InProgress, WinnerUuid: "bidder-a", WinningPrice: usd(150), ExpiredAt: now.Add(30 * time.Minute)},
+	}, nil)
+	pub.mock.On("Publish", mock.Anything).Return(nil)
+
+	err := scheduler.NotifyEndingSoon(now)
+
+	assert.NoError(t, err)
+	pub.mock.AssertNumberOfCalls(t, "Publish", 1)
+
+	var event model.Event
+	assert.NoError(t, json.Unmarshal(pub.mock.Calls[0].Arguments.Get(0).([]byte), &event))
+	assert.Equal(t, model.EventEndingSoon, event.Type)
+	assert.Equal(t, "ending-uuid", event.AuctionUuid)
+	assert.Empty(t, event.WinnerUuid)
+}
+
+func TestScheduler_NotifyEndingSoonRepositoryError(t *testing.T) {
+	now := time.Now()
+	repo := &MockSchedulerRepository{}
+	pub := &MockPublisher{}
+	scheduler := NewScheduler(repo, pub, SchedulerConfig{IntervalSeconds: 1, BatchSize: 10, EndingSoonSeconds: 3600}, zap.NewNop())
+
+	repo.mock.On("ClaimEndingSoon", now, now.Add(time.Hour), int64(10)).Return(nil, errors.New("db is down"))
+
+	err := scheduler.NotifyEndingSoon(now)
+
+	assert.Error(t, err)
+	pub.mock.AssertNotCalled(t, "Publish", mock.Anything)
+}
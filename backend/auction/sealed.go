@@ -0,0 +1,79 @@
+package auction
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+// applySealedBid - records a sealed bid without revealing it, the auction price and leader are left untouched
+// until the close. a bidder may bid again, only the bidder's highest bid counts
+func applySealedBid(auction *model.Auction, bid model.BidInput, now time.Time) (model.Placement, error) {
+	if err := checkBiddable(auction, now); err != nil {
+		return model.Placement{}, err
+	}
+
+	if bid.Amount < auction.Price.Amount {
+		return model.Placement{}, fmt.Errorf("%w: minimum bid is %d", model.ErrBidTooLow, auction.Price.Amount)
+	}
+
+	return model.Placement{
+		Bids: []model.Bid{{BidderUuid: bid.BidderUuid, Amount: bid.Amount}},
+	}, nil
+}
+
+// resolveSealed - the highest bid wins, the earliest one on ties. in a first price auction the winner pays the winning bid,
+// in a second price (vickrey) auction the winner pays the best bid of the other bidders, or the opening price
+// when nobody else bid, raised to the reserve when the winning bid met it
+func resolveSealed(auction *model.Auction, bids []model.Bid) {
+	best := highestBids(bids)
+	if len(best) == 0 {
+		return
+	}
+
+	auction.WinnerUuid = best[0].BidderUuid
+	auction.WinningPrice = auction.Money(best[0].Amount)
+
+	if auction.Type == model.SealedSecondPrice {
+		floor := auction.Price.Amount
+		if auction.ReservePrice.Amount > floor && best[0].Amount >= auction.ReservePrice.Amount {
+			floor = auction.ReservePrice.Amount
+		}
+
+		auction.WinningPrice = auction.Money(floor)
+		if len(best) > 1 && best[1].Amount > floor {
+			auction.WinningPrice = auction.Money(best[1].Amount)
+		}
+	}
+}
+
+// highestBids - the highest bid of every bidder, highest first and earliest first on equal amounts
+func highestBids(bids []model.Bid) []model.Bid {
+	byBidder := map[string]int{}
+	var result []model.Bid
+
+	for _, bid := range bids {
+		i, ok := byBidder[bid.BidderUuid]
+		if !ok {
+			byBidder[bid.BidderUuid] = len(result)
+			result = append(result, bid)
+			continue
+		}
+
+		if bid.Amount > result[i].Amount {
+			result[i] = bid
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Amount != result[j].Amount {
+			return result[i].Amount > result[j].Amount
+		}
+
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
@@ -0,0 +1,107 @@
+package auction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+func TestResolveSealed(t *testing.T) {
+	bids := []model.Bid{
+		{ID: 1, BidderUuid: "bidder-a", Amount: 200},
+		{ID: 2, BidderUuid: "bidder-b", Amount: 300},
+		{ID: 3, BidderUuid: "bidder-c", Amount: 300},
+		{ID: 4, BidderUuid: "bidder-a", Amount: 250},
+	}
+
+	tests := []struct {
+		name       string
+		auction    model.Auction
+		bids       []model.Bid
+		wantWinner string
+		wantPrice  int64
+	}{
+		{
+			name:    "no bids",
+			auction: model.Auction{Price: usd(100), Type: model.SealedFirstPrice},
+		},
+		{
+			name:       "first price pays the winning bid, earliest bid wins ties",
+			auction:    model.Auction{Price: usd(100), Type: model.SealedFirstPrice},
+			bids:       bids,
+			wantWinner: "bidder-b",
+			wantPrice:  300,
+		},
+		{
+			name:       "second price pays the second highest bid",
+			auction:    model.Auction{Price: usd(100), Type: model.SealedSecondPrice},
+			bids:       bids[:2],
+			wantWinner: "bidder-b",
+			wantPrice:  200,
+		},
+		{
+			name:       "second price counts only the highest bid of each bidder",
+			auction:    model.Auction{Price: usd(100), Type: model.SealedSecondPrice},
+			bids:       []model.Bid{bids[0], bids[1], bids[3]},
+			wantWinner: "bidder-b",
+			wantPrice:  250,
+		},
+		{
+			name:       "second price with a single bidder pays the opening price",
+			auction:    model.Auction{Price: usd(100), Type: model.SealedSecondPrice},
+			bids:       bids[:1],
+			wantWinner: "bidder-a",
+			wantPrice:  100,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolveSealed(&test.auction, test.bids)
+
+			assert.Equal(t, test.wantWinner, test.auction.WinnerUuid)
+			assert.Equal(t, test.wantPrice, test.auction.WinningPrice.Amount)
+		})
+	}
+}
+
+func TestServiceAuction_PlaceSealedBid(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("PlaceBid", "auction-uuid").Return(model.Auction{
+		Uuid:      "auction-uuid",
+		UserUuid:  "seller-uuid",
+		Price:     usd(100),
+		ExpiredAt: time.Now().Add(10 * time.Second),
+		Status:    model.InProgress,
+		Type:      model.SealedFirstPrice,
+	}, nil, nil)
+	service := New(repo, &MockPublisher{}, userBidders{}, Config{SoftClose: SoftCloseConfig{WindowSeconds: 60, ExtensionSeconds: 60, MaxExtensionSeconds: 600}}, zap.NewNop())
+
+	result, err := service.PlaceBid(model.BidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", Amount: 500})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.WinnerUuid)
+	assert.Zero(t, result.WinningPrice.Amount)
+	assert.Equal(t, []model.Bid{{BidderUuid: "bidder-a", Amount: 500}}, repo.placements[0].Bids)
+
+	_, err = service.PlaceProxyBid(model.ProxyBidInput{AuctionUuid: "auction-uuid", BidderUuid: "bidder-a", ActorUuid: "user-bidder-a", MaxAmount: 600})
+
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestServiceAuction_ListBidsHidesSealedBids(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Single", "sealed-uuid").Return(model.Auction{Uuid: "sealed-uuid", Status: model.InProgress, Type: model.SealedSecondPrice}, nil)
+	service := New(repo, &MockPublisher{}, userBidders{}, Config{}, zap.NewNop())
+
+	result, err := service.ListBids(model.BidListInput{AuctionUuid: "sealed-uuid"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Bids)
+	repo.mock.AssertNotCalled(t, "ListBids", mock.Anything)
+}
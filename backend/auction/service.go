@@ -0,0 +1,408 @@
+package auction
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	fraudmodel "github.com/ireuven89/hello-world/backend/fraud/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+type Service interface {
+	ListAuctions(input model.ListInput) ([]model.Auction, error)
+	GetAuction(uuid string) (model.Auction, error)
+	CreateAuction(input model.AuctionInput) (string, error)
+	UpdateAuction(input model.AuctionInput) error
+	CancelAuction(uuid string, actorUuid string) error
+	PlaceBid(input model.BidInput) (model.Auction, error)
+	PlaceProxyBid(input model.ProxyBidInput) (model.Auction, error)
+	BuyNow(input model.AcceptInput) (model.Auction, error)
+	AcceptAuction(input model.AcceptInput) (model.Auction, error)
+	RetractBid(input model.RetractInput) (model.Auction, error)
+	CancelBids(input model.CancelBidsInput) (model.Auction, error)
+	ListBids(input model.BidListInput) (model.BidHistory, error)
+	CreateAuctionEvent(input model.AuctionEventInput) (string, error)
+	GetCatalogue(uuid string) (model.Catalogue, error)
+	RelistAuction(input model.RelistInput) (string, error)
+	CloneAuction(input model.RelistInput) (string, error)
+	CreateIncrementTable(input model.IncrementTableInput) (string, error)
+	GetIncrementTable(uuid string) (model.IncrementTable, error)
+	NextBid(auctionUuid string) (model.NextBid, error)
+	SetCredit(input bidermodel.CreditInput) (bidermodel.Credit, error)
+	GetCredit(bidderUuid string, actorUuid string) (bidermodel.Exposure, error)
+	ListFlags(input fraudmodel.FlagListInput) (fraudmodel.FlagList, error)
+	ReviewFlag(input fraudmodel.ReviewInput) (fraudmodel.Flag, error)
+	LeaveFeedback(input model.FeedbackInput) (model.Feedback, error)
+	ListFeedback(input model.FeedbackListInput) (model.FeedbackList, error)
+}
+
+type Repository interface {
+	List(input model.ListInput) ([]model.Auction, error)
+	Single(uuid string) (model.Auction, error)
+	Create(input model.AuctionInput) (string, error)
+	Update(input model.AuctionInput) error
+	Cancel(uuid string) error
+	PlaceBid(auctionUuid string, bidderUuid string, place func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error)) (model.Auction, error)
+	RetractBids(auctionUuid string, retract func(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid) (model.Placement, error)) (model.Auction, error)
+	ListBids(input model.BidListInput) (model.BidHistory, error)
+	CreateEvent(input model.AuctionEventInput, lots []model.AuctionInput) (string, error)
+	Catalogue(uuid string) (model.Catalogue, error)
+	CreateIncrementTable(input model.IncrementTableInput) (string, error)
+	IncrementTable(uuid string) (model.IncrementTable, error)
+	CategoryIncrementTable(category string) (model.IncrementTable, error)
+	SetCredit(input bidermodel.CreditInput) (bidermodel.Credit, error)
+	Credit(bidderUuid string) (bidermodel.Credit, error)
+	Exposure(bidderUuid string, currency money.Currency) (int64, error)
+	ListFlags(input fraudmodel.FlagListInput) (fraudmodel.FlagList, error)
+	ReviewFlag(input fraudmodel.ReviewInput) (fraudmodel.Flag, error)
+	Flagged(bidderUuid string) (bool, error)
+	CreateFeedback(feedback model.Feedback) (model.Reputation, error)
+	ListFeedback(input model.FeedbackListInput) (model.FeedbackList, error)
+}
+
+// Bidders - looks up the bidders, a bidder bids on behalf of a user
+type Bidders interface {
+	FindOne(uuid string) (bidermodel.Bidder, error)
+}
+
+type ServiceAuction struct {
+	repo    Repository
+	pub     Publisher
+	bidders Bidders
+	config  Config
+	logger  *zap.Logger
+}
+
+func New(repo Repository, pub Publisher, bidders Bidders, config Config, logger *zap.Logger) Service {
+
+	return &ServiceAuction{repo: repo, pub: pub, bidders: bidders, config: config, logger: logger}
+}
+
+func (s *ServiceAuction) ListAuctions(input model.ListInput) ([]model.Auction, error) {
+	result, err := s.repo.List(input)
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.ListAuctions failed listing auctions", zap.Any("input", input), zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range result {
+		present(&result[i], now)
+	}
+
+	return result, nil
+}
+
+func (s *ServiceAuction) GetAuction(uuid string) (model.Auction, error) {
+	result, err := s.repo.Single(uuid)
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.GetAuction failed getting auction", zap.String("uuid", uuid), zap.Error(err))
+		return model.Auction{}, err
+	}
+
+	present(&result, time.Now())
+
+	return result, nil
+}
+
+func (s *ServiceAuction) CreateAuction(input model.AuctionInput) (string, error) {
+	currency := input.Currency()
+	if currency == "" {
+		currency = money.Default
+	}
+	input = input.InCurrency(currency)
+
+	if err := validateCreate(input); err != nil {
+		return "", err
+	}
+
+	if err := s.checkIncrementTable(input.IncrementTableUuid); err != nil {
+		return "", err
+	}
+
+	id, err := s.repo.Create(input)
+
+	if err != nil {
+		s.logger.Error("ServiceAuction.CreateAuction failed creating auction", zap.Any("input", input), zap.Error(err))
+		return "", err
+	}
+
+	return id, nil
+}
+
+// UpdateAuction - the seller's fields set in the input replace the stored ones, the result is validated as a whole
+// against the type and the currency of the stored auction. the prices are fixed once the auction has a bid
+func (s *ServiceAuction) UpdateAuction(input model.AuctionInput) error {
+	if err := validateUpdate(input); err != nil {
+		return err
+	}
+
+	stored, err := s.repo.Single(input.Uuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.UpdateAuction failed getting auction", zap.String("uuid", input.Uuid), zap.Error(err))
+		return err
+	}
+
+	if stored.UserUuid != input.UserUuid {
+		return model.ErrForbidden
+	}
+
+	if input.ChangesPrices() && stored.BiddersCount > 0 {
+		return model.ErrHasBids
+	}
+
+	merged := mergeUpdate(stored, input)
+	if err = validateCurrency(merged); err != nil {
+		return err
+	}
+
+	if err = validateReserve(merged); err != nil {
+		return err
+	}
+
+	if stored.Type == model.Dutch {
+		if err = validateDutch(merged); err != nil {
+			return err
+		}
+	}
+
+	if err := s.checkIncrementTable(input.IncrementTableUuid); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(input); err != nil {
+		s.logger.Error("ServiceAuction.UpdateAuction failed updating auction", zap.Any("input", input), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// CancelAuction - the seller, the actor, cancels an auction in progress
+func (s *ServiceAuction) CancelAuction(uuid string, actorUuid string) error {
+	stored, err := s.repo.Single(uuid)
+	if err != nil {
+		s.logger.Error("ServiceAuction.CancelAuction failed getting auction", zap.String("uuid", uuid), zap.Error(err))
+		return err
+	}
+
+	if stored.UserUuid != actorUuid {
+		return model.ErrForbidden
+	}
+
+	if err = s.repo.Cancel(uuid); err != nil {
+		s.logger.Error("ServiceAuction.CancelAuction failed cancelling auction", zap.String("uuid", uuid), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func validateCreate(input model.AuctionInput) error {
+	if input.Item == "" || input.UserUuid == "" {
+		return fmt.Errorf("%w: item and userUuid are required", model.ErrInvalidInput)
+	}
+
+	if input.Price.Amount < 0 || input.BiddersThreshold < 0 {
+		return fmt.Errorf("%w: price and biddersThreshold must not be negative", model.ErrInvalidInput)
+	}
+
+	if !input.ExpiredAt.After(time.Now()) {
+		return fmt.Errorf("%w: expiredAt must be in the future", model.ErrInvalidInput)
+	}
+
+	if !input.Type.Valid() {
+		return fmt.Errorf("%w: unknown auction type %d", model.ErrInvalidInput, input.Type)
+	}
+
+	if err := validateCurrency(input); err != nil {
+		return err
+	}
+
+	if err := validateReserve(input); err != nil {
+		return err
+	}
+
+	if input.Type == model.Dutch {
+		return validateDutch(input)
+	}
+
+	return nil
+}
+
+// validateCurrency - the auction must be in a known currency and all its prices in that currency
+func validateCurrency(input model.AuctionInput) error {
+	currency := input.Currency()
+	if !currency.Valid() {
+		return fmt.Errorf("%w: %w: %q", model.ErrInvalidInput, money.ErrUnknownCurrency, currency)
+	}
+
+	for _, price := range input.Prices() {
+		if price.Currency != currency {
+			return fmt.Errorf("%w: %w: price in %s on an auction in %s", model.ErrInvalidInput, money.ErrCurrencyMismatch, price.Currency, currency)
+		}
+	}
+
+	return nil
+}
+
+func validateReserve(input model.AuctionInput) error {
+	if input.ReservePrice.Amount < 0 || input.BuyNowPrice.Amount < 0 {
+		return fmt.Errorf("%w: reservePrice and buyNowPrice must not be negative", model.ErrInvalidInput)
+	}
+
+	if input.ReservePrice.Amount > 0 && input.Type == model.Dutch {
+		return fmt.Errorf("%w: dutch auctions use a floor price instead of a reserve", model.ErrInvalidInput)
+	}
+
+	if input.BuyNowPrice.Amount == 0 {
+		return nil
+	}
+
+	if input.Type != model.English {
+		return fmt.Errorf("%w: buy it now is offered on english auctions only", model.ErrInvalidInput)
+	}
+
+	if input.BuyNowPrice.Amount < input.Price.Amount || input.BuyNowPrice.Amount < input.ReservePrice.Amount {
+		return fmt.Errorf("%w: buyNowPrice must not be lower than the price and the reserve", model.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+func validateDutch(input model.AuctionInput) error {
+	if input.PriceStep.Amount <= 0 || input.StepIntervalSeconds <= 0 {
+		return fmt.Errorf("%w: dutch auctions require a positive priceStep and stepIntervalSeconds", model.ErrInvalidInput)
+	}
+
+	if input.FloorPrice.Amount < 0 || input.FloorPrice.Amount > input.Price.Amount {
+		return fmt.Errorf("%w: floorPrice must be between 0 and the start price", model.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+func validateUpdate(input model.AuctionInput) error {
+	if input.Uuid == "" {
+		return fmt.Errorf("%w: uuid is required", model.ErrInvalidInput)
+	}
+
+	if input.Price.Amount < 0 || input.BiddersThreshold < 0 || input.FloorPrice.Amount < 0 || input.PriceStep.Amount < 0 || input.StepIntervalSeconds < 0 {
+		return fmt.Errorf("%w: price, biddersThreshold, floorPrice, priceStep and stepIntervalSeconds must not be negative", model.ErrInvalidInput)
+	}
+
+	if !input.ExpiredAt.IsZero() && !input.ExpiredAt.After(time.Now()) {
+		return fmt.Errorf("%w: expiredAt must be in the future", model.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// mergeUpdate - the stored auction with the fields the update sets, the same fields the repository writes.
+// the prices the update sets without a currency are in the currency of the stored auction
+func mergeUpdate(stored model.Auction, input model.AuctionInput) model.AuctionInput {
+	merged := model.AuctionInput{
+		Uuid:                stored.Uuid,
+		Item:                stored.Item,
+		Price:               stored.Price,
+		UserUuid:            stored.UserUuid,
+		BiddersThreshold:    stored.BiddersThreshold,
+		ExpiredAt:           stored.ExpiredAt,
+		Type:                stored.Type,
+		FloorPrice:          stored.FloorPrice,
+		PriceStep:           stored.PriceStep,
+		StepIntervalSeconds: stored.StepIntervalSeconds,
+		ReservePrice:        stored.ReservePrice,
+		BuyNowPrice:         stored.BuyNowPrice,
+		Category:            stored.Category,
+		IncrementTableUuid:  stored.IncrementTableUuid,
+	}
+
+	if input.Item != "" {
+		merged.Item = input.Item
+	}
+
+	if input.Price.Amount != 0 {
+		merged.Price = input.Price
+	}
+
+	if input.BiddersThreshold != 0 {
+		merged.BiddersThreshold = input.BiddersThreshold
+	}
+
+	if input.FloorPrice.Amount != 0 {
+		merged.FloorPrice = input.FloorPrice
+	}
+
+	if input.PriceStep.Amount != 0 {
+		merged.PriceStep = input.PriceStep
+	}
+
+	if input.StepIntervalSeconds != 0 {
+		merged.StepIntervalSeconds = input.StepIntervalSeconds
+	}
+
+	if input.ReservePrice.Amount != 0 {
+		merged.ReservePrice = input.ReservePrice
+	}
+
+	if input.BuyNowPrice.Amount != 0 {
+		merged.BuyNowPrice = input.BuyNowPrice
+	}
+
+	if input.Category != "" {
+		merged.Category = input.Category
+	}
+
+	if input.IncrementTableUuid != "" {
+		merged.IncrementTableUuid = input.IncrementTableUuid
+	}
+
+	if !input.ExpiredAt.IsZero() {
+		merged.ExpiredAt = input.ExpiredAt
+	}
+
+	return merged.InCurrency(stored.Currency())
+}
+
+// present - fills the fields computed for the readers of an auction
+func present(auction *model.Auction, now time.Time) {
+	auction.CurrentPrice = auction.PriceAt(now)
+	auction.ReserveMet = auction.HasReserveMet()
+	auction.BuyNowAvailable = auction.CanBuyNow()
+}
+
+// publishClosed - announces an auction which was closed by a bidder rather than by the scheduler
+func (s *ServiceAuction) publishClosed(auction model.Auction) {
+	s.publish(model.Event{
+		Type:         model.EventClosed,
+		AuctionUuid:  auction.Uuid,
+		Status:       auction.Status,
+		WinnerUuid:   auction.WinnerUuid,
+		WinningPrice: auction.WinningPrice.Amount,
+		Currency:     auction.Currency(),
+		SellerUuid:   auction.UserUuid,
+		Category:     auction.Category,
+		ExpiredAt:    auction.ExpiredAt,
+		OccurredAt:   auction.UpdatedAt,
+	})
+}
+
+func (s *ServiceAuction) publish(event model.Event) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("ServiceAuction.publish failed encoding event", zap.Any("event", event), zap.Error(err))
+		return
+	}
+
+	if err = s.pub.Publish(message); err != nil {
+		s.logger.Error("ServiceAuction.publish failed publishing event", zap.Any("event", event), zap.Error(err))
+	}
+}
@@ -0,0 +1,379 @@
+package auction
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	fraudmodel "github.com/ireuven89/hello-world/backend/fraud/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+type MockRepository struct {
+	mock       mock.Mock
+	placements []model.Placement
+}
+
+func (m *MockRepository) List(input model.ListInput) ([]model.Auction, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).([]model.Auction), args.Error(1)
+}
+
+func (m *MockRepository) Single(uuid string) (model.Auction, error) {
+	args := m.mock.Called(uuid)
+
+	return args.Get(0).(model.Auction), args.Error(1)
+}
+
+func (m *MockRepository) Create(input model.AuctionInput) (string, error) {
+	args := m.mock.Called(input)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) Update(input model.AuctionInput) error {
+	args := m.mock.Called(input)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) Cancel(uuid string) error {
+	args := m.mock.Called(uuid)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) PlaceBid(auctionUuid string, bidderUuid string, place func(auction *model.Auction, proxies []bidermodel.ProxyBid) (model.Placement, error)) (model.Auction, error) {
+	args := m.mock.Called(auctionUuid)
+	auction := args.Get(0).(model.Auction)
+	proxies, _ := args.Get(1).([]bidermodel.ProxyBid)
+
+	placement, err := place(&auction, proxies)
+	if err != nil {
+		return model.Auction{}, err
+	}
+	m.placements = append(m.placements, placement)
+
+	return auction, args.Error(2)
+}
+
+func (m *MockRepository) RetractBids(auctionUuid string, retract func(auction *model.Auction, bids []model.Bid, proxies []bidermodel.ProxyBid) (model.Placement, error)) (model.Auction, error) {
+	args := m.mock.Called(auctionUuid)
+	auction := args.Get(0).(model.Auction)
+	bids, _ := args.Get(1).([]model.Bid)
+	proxies, _ := args.Get(2).([]bidermodel.ProxyBid)
+
+	placement, err := retract(&auction, bids, proxies)
+	if err != nil {
+		return model.Auction{}, err
+	}
+	m.placements = append(m.placements, placement)
+
+	return auction, args.Error(3)
+}
+
+func (m *MockRepository) CreateEvent(input model.AuctionEventInput, lots []model.AuctionInput) (string, error) {
+	args := m.mock.Called(input, lots)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) Catalogue(uuid string) (model.Catalogue, error) {
+	args := m.mock.Called(uuid)
+
+	return args.Get(0).(model.Catalogue), args.Error(1)
+}
+
+func (m *MockRepository) CreateIncrementTable(input model.IncrementTableInput) (string, error) {
+	args := m.mock.Called(input)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) IncrementTable(uuid string) (model.IncrementTable, error) {
+	args := m.mock.Called(uuid)
+
+	return args.Get(0).(model.IncrementTable), args.Error(1)
+}
+
+func (m *MockRepository) CategoryIncrementTable(category string) (model.IncrementTable, error) {
+	args := m.mock.Called(category)
+
+	return args.Get(0).(model.IncrementTable), args.Error(1)
+}
+
+func (m *MockRepository) SetCredit(input bidermodel.CreditInput) (bidermodel.Credit, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(bidermodel.Credit), args.Error(1)
+}
+
+func (m *MockRepository) Credit(bidderUuid string) (bidermodel.Credit, error) {
+	args := m.mock.Called(bidderUuid)
+
+	return args.Get(0).(bidermodel.Credit), args.Error(1)
+}
+
+func (m *MockRepository) Exposure(bidderUuid string, currency money.Currency) (int64, error) {
+	args := m.mock.Called(bidderUuid, currency)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) ListFlags(input fraudmodel.FlagListInput) (fraudmodel.FlagList, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(fraudmodel.FlagList), args.Error(1)
+}
+
+func (m *MockRepository) ReviewFlag(input fraudmodel.ReviewInput) (fraudmodel.Flag, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(fraudmodel.Flag), args.Error(1)
+}
+
+func (m *MockRepository) Flagged(bidderUuid string) (bool, error) {
+	args := m.mock.Called(bidderUuid)
+
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CreateFeedback(feedback model.Feedback) (model.Reputation, error) {
+	args := m.mock.Called(feedback)
+
+	return args.Get(0).(model.Reputation), args.Error(1)
+}
+
+func (m *MockRepository) ListFeedback(input model.FeedbackListInput) (model.FeedbackList, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(model.FeedbackList), args.Error(1)
+}
+
+func (m *MockRepository) ListBids(input model.BidListInput) (model.BidHistory, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(model.BidHistory), args.Error(1)
+}
+
+func TestServiceAuction_CreateAuction(t *testing.T) {
+	valid := model.AuctionInput{
+		Item:      "item-uuid",
+		UserUuid:  "user-uuid",
+		Price:     usd(100),
+		ExpiredAt: time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		input   model.AuctionInput
+		wantErr error
+	}{
+		{
+			name:  "success",
+			input: valid,
+		},
+		{
+			name:    "missing item",
+			input:   model.AuctionInput{UserUuid: "user-uuid", ExpiredAt: valid.ExpiredAt},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "negative price",
+			input:   model.AuctionInput{Item: "item-uuid", UserUuid: "user-uuid", Price: usd(-1), ExpiredAt: valid.ExpiredAt},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "dutch without a price step",
+			input:   model.AuctionInput{Item: "item-uuid", UserUuid: "user-uuid", Price: usd(100), ExpiredAt: valid.ExpiredAt, Type: model.Dutch, StepIntervalSeconds: 60},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "dutch floor above the start price",
+			input:   model.AuctionInput{Item: "item-uuid", UserUuid: "user-uuid", Price: usd(100), ExpiredAt: valid.ExpiredAt, Type: model.Dutch, FloorPrice: usd(200), PriceStep: usd(10), StepIntervalSeconds: 60},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "expired in the past",
+			input:   model.AuctionInput{Item: "item-uuid", UserUuid: "user-uuid", ExpiredAt: time.Now().Add(-time.Minute)},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:  "in euros",
+			input: model.AuctionInput{Item: "item-uuid", UserUuid: "user-uuid", Price: money.Money{Amount: 100, Currency: money.EUR}, ExpiredAt: valid.ExpiredAt},
+		},
+		{
+			name:    "unknown currency",
+			input:   model.AuctionInput{Item: "item-uuid", UserUuid: "user-uuid", Price: money.Money{Amount: 100, Currency: "XYZ"}, ExpiredAt: valid.ExpiredAt},
+			wantErr: money.ErrUnknownCurrency,
+		},
+		{
+			name:    "reserve in another currency",
+			input:   model.AuctionInput{Item: "item-uuid", UserUuid: "user-uuid", Price: usd(100), ReservePrice: money.Money{Amount: 300, Currency: money.EUR}, ExpiredAt: valid.ExpiredAt},
+			wantErr: money.ErrCurrencyMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Create", mock.Anything).Return("mock-uuid", nil)
+			service := New(repo, &MockPublisher{}, nil, Config{}, zap.NewNop())
+
+			id, err := service.CreateAuction(test.input)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				repo.mock.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "mock-uuid", id)
+		})
+	}
+}
+
+func TestServiceAuction_CreateAuctionDefaultsCurrency(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Create", mock.Anything).Return("mock-uuid", nil)
+	service := New(repo, &MockPublisher{}, nil, Config{}, zap.NewNop())
+
+	_, err := service.CreateAuction(model.AuctionInput{Item: "item", UserUuid: "user-uuid", Price: money.Money{Amount: 100}, ReservePrice: money.Money{Amount: 300}, ExpiredAt: time.Now().Add(time.Hour)})
+
+	assert.NoError(t, err)
+	created := repo.mock.Calls[0].Arguments.Get(0).(model.AuctionInput)
+	assert.Equal(t, money.USD, created.Currency())
+	assert.Equal(t, usd(300), created.ReservePrice)
+}
+
+func TestServiceAuction_CancelAuction(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Single", "sold-uuid").Return(model.Auction{Uuid: "sold-uuid", UserUuid: "seller-uuid", Status: model.Sold}, nil)
+	repo.mock.On("Cancel", "sold-uuid").Return(model.ErrNotInProgress)
+	service := New(repo, &MockPublisher{}, nil, Config{}, zap.NewNop())
+
+	err := service.CancelAuction("sold-uuid", "seller-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotInProgress)
+}
+
+func TestServiceAuction_CancelAuctionNotTheSeller(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Single", "mock-uuid").Return(model.Auction{Uuid: "mock-uuid", UserUuid: "seller-uuid", Status: model.InProgress}, nil)
+	service := New(repo, &MockPublisher{}, nil, Config{}, zap.NewNop())
+
+	err := service.CancelAuction("mock-uuid", "other-uuid")
+
+	assert.ErrorIs(t, err, model.ErrForbidden)
+	repo.mock.AssertNotCalled(t, "Cancel", mock.Anything)
+}
+
+func TestAuction_Localize(t *testing.T) {
+	euros := func(amount int64) money.Money { return money.Money{Amount: amount, Currency: money.EUR} }
+	auction := model.Auction{Price: euros(123456), CurrentPrice: euros(150000), WinnerUuid: "bidder-a", WinningPrice: euros(150000)}
+
+	auction.Localize("de-DE")
+
+	assert.Equal(t, &model.Display{Locale: "de-DE", Price: "1.234,56 €", CurrentPrice: "1.500,00 €", WinningPrice: "1.500,00 €"}, auction.Display)
+
+	auction.Type = model.SealedFirstPrice
+	auction.Localize("en-US")
+
+	assert.Empty(t, auction.Display.WinningPrice)
+}
+
+func TestServiceAuction_UpdateAuction(t *testing.T) {
+	english := model.Auction{Uuid: "mock-uuid", UserUuid: "seller-uuid", Price: usd(100), Status: model.InProgress, Type: model.English}
+	sealed := model.Auction{Uuid: "mock-uuid", UserUuid: "seller-uuid", Price: usd(100), Status: model.InProgress, Type: model.SealedFirstPrice}
+	withBids := model.Auction{Uuid: "mock-uuid", UserUuid: "seller-uuid", Price: usd(100), WinnerUuid: "bidder-a", WinningPrice: usd(120), BiddersCount: 1, Status: model.InProgress}
+
+	tests := []struct {
+		name    string
+		stored  model.Auction
+		input   model.AuctionInput
+		wantErr error
+	}{
+		{
+			name:   "buy now above the stored price",
+			stored: english,
+			input:  model.AuctionInput{Uuid: "mock-uuid", BuyNowPrice: usd(500)},
+		},
+		{
+			name:    "negative reserve",
+			stored:  english,
+			input:   model.AuctionInput{Uuid: "mock-uuid", ReservePrice: usd(-1)},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "buy now below the stored price",
+			stored:  english,
+			input:   model.AuctionInput{Uuid: "mock-uuid", BuyNowPrice: usd(50)},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "buy now on a sealed auction",
+			stored:  sealed,
+			input:   model.AuctionInput{Uuid: "mock-uuid", BuyNowPrice: usd(500)},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name:    "reserve after a bid",
+			stored:  withBids,
+			input:   model.AuctionInput{Uuid: "mock-uuid", ReservePrice: usd(500)},
+			wantErr: model.ErrHasBids,
+		},
+		{
+			name:   "category after a bid",
+			stored: withBids,
+			input:  model.AuctionInput{Uuid: "mock-uuid", Category: "art"},
+		},
+		{
+			name:    "not the seller",
+			stored:  model.Auction{Uuid: "mock-uuid", UserUuid: "other-uuid", Price: usd(100), Status: model.InProgress, Type: model.English},
+			input:   model.AuctionInput{Uuid: "mock-uuid", Category: "art"},
+			wantErr: model.ErrForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Single", "mock-uuid").Return(test.stored, nil)
+			repo.mock.On("Update", mock.Anything).Return(nil)
+			service := New(repo, &MockPublisher{}, nil, Config{}, zap.NewNop())
+			test.input.UserUuid = "seller-uuid"
+
+			err := service.UpdateAuction(test.input)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				repo.mock.AssertNotCalled(t, "Update", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuction_MarshalJSON(t *testing.T) {
+	message, err := json.Marshal(model.Auction{Uuid: "mock-uuid", WinnerUuid: "bidder-uuid-a", WinningPrice: usd(150)})
+	assert.NoError(t, err)
+
+	var public map[string]interface{}
+	assert.NoError(t, json.Unmarshal(message, &public))
+	assert.Equal(t, "bi***-a", public["winner"])
+	assert.Equal(t, "mock-uuid", public["uuid"])
+	assert.NotContains(t, public, "winnerUuid")
+	assert.NotContains(t, string(message), "bidder-uuid-a")
+}
+
+func usd(amount int64) money.Money {
+	return money.Money{Amount: amount, Currency: money.USD}
+}
@@ -0,0 +1,808 @@
+package auction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/julienschmidt/httprouter"
+	"github.com/labstack/gommon/log"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/authenticating"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	fraudmodel "github.com/ireuven89/hello-world/backend/fraud/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+func NewTransport(s Service, router *httprouter.Router, rates money.RateProvider, verifier authenticating.Verifier, proxies []netip.Prefix) Transport {
+
+	transport := Transport{
+		router: router,
+		s:      s,
+	}
+	RegisterRoutes(router, s, rates, verifier, proxies) // Register routes during initialization
+	return transport
+}
+
+type Transport struct {
+	router *httprouter.Router
+	s      Service
+}
+
+func (t *Transport) ListenAndServe(port string) {
+	log.Printf("Starting server on port %s...", port)
+	err := http.ListenAndServe(":"+port, t.router)
+	if err != nil {
+		log.Fatalf("Server failed to start: %v", err)
+	}
+}
+
+// RegisterRoutes - rates converts the prices shown to the currency of the viewer, nil shows the auction currency only.
+// verifier checks the token of the requests acting as a user, proxies are the networks of the trusted proxies
+func RegisterRoutes(router *httprouter.Router, s Service, rates money.RateProvider, verifier authenticating.Verifier, proxies []netip.Prefix) {
+	options := []kithttp.ServerOption{
+		kithttp.ServerBefore(authenticating.VerifyActor(verifier), clientAddress(proxies)),
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	getAuctionHandler := kithttp.NewServer(
+		MakeEndpointGetAuction(s, rates),
+		decodeGetAuctionRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listAuctionsHandler := kithttp.NewServer(
+		MakeEndpointListAuctions(s, rates),
+		decodeListAuctionsRequest,
+		encodeListAuctionsResponse,
+		options...,
+	)
+
+	createAuctionHandler := kithttp.NewServer(
+		MakeEndpointCreateAuction(s),
+		decodeCreateAuctionRequest,
+		encodeCreateAuctionResponse,
+		options...,
+	)
+
+	updateAuctionHandler := kithttp.NewServer(
+		MakeEndpointUpdateAuction(s),
+		decodeUpdateAuctionRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	cancelAuctionHandler := kithttp.NewServer(
+		MakeEndpointCancelAuction(s),
+		decodeCancelAuctionRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	placeBidHandler := kithttp.NewServer(
+		MakeEndpointPlaceBid(s),
+		decodePlaceBidRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	placeProxyBidHandler := kithttp.NewServer(
+		MakeEndpointPlaceProxyBid(s),
+		decodePlaceProxyBidRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	buyNowHandler := kithttp.NewServer(
+		MakeEndpointBuyNow(s),
+		decodeBuyNowRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	acceptAuctionHandler := kithttp.NewServer(
+		MakeEndpointAcceptAuction(s),
+		decodeAcceptAuctionRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	retractBidHandler := kithttp.NewServer(
+		MakeEndpointRetractBid(s),
+		decodeRetractBidRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	cancelBidsHandler := kithttp.NewServer(
+		MakeEndpointCancelBids(s),
+		decodeCancelBidsRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listBidsHandler := kithttp.NewServer(
+		MakeEndpointListBids(s, rates),
+		decodeListBidsRequest,
+		encodeListBidsResponse,
+		options...,
+	)
+
+	createAuctionEventHandler := kithttp.NewServer(
+		MakeEndpointCreateAuctionEvent(s),
+		decodeCreateAuctionEventRequest,
+		encodeCreateAuctionResponse,
+		options...,
+	)
+
+	getCatalogueHandler := kithttp.NewServer(
+		MakeEndpointGetCatalogue(s),
+		decodeGetCatalogueRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	relistAuctionHandler := kithttp.NewServer(
+		MakeEndpointRelistAuction(s),
+		decodeRelistAuctionRequest,
+		encodeCreateAuctionResponse,
+		options...,
+	)
+
+	cloneAuctionHandler := kithttp.NewServer(
+		MakeEndpointCloneAuction(s),
+		decodeRelistAuctionRequest,
+		encodeCreateAuctionResponse,
+		options...,
+	)
+
+	createIncrementTableHandler := kithttp.NewServer(
+		MakeEndpointCreateIncrementTable(s),
+		decodeCreateIncrementTableRequest,
+		encodeCreateAuctionResponse,
+		options...,
+	)
+
+	getIncrementTableHandler := kithttp.NewServer(
+		MakeEndpointGetIncrementTable(s),
+		decodeGetIncrementTableRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	nextBidHandler := kithttp.NewServer(
+		MakeEndpointNextBid(s),
+		decodeNextBidRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	setCreditHandler := kithttp.NewServer(
+		MakeEndpointSetCredit(s),
+		decodeSetCreditRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	getCreditHandler := kithttp.NewServer(
+		MakeEndpointGetCredit(s),
+		decodeGetCreditRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listFlagsHandler := kithttp.NewServer(
+		MakeEndpointListFlags(s),
+		decodeListFlagsRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	reviewFlagHandler := kithttp.NewServer(
+		MakeEndpointReviewFlag(s),
+		decodeReviewFlagRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	leaveFeedbackHandler := kithttp.NewServer(
+		MakeEndpointLeaveFeedback(s),
+		decodeLeaveFeedbackRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listFeedbackHandler := kithttp.NewServer(
+		MakeEndpointListFeedback(s),
+		decodeListFeedbackRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	router.Handler(http.MethodGet, "/auctions/:uuid", getAuctionHandler)
+	router.Handler(http.MethodGet, "/auctions", listAuctionsHandler)
+	router.Handler(http.MethodPost, "/auctions", createAuctionHandler)
+	router.Handler(http.MethodPut, "/auctions/:uuid", updateAuctionHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/cancel", cancelAuctionHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/bids", placeBidHandler)
+	router.Handler(http.MethodGet, "/auctions/:uuid/bids", listBidsHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/bids/:bidUuid/retract", retractBidHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/bidders/:bidderUuid/cancel-bids", cancelBidsHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/proxy-bids", placeProxyBidHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/buy-now", buyNowHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/accept", acceptAuctionHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/relist", relistAuctionHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/clone", cloneAuctionHandler)
+	router.Handler(http.MethodPost, "/events", createAuctionEventHandler)
+	router.Handler(http.MethodGet, "/auctions/:uuid/next-bid", nextBidHandler)
+	router.Handler(http.MethodGet, "/events/:uuid/catalogue", getCatalogueHandler)
+	router.Handler(http.MethodPost, "/increment-tables", createIncrementTableHandler)
+	router.Handler(http.MethodGet, "/increment-tables/:uuid", getIncrementTableHandler)
+	router.Handler(http.MethodPut, "/bidders/:bidderUuid/credit", setCreditHandler)
+	router.Handler(http.MethodGet, "/bidders/:bidderUuid/credit", getCreditHandler)
+	router.Handler(http.MethodGet, "/fraud/flags", listFlagsHandler)
+	router.Handler(http.MethodPost, "/fraud/flags/:uuid/review", reviewFlagHandler)
+	router.Handler(http.MethodPost, "/auctions/:uuid/feedback", leaveFeedbackHandler)
+	router.Handler(http.MethodGet, "/auctions/:uuid/feedback", listFeedbackHandler)
+	router.Handler(http.MethodGet, "/users/:userUuid/feedback", listFeedbackHandler)
+}
+
+func encodeError(ctx context.Context, err error, writer http.ResponseWriter) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, model.ErrNotFound), errors.Is(err, fraudmodel.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrInvalidInput), errors.Is(err, fraudmodel.ErrInvalidInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, model.ErrNotInProgress), errors.Is(err, model.ErrBuyNowClosed), errors.Is(err, model.ErrRetractClosed),
+		errors.Is(err, model.ErrNotRelistable), errors.Is(err, model.ErrRelisted), errors.Is(err, model.ErrHasBids), errors.Is(err, model.ErrFeedbackClosed), errors.Is(err, model.ErrFeedbackExists):
+		status = http.StatusConflict
+	case errors.Is(err, authenticating.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, model.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, model.ErrBidTooLow), errors.Is(err, bidermodel.ErrCreditLimit):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, bidermodel.ErrDepositRequired):
+		status = http.StatusPaymentRequired
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	json.NewEncoder(writer).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func decodeGetAuctionRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+
+	currency, err := viewerCurrency(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetAuctionRequest{
+		Uuid:     params.ByName("uuid"),
+		Locale:   r.URL.Query().Get("locale"),
+		Currency: currency,
+	}, nil
+}
+
+func decodeListAuctionsRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.ListInput
+	queryParams := r.URL.Query()
+
+	input.Item = queryParams.Get("item")
+	input.ItemUuid = queryParams.Get("itemUuid")
+	input.UserUuid = queryParams.Get("userUuid")
+
+	if status := queryParams.Get("status"); status != "" {
+		value, err := strconv.Atoi(status)
+		if err != nil {
+			return nil, model.ErrInvalidInput
+		}
+		parsed := model.Status(value)
+		input.Status = &parsed
+	}
+
+	input.Page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	input.Page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	currency, err := viewerCurrency(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ListAuctionsRequest{
+		input:    input,
+		locale:   queryParams.Get("locale"),
+		currency: currency,
+	}, nil
+}
+
+func encodeListAuctionsResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
+	res, ok := response.(ListAuctionsResponse)
+
+	if !ok {
+		return errors.New("encodeListAuctionsResponse failed to parse response")
+	}
+
+	formatted := map[string]interface{}{
+		"auctions": res.auctions,
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(writer).Encode(formatted)
+}
+
+func decodeCreateAuctionRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.AuctionInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.UserUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	return CreateAuctionRequest{
+		auction: input,
+	}, nil
+}
+
+func encodeCreateAuctionResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
+	res, ok := response.(CreateAuctionResponse)
+
+	if !ok {
+		return errors.New("encodeCreateAuctionResponse failed to encode response")
+	}
+
+	formatted := map[string]interface{}{
+		"uuid": res.Uuid,
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+
+	return json.NewEncoder(writer).Encode(formatted)
+}
+
+func decodeUpdateAuctionRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.AuctionInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.UserUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.Uuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return UpdateAuctionRequest{
+		auction: input,
+	}, nil
+}
+
+func decodeCancelAuctionRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	actor, err := authenticating.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return CancelAuctionRequest{
+		Uuid:      httprouter.ParamsFromContext(ctx).ByName("uuid"),
+		ActorUuid: actor,
+	}, nil
+}
+
+func decodePlaceBidRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.BidInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.AuctionUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+	input.IP = clientIP(ctx)
+
+	return PlaceBidRequest{
+		bid: input,
+	}, nil
+}
+
+func decodePlaceProxyBidRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.ProxyBidInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.AuctionUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+	input.IP = clientIP(ctx)
+
+	return PlaceProxyBidRequest{
+		bid: input,
+	}, nil
+}
+
+func decodeBuyNowRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.AcceptInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.AuctionUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+	input.IP = clientIP(ctx)
+
+	return BuyNowRequest{
+		buy: input,
+	}, nil
+}
+
+func decodeAcceptAuctionRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.AcceptInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.AuctionUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+	input.IP = clientIP(ctx)
+
+	return AcceptAuctionRequest{
+		accept: input,
+	}, nil
+}
+
+func decodeRetractBidRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.RetractInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	input.AuctionUuid = params.ByName("uuid")
+	input.BidUuid = params.ByName("bidUuid")
+
+	return RetractBidRequest{
+		retract: input,
+	}, nil
+}
+
+func decodeCancelBidsRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.CancelBidsInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	input.AuctionUuid = params.ByName("uuid")
+	input.BidderUuid = params.ByName("bidderUuid")
+
+	return CancelBidsRequest{
+		cancel: input,
+	}, nil
+}
+
+func decodeListBidsRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.BidListInput
+	queryParams := r.URL.Query()
+
+	input.AuctionUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+	input.Page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	input.Page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	currency, err := viewerCurrency(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ListBidsRequest{
+		input:    input,
+		currency: currency,
+	}, nil
+}
+
+func encodeListBidsResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
+	res, ok := response.(ListBidsResponse)
+
+	if !ok {
+		return errors.New("encodeListBidsResponse failed to parse response")
+	}
+
+	formatted := map[string]interface{}{
+		"bids":     res.history.Bids,
+		"total":    res.history.Total,
+		"currency": res.history.Currency,
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(writer).Encode(formatted)
+}
+
+func decodeCreateAuctionEventRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.AuctionEventInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	return CreateAuctionEventRequest{
+		event: input,
+	}, nil
+}
+
+func decodeGetCatalogueRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+
+	return GetCatalogueRequest{
+		Uuid: params.ByName("uuid"),
+	}, nil
+}
+
+func decodeRelistAuctionRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.RelistInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	input.AuctionUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return RelistAuctionRequest{
+		relist: input,
+	}, nil
+}
+
+func decodeCreateIncrementTableRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.IncrementTableInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	return CreateIncrementTableRequest{
+		table: input,
+	}, nil
+}
+
+func decodeGetIncrementTableRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+
+	return GetIncrementTableRequest{
+		Uuid: params.ByName("uuid"),
+	}, nil
+}
+
+func decodeNextBidRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+
+	return NextBidRequest{
+		AuctionUuid: params.ByName("uuid"),
+	}, nil
+}
+
+func decodeSetCreditRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input bidermodel.CreditInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.BidderUuid = httprouter.ParamsFromContext(ctx).ByName("bidderUuid")
+
+	return SetCreditRequest{
+		credit: input,
+	}, nil
+}
+
+func decodeGetCreditRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+
+	actorUuid, err := authenticating.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetCreditRequest{
+		BidderUuid: params.ByName("bidderUuid"),
+		ActorUuid:  actorUuid,
+	}, nil
+}
+
+func decodeListFlagsRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input fraudmodel.FlagListInput
+	queryParams := r.URL.Query()
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.Subject = fraudmodel.Subject(queryParams.Get("subject"))
+	input.SubjectUuid = queryParams.Get("subjectUuid")
+	input.Status = fraudmodel.FlagStatus(queryParams.Get("status"))
+	input.Page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	input.Page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	return ListFlagsRequest{
+		input: input,
+	}, nil
+}
+
+func decodeReviewFlagRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input fraudmodel.ReviewInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.FlagUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return ReviewFlagRequest{
+		review: input,
+	}, nil
+}
+
+func decodeLeaveFeedbackRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.FeedbackInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.AuthorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.AuctionUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return LeaveFeedbackRequest{
+		feedback: input,
+	}, nil
+}
+
+// decodeListFeedbackRequest - the feedback of an auction or, on the users route, the feedback a user received
+func decodeListFeedbackRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.FeedbackListInput
+	params := httprouter.ParamsFromContext(ctx)
+	queryParams := r.URL.Query()
+
+	input.AuctionUuid = params.ByName("uuid")
+	input.SubjectUuid = params.ByName("userUuid")
+	input.Page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	input.Page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	return ListFeedbackRequest{
+		input: input,
+	}, nil
+}
+
+// viewerCurrency - the currency the viewer wants prices converted to, the currency query param or the
+// currency of the region query param, the region of the user. empty when the viewer asked for neither
+func viewerCurrency(r *http.Request) (money.Currency, error) {
+	queryParams := r.URL.Query()
+
+	if code := queryParams.Get("currency"); code != "" {
+		currency, err := money.ParseCurrency(code)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", model.ErrInvalidInput, err)
+		}
+		return currency, nil
+	}
+
+	if region := queryParams.Get("region"); region != "" {
+		return money.RegionCurrency(region), nil
+	}
+
+	return "", nil
+}
+
+type addressKey struct{}
+
+// clientAddress - puts the address the request came from in the context. a request from a trusted proxy is from
+// the last address of X-Forwarded-For which is not a trusted proxy, the earlier addresses are whatever the client
+// sent and are never read. any other request is from its remote address
+func clientAddress(proxies []netip.Prefix) kithttp.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		address := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			address = host
+		}
+
+		if trusted(proxies, address) {
+			hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+			for i := len(hops) - 1; i >= 0; i-- {
+				hop := strings.TrimSpace(hops[i])
+				if hop == "" {
+					continue
+				}
+
+				address = hop
+				if !trusted(proxies, hop) {
+					break
+				}
+			}
+		}
+
+		return context.WithValue(ctx, addressKey{}, address)
+	}
+}
+
+// trusted - whether the address is of one of the trusted proxies
+func trusted(proxies []netip.Prefix, address string) bool {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return false
+	}
+
+	for _, proxy := range proxies {
+		if proxy.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIP - the address the request came from, set by clientAddress
+func clientIP(ctx context.Context) string {
+	address, _ := ctx.Value(addressKey{}).(string)
+
+	return address
+}
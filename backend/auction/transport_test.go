@@ -0,0 +1,48 @@
+package auction
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientAddress(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded string
+		want      string
+	}{
+		{"no proxy", "203.0.113.7:5000", "", "203.0.113.7"},
+		{"forged header without a proxy", "203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+		{"behind a proxy", "10.0.0.2:5000", "198.51.100.1", "198.51.100.1"},
+		{"forged header behind a proxy", "10.0.0.2:5000", "198.51.100.1, 203.0.113.7", "203.0.113.7"},
+		{"behind two proxies", "10.0.0.2:5000", "203.0.113.7, 192.168.1.1", "203.0.113.7"},
+		{"proxies only", "10.0.0.2:5000", "10.0.0.3", "10.0.0.3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/auctions/auction-uuid/bids", nil)
+			r.RemoteAddr = test.remote
+			if test.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", test.forwarded)
+			}
+
+			ctx := clientAddress(proxies)(context.Background(), r)
+
+			assert.Equal(t, test.want, clientIP(ctx))
+		})
+	}
+}
+
+func TestParseProxiesInvalid(t *testing.T) {
+	_, err := ParseProxies([]string{"not-an-address"})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package authenticating
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+)
+
+var ErrUnauthorized = errors.New("missing or invalid token")
+
+// Verifier - verifies a token and returns the user it was issued to, the services know users by the uuid
+// they registered with
+type Verifier interface {
+	VerifyToken(tokenString string) (string, error)
+}
+
+type actorKey struct{}
+
+// VerifyActor - puts the user of the bearer token of the request in the context, a request without a valid
+// token carries no user. the transports of the other services run it before decoding their requests
+func VerifyActor(verifier Verifier) kithttp.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			return ctx
+		}
+
+		user, err := verifier.VerifyToken(token)
+		if err != nil || user == "" {
+			return ctx
+		}
+
+		return context.WithValue(ctx, actorKey{}, user)
+	}
+}
+
+// Actor - the user of the verified token of the request, ErrUnauthorized when the request had none
+func Actor(ctx context.Context) (string, error) {
+	user, ok := ctx.Value(actorKey{}).(string)
+	if !ok {
+		return "", ErrUnauthorized
+	}
+
+	return user, nil
+}
@@ -0,0 +1,56 @@
+package authenticating
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type VerifierFunc func(tokenString string) (string, error)
+
+func (f VerifierFunc) VerifyToken(tokenString string) (string, error) {
+	return f(tokenString)
+}
+
+func TestVerifyActor(t *testing.T) {
+	verifier := VerifierFunc(func(tokenString string) (string, error) {
+		if tokenString != "valid-token" {
+			return "", errors.New("invalid token")
+		}
+		return "user-uuid", nil
+	})
+
+	tests := []struct {
+		name    string
+		header  string
+		want    string
+		wantErr error
+	}{
+		{name: "valid token", header: "Bearer valid-token", want: "user-uuid"},
+		{name: "no token", wantErr: ErrUnauthorized},
+		{name: "invalid token", header: "Bearer forged-token", wantErr: ErrUnauthorized},
+		{name: "not a bearer token", header: "valid-token", wantErr: ErrUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if test.header != "" {
+				req.Header.Set("Authorization", test.header)
+			}
+
+			actor, err := Actor(VerifyActor(verifier)(context.Background(), req))
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, actor)
+		})
+	}
+}
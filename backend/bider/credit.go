@@ -0,0 +1,50 @@
+package bider
+
+import (
+	"fmt"
+
+	"github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+// CheckCredit - checks the bidder can take the commitment on top of its exposure on the other open auctions.
+// a limited bidder may only commit in the currency of its credit, the exposure is never converted
+func CheckCredit(credit model.Credit, exposure int64, commitment model.Commitment) error {
+	if !credit.Limited() {
+		return nil
+	}
+
+	if credit.DepositRequired && credit.Deposit.Amount <= 0 {
+		return model.ErrDepositRequired
+	}
+
+	if commitment.Amount.Currency != credit.Currency() {
+		return fmt.Errorf("%w: %w, the credit is in %s", model.ErrCreditLimit, money.ErrCurrencyMismatch, credit.Currency())
+	}
+
+	available := credit.Available()
+	if exposure+commitment.Amount.Amount > available.Amount {
+		remaining := money.Money{Amount: max(available.Amount-exposure, 0), Currency: available.Currency}
+		return fmt.Errorf("%w: %s left to commit", model.ErrCreditLimit, remaining)
+	}
+
+	return nil
+}
+
+// ValidateCredit - the limit and the deposit may not be negative and must share a known currency
+func ValidateCredit(input model.CreditInput) error {
+	if input.Limit.Amount < 0 || input.Deposit.Amount < 0 {
+		return fmt.Errorf("%w: limit and deposit can not be negative", money.ErrInvalidAmount)
+	}
+
+	credit := model.Credit{Limit: input.Limit, Deposit: input.Deposit}
+	if !credit.Currency().Valid() {
+		return fmt.Errorf("%w: %q", money.ErrUnknownCurrency, credit.Currency())
+	}
+
+	if input.Limit.Currency != "" && input.Deposit.Currency != "" && input.Limit.Currency != input.Deposit.Currency {
+		return money.ErrCurrencyMismatch
+	}
+
+	return nil
+}
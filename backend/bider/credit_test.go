@@ -0,0 +1,53 @@
+package bider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+func usd(amount int64) money.Money {
+	return money.Money{Amount: amount, Currency: money.USD}
+}
+
+func TestCheckCredit(t *testing.T) {
+	tests := []struct {
+		name       string
+		credit     model.Credit
+		exposure   int64
+		commitment money.Money
+		wantErr    error
+	}{
+		{name: "unlimited bidder", credit: model.Credit{}, exposure: 1000000, commitment: usd(1000000)},
+		{name: "within the limit", credit: model.Credit{Limit: usd(10000)}, exposure: 6000, commitment: usd(4000)},
+		{name: "over the limit", credit: model.Credit{Limit: usd(10000)}, exposure: 6000, commitment: usd(4001), wantErr: model.ErrCreditLimit},
+		{name: "deposit adds to the limit", credit: model.Credit{Limit: usd(10000), Deposit: usd(5000)}, exposure: 6000, commitment: usd(9000)},
+		{name: "deposit missing", credit: model.Credit{Limit: usd(10000), Deposit: usd(0), DepositRequired: true}, commitment: usd(100), wantErr: model.ErrDepositRequired},
+		{name: "deposit paid", credit: model.Credit{Deposit: usd(500), DepositRequired: true}, commitment: usd(500)},
+		{name: "other currency", credit: model.Credit{Limit: usd(10000)}, commitment: money.Money{Amount: 100, Currency: money.EUR}, wantErr: money.ErrCurrencyMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckCredit(test.credit, test.exposure, model.Commitment{BidderUuid: "bidder", Amount: test.commitment})
+
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestValidateCredit(t *testing.T) {
+	assert.NoError(t, ValidateCredit(model.CreditInput{Limit: usd(100)}))
+	assert.NoError(t, ValidateCredit(model.CreditInput{Deposit: money.Money{Amount: 100, Currency: money.EUR}, DepositRequired: true}))
+
+	assert.ErrorIs(t, ValidateCredit(model.CreditInput{Limit: usd(-1)}), money.ErrInvalidAmount)
+	assert.ErrorIs(t, ValidateCredit(model.CreditInput{}), money.ErrUnknownCurrency)
+	assert.ErrorIs(t, ValidateCredit(model.CreditInput{Limit: usd(100), Deposit: money.Money{Amount: 100, Currency: money.EUR}}), money.ErrCurrencyMismatch)
+}
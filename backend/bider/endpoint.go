@@ -0,0 +1,106 @@
+package bider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+type ListBiddersRequest struct {
+	input model.BiddersInput
+}
+
+func MakeEndpointListBidders(s SService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListBiddersRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListBidders failed cast request")
+		}
+
+		result, err := s.List(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListBidders: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type GetBidderRequest struct {
+	Uuid string
+}
+
+func MakeEndpointGetBidder(s SService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetBidderRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointGetBidder failed cast request")
+		}
+
+		result, err := s.FindOne(req.Uuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointGetBidder: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type UpsertBidderRequest struct {
+	bidder model.BiddersInput
+}
+
+// UpsertBidderResponse - the uuid of the created or updated bidder
+type UpsertBidderResponse struct {
+	Uuid string `json:"uuid"`
+}
+
+func MakeEndpointCreateBidder(s SService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(UpsertBidderRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointCreateBidder failed cast request")
+		}
+
+		result, err := s.CreateBidder(req.bidder)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointCreateBidder: %w", err)
+		}
+
+		return UpsertBidderResponse{Uuid: result}, nil
+	}
+}
+
+func MakeEndpointUpdateBidder(s SService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(UpsertBidderRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointUpdateBidder failed cast request")
+		}
+
+		result, err := s.UpdateBidder(req.bidder)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointUpdateBidder: %w", err)
+		}
+
+		return UpsertBidderResponse{Uuid: result}, nil
+	}
+}
+
+func MakeEndpointDeleteBidder(s SService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetBidderRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointDeleteBidder failed cast request")
+		}
+
+		if err = s.Delete(req.Uuid); err != nil {
+			return nil, fmt.Errorf("MakeEndpointDeleteBidder: %w", err)
+		}
+
+		return nil, nil
+	}
+}
@@ -0,0 +1,86 @@
+package bider
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+)
+
+// legacyBidder - a bidder of the bidders table the items db kept before the bidders got their own db, its id was
+// its uuid and it had no user, item or description
+type legacyBidder struct {
+	Id            string  `db:"id"`
+	Name          string  `db:"name"`
+	PriceAmount   int64   `db:"price_amount"`
+	PriceCurrency string  `db:"price_currency"`
+	RatingCount   int64   `db:"rating_count"`
+	RatingScore   float64 `db:"rating_score"`
+	CreatedAt     string  `db:"created_at"`
+	UpdatedAt     string  `db:"updated_at"`
+}
+
+// MoveLegacy - moves the bidders the legacy db still keeps into the bidders db and drops them from the legacy db.
+// a bidder already moved is left as it is, so a move which failed half way is finished by the next one
+func (r *Repository) MoveLegacy(legacy *sqlz.DB) error {
+	var tables int64
+
+	err := legacy.QueryRow("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", dbmodel.Bidders).
+		Scan(&tables)
+	if err != nil {
+		r.logger.Error("BidderRepo.MoveLegacy failed finding legacy bidders", zap.Error(err))
+		return err
+	}
+
+	if tables == 0 {
+		return nil
+	}
+
+	var bidders []legacyBidder
+
+	q := legacy.Select(
+		"id", "name", "price_amount", "price_currency", "rating_count", "rating_score",
+		"CAST(COALESCE(created_at, CURRENT_TIMESTAMP) AS CHAR) AS created_at",
+		"CAST(COALESCE(updated_at, CURRENT_TIMESTAMP) AS CHAR) AS updated_at",
+	).From(dbmodel.Bidders)
+
+	utils.New().DebugSelect(q, "select legacy bidders")
+
+	if err = q.GetAll(&bidders); err != nil && err != sql.ErrNoRows {
+		r.logger.Error("BidderRepo.MoveLegacy failed listing legacy bidders", zap.Error(err))
+		return err
+	}
+
+	if len(bidders) > 0 {
+		rows := make([][]interface{}, 0, len(bidders))
+		for _, bidder := range bidders {
+			rows = append(rows, []interface{}{
+				bidder.Id, bidder.Name, bidder.PriceAmount, bidder.PriceCurrency, bidder.RatingCount, bidder.RatingScore,
+				bidder.CreatedAt, bidder.UpdatedAt,
+			})
+		}
+
+		insert := r.db.InsertInto(dbmodel.Bidders).
+			Columns("uuid", "name", "price_amount", "price_currency", "rating_count", "rating_score", "created_at", "updated_at").
+			ValueMultiple(rows)
+
+		query, bindings := insert.ToSQL(true)
+		if _, err = r.db.Exec(query+" ON DUPLICATE KEY UPDATE uuid = uuid", bindings...); err != nil {
+			r.logger.Error("BidderRepo.MoveLegacy failed moving legacy bidders", zap.Int("bidders", len(bidders)), zap.Error(err))
+			return err
+		}
+	}
+
+	if _, err = legacy.Exec(fmt.Sprintf("DROP TABLE %s", dbmodel.Bidders)); err != nil {
+		r.logger.Error("BidderRepo.MoveLegacy failed dropping legacy bidders", zap.Error(err))
+		return err
+	}
+
+	r.logger.Info("moved the legacy bidders", zap.Int("bidders", len(bidders)))
+
+	return nil
+}
@@ -1,26 +1,52 @@
 package model
 
 import (
+	"errors"
 	"time"
+
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+var (
+	ErrNotFound     = errors.New("bidder not found")
+	ErrInvalidInput = errors.New("invalid input")
 )
 
+// Bidder - RatingCount and RatingScore are the reputation of the bidder's user as last published by the auction service
 type Bidder struct {
-	Id          int64     `json:"-" db:"id"`
-	Uuid        string    `json:"uuid" db:"uuid"`
-	UserUuid    string    `json:"UserUuid" db:"user_uuid"`
-	Name        string    `json:"Name" db:"name"`
-	Item        string    `json:"Item" db:"item"`
-	Price       string    `json:"Price" db:"price"`
-	Description string    `json:"Description" db:"description"`
-	CreatedAt   time.Time `json:"Created_At" db:"created_at"`
-	UpdatedAt   time.Time `json:"UpdatedAt" db:"updated_at"`
+	Id          int64       `json:"-" db:"id"`
+	Uuid        string      `json:"uuid" db:"uuid"`
+	UserUuid    string      `json:"UserUuid" db:"user_uuid"`
+	Name        string      `json:"Name" db:"name"`
+	Item        string      `json:"Item" db:"item"`
+	Price       money.Money `json:"Price" db:"price"`
+	Description string      `json:"Description" db:"description"`
+	CreatedAt   time.Time   `json:"Created_At" db:"created_at"`
+	UpdatedAt   time.Time   `json:"UpdatedAt" db:"updated_at"`
+	RatingCount int64       `json:"ratingCount" db:"rating_count"`
+	RatingScore float64     `json:"ratingScore" db:"rating_score"`
 }
 
 type BiddersInput struct {
 	Page PageRequest `json:"defaultRequest"`
 	Uuid string      `json:"uuid"`
-	Name string      `json:"name"`
-	Item string      `json:"item"`
+	// UserUuid - the user the bidder bids for, required on create and never updated
+	UserUuid string `json:"userUuid"`
+	Name     string `json:"name"`
+	Item     string `json:"item"`
+	// Price - the price the bidder offers, stored in the price_amount and price_currency columns
+	Price money.Money `json:"price"`
+	// MinRating - lists the bidders whose rating score is at least MinRating, unrated bidders score zero
+	MinRating float64 `json:"minRating"`
+	// UserUuids - lists the bidders of any of these users
+	UserUuids []string `json:"userUuids"`
+}
+
+// ReputationInput - the reputation of a user as published by the auction service, every bidder of the user has it
+type ReputationInput struct {
+	UserUuid string
+	Count    int64
+	Score    float64
 }
 
 type PageRequest struct {
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+var (
+	ErrCreditLimit     = errors.New("bid exceeds the credit limit of the bidder")
+	ErrDepositRequired = errors.New("a deposit is required to bid")
+)
+
+// Credit - how much a bidder may commit across the open auctions. the bidder may commit up to Limit plus Deposit,
+// a bidder with DepositRequired may not bid until a deposit is paid and a bidder without limit, deposit or requirement
+// is not limited at all. the limit and the deposit are in the same currency
+type Credit struct {
+	BidderUuid      string      `json:"bidderUuid" db:"bidder_uuid"`
+	Limit           money.Money `json:"limit" db:"credit_limit"`
+	Deposit         money.Money `json:"deposit" db:"deposit"`
+	DepositRequired bool        `json:"depositRequired" db:"deposit_required"`
+	UpdatedAt       time.Time   `json:"updatedAt" db:"updated_at"`
+}
+
+// Limited - whether the bidder's commitments are checked at all
+func (c Credit) Limited() bool {
+	return c.DepositRequired || !c.Limit.IsZero() || !c.Deposit.IsZero()
+}
+
+// Available - the most the bidder may commit
+func (c Credit) Available() money.Money {
+	return money.Money{Amount: c.Limit.Amount + c.Deposit.Amount, Currency: c.Currency()}
+}
+
+// Currency - the currency of the credit, the limit's or the deposit's when there is no limit
+func (c Credit) Currency() money.Currency {
+	if c.Limit.Currency != "" {
+		return c.Limit.Currency
+	}
+
+	return c.Deposit.Currency
+}
+
+// Commitment - what a bidder stands to pay on an auction: its highest bid, or its proxy maximum when it is higher
+type Commitment struct {
+	BidderUuid string
+	Amount     money.Money
+}
+
+// Exposure - the bidder's commitments on the open auctions in the currency of its credit
+type Exposure struct {
+	Credit
+	Exposure  money.Money `json:"exposure"`
+	Remaining money.Money `json:"remaining"`
+}
+
+// CreditInput - the credit an operator, the actor, sets for a bidder
+type CreditInput struct {
+	BidderUuid      string      `json:"-"`
+	ActorUuid       string      `json:"-"`
+	Limit           money.Money `json:"limit"`
+	Deposit         money.Money `json:"deposit"`
+	DepositRequired bool        `json:"depositRequired"`
+}
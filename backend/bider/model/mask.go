@@ -0,0 +1,13 @@
+package model
+
+const maskVisible = 2
+
+// MaskIdentity - hides a bidder identity behind its first and last characters, stable for the same bidder
+// so a bid history can be followed without revealing who bid
+func MaskIdentity(identity string) string {
+	if len(identity) <= 2*maskVisible {
+		return "***"
+	}
+
+	return identity[:maskVisible] + "***" + identity[len(identity)-maskVisible:]
+}
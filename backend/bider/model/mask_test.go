@@ -0,0 +1,13 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskIdentity(t *testing.T) {
+	assert.Equal(t, "3f***9e", MaskIdentity("3f2a6c1e-8d4b-4f0a-9c1d-2b7e5a0c419e"))
+	assert.Equal(t, "***", MaskIdentity("abcd"))
+	assert.Equal(t, "***", MaskIdentity(""))
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// ProxyBid - the secret maximum a bidder allows the system to bid on the bidder's behalf
+type ProxyBid struct {
+	ID          int64     `json:"-" db:"id"`
+	AuctionUuid string    `json:"auctionUuid" db:"auction_uuid"`
+	BidderUuid  string    `json:"bidderUuid" db:"bidder_uuid"`
+	MaxAmount   int64     `json:"-" db:"max_amount"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// ProxyState - the current high bid and its bidder
+type ProxyState struct {
+	Price  int64
+	Leader string
+}
+
+// AutoBid - a bid generated by the system for a proxy bidder
+type AutoBid struct {
+	BidderUuid string
+	Amount     int64
+}
+
+// Increment - the minimum raise over the given price
+type Increment func(price int64) int64
@@ -0,0 +1,38 @@
+package bider
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	auctionmodel "github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+// HandleEvent - keeps the reputation of the users on their bidders so listings can filter on it, the other events
+// are ignored
+func (s *Service) HandleEvent(message []byte) error {
+	var event auctionmodel.Event
+
+	if err := json.Unmarshal(message, &event); err != nil {
+		s.logger.Error("bidderService.HandleEvent failed decoding event", zap.ByteString("message", message), zap.Error(err))
+		return err
+	}
+
+	if event.Type != auctionmodel.EventFeedback || event.Reputation == nil {
+		return nil
+	}
+
+	err := s.repo.SetReputation(model.ReputationInput{
+		UserUuid: event.Reputation.UserUuid,
+		Count:    event.Reputation.Count,
+		Score:    event.Reputation.Score,
+	})
+
+	if err != nil {
+		s.logger.Error("bidderService.HandleEvent failed setting reputation", zap.Any("event", event), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package bider
+
+import (
+	"sort"
+
+	"github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+// ResolveProxyBids - lets the proxy bidders outbid the current leader in minimum increments until
+// only one of them can still raise. it returns the new high bid and the auto bids in the order they were made
+func ResolveProxyBids(state model.ProxyState, proxies []model.ProxyBid, increment model.Increment) (model.ProxyState, []model.AutoBid) {
+	var bids []model.AutoBid
+
+	ordered := make([]model.ProxyBid, len(proxies))
+	copy(ordered, proxies)
+	// the highest maximum first, on equal maximums the earlier proxy wins
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].MaxAmount != ordered[j].MaxAmount {
+			return ordered[i].MaxAmount > ordered[j].MaxAmount
+		}
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+
+	for {
+		challenger, ok := strongestChallenger(state, ordered, increment)
+		if !ok {
+			return state, bids
+		}
+
+		leaderMax := state.Price
+		if leader, ok := findProxy(ordered, state.Leader); ok && leader.MaxAmount > leaderMax {
+			leaderMax = leader.MaxAmount
+		}
+
+		if challenger.MaxAmount > leaderMax {
+			// the leader's proxy is exhausted and the challenger takes the lead one increment above it
+			if leaderMax > state.Price {
+				bids = append(bids, model.AutoBid{BidderUuid: state.Leader, Amount: leaderMax})
+			}
+			state = model.ProxyState{
+				Price:  min(challenger.MaxAmount, leaderMax+increment(leaderMax)),
+				Leader: challenger.BidderUuid,
+			}
+			bids = append(bids, model.AutoBid{BidderUuid: state.Leader, Amount: state.Price})
+			continue
+		}
+
+		// the leader's proxy covers the challenger, which bids its maximum and gets outbid right away
+		bids = append(bids, model.AutoBid{BidderUuid: challenger.BidderUuid, Amount: challenger.MaxAmount})
+		state.Price = min(leaderMax, challenger.MaxAmount+increment(challenger.MaxAmount))
+		bids = append(bids, model.AutoBid{BidderUuid: state.Leader, Amount: state.Price})
+	}
+}
+
+// strongestChallenger - the proxy with the highest maximum that is not leading and can still beat the price
+func strongestChallenger(state model.ProxyState, ordered []model.ProxyBid, increment model.Increment) (model.ProxyBid, bool) {
+	for _, proxy := range ordered {
+		if proxy.BidderUuid == state.Leader {
+			continue
+		}
+
+		if proxy.MaxAmount >= state.Price+increment(state.Price) {
+			return proxy, true
+		}
+
+		return model.ProxyBid{}, false
+	}
+
+	return model.ProxyBid{}, false
+}
+
+func findProxy(proxies []model.ProxyBid, bidderUuid string) (model.ProxyBid, bool) {
+	for _, proxy := range proxies {
+		if proxy.BidderUuid == bidderUuid {
+			return proxy, true
+		}
+	}
+
+	return model.ProxyBid{}, false
+}
@@ -0,0 +1,137 @@
+package bider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+func flatIncrement(increment int64) model.Increment {
+	return func(price int64) int64 {
+		return increment
+	}
+}
+
+func TestResolveProxyBids(t *testing.T) {
+	earlier := time.Now()
+	later := earlier.Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		state     model.ProxyState
+		proxies   []model.ProxyBid
+		wantState model.ProxyState
+		wantBids  []model.AutoBid
+	}{
+		{
+			name:      "no proxies",
+			state:     model.ProxyState{Price: 100, Leader: "a"},
+			wantState: model.ProxyState{Price: 100, Leader: "a"},
+		},
+		{
+			name:      "leader proxy is not challenged",
+			state:     model.ProxyState{Price: 100, Leader: "a"},
+			proxies:   []model.ProxyBid{{BidderUuid: "a", MaxAmount: 300}},
+			wantState: model.ProxyState{Price: 100, Leader: "a"},
+		},
+		{
+			name:      "proxy outbids a manual bid by one increment",
+			state:     model.ProxyState{Price: 100, Leader: "b"},
+			proxies:   []model.ProxyBid{{BidderUuid: "a", MaxAmount: 300}},
+			wantState: model.ProxyState{Price: 110, Leader: "a"},
+			wantBids:  []model.AutoBid{{BidderUuid: "a", Amount: 110}},
+		},
+		{
+			name:      "proxy can not cover an increment",
+			state:     model.ProxyState{Price: 100, Leader: "b"},
+			proxies:   []model.ProxyBid{{BidderUuid: "a", MaxAmount: 105}},
+			wantState: model.ProxyState{Price: 100, Leader: "b"},
+		},
+		{
+			name:  "two proxies, the higher maximum wins one increment above the other",
+			state: model.ProxyState{Price: 100, Leader: "a"},
+			proxies: []model.ProxyBid{
+				{BidderUuid: "a", MaxAmount: 200, CreatedAt: earlier},
+				{BidderUuid: "b", MaxAmount: 250, CreatedAt: later},
+			},
+			wantState: model.ProxyState{Price: 210, Leader: "b"},
+			wantBids: []model.AutoBid{
+				{BidderUuid: "a", Amount: 200},
+				{BidderUuid: "b", Amount: 210},
+			},
+		},
+		{
+			name:  "challenger maximum within an increment of the winner caps the price",
+			state: model.ProxyState{Price: 100, Leader: "a"},
+			proxies: []model.ProxyBid{
+				{BidderUuid: "a", MaxAmount: 200, CreatedAt: earlier},
+				{BidderUuid: "b", MaxAmount: 205, CreatedAt: later},
+			},
+			wantState: model.ProxyState{Price: 205, Leader: "b"},
+			wantBids: []model.AutoBid{
+				{BidderUuid: "a", Amount: 200},
+				{BidderUuid: "b", Amount: 205},
+			},
+		},
+		{
+			name:  "equal maximums keep the earlier proxy leading",
+			state: model.ProxyState{Price: 100, Leader: "a"},
+			proxies: []model.ProxyBid{
+				{BidderUuid: "b", MaxAmount: 200, CreatedAt: later},
+				{BidderUuid: "a", MaxAmount: 200, CreatedAt: earlier},
+			},
+			wantState: model.ProxyState{Price: 200, Leader: "a"},
+			wantBids: []model.AutoBid{
+				{BidderUuid: "b", Amount: 200},
+				{BidderUuid: "a", Amount: 200},
+			},
+		},
+		{
+			name:  "manual leader against several proxies",
+			state: model.ProxyState{Price: 100, Leader: "c"},
+			proxies: []model.ProxyBid{
+				{BidderUuid: "a", MaxAmount: 150, CreatedAt: earlier},
+				{BidderUuid: "b", MaxAmount: 300, CreatedAt: later},
+			},
+			wantState: model.ProxyState{Price: 160, Leader: "b"},
+			wantBids: []model.AutoBid{
+				{BidderUuid: "b", Amount: 110},
+				{BidderUuid: "a", Amount: 150},
+				{BidderUuid: "b", Amount: 160},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state, bids := ResolveProxyBids(test.state, test.proxies, flatIncrement(10))
+
+			assert.Equal(t, test.wantState, state)
+			assert.Equal(t, test.wantBids, bids)
+		})
+	}
+}
+
+func TestResolveProxyBidsBandedIncrement(t *testing.T) {
+	now := time.Now()
+	banded := func(price int64) int64 {
+		if price < 100 {
+			return 5
+		}
+		return 20
+	}
+
+	state, bids := ResolveProxyBids(model.ProxyState{Price: 90, Leader: "a"}, []model.ProxyBid{
+		{BidderUuid: "a", MaxAmount: 95, CreatedAt: now},
+		{BidderUuid: "b", MaxAmount: 300, CreatedAt: now.Add(time.Minute)},
+	}, banded)
+
+	assert.Equal(t, model.ProxyState{Price: 100, Leader: "b"}, state)
+	assert.Equal(t, []model.AutoBid{
+		{BidderUuid: "a", Amount: 95},
+		{BidderUuid: "b", Amount: 100},
+	}, bids)
+}
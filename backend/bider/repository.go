@@ -1,44 +1,33 @@
 package bider
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/ido50/sqlz"
 	"go.uber.org/zap"
 
 	"github.com/ireuven89/hello-world/backend/bider/model"
 	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
 	"github.com/ireuven89/hello-world/backend/db/utils"
+	"github.com/ireuven89/hello-world/backend/money"
 )
 
-type Bidder struct {
-	ID        int64     `json:"-" sql:"id"`
-	Uuid      string    `json:"uuid" sql:"uuid"`
-	Name      string    `json:"name" sql:"name"`
-	CreatedAt time.Time `json:"createdAt" sql:"created_at"`
-	UpdatedAt time.Time `json:"updatedAt" sql:"updated_at"`
-}
-
-type Redis interface {
-	Get(key string) (interface{}, error)
-	Set(key string, value interface{}, ttl time.Duration) error
-}
-
-const redisQueryTtl = time.Minute * 3
+var bidderColumns = append([]string{"uuid", "user_uuid", "name", "item"}, append(money.Columns("price"), "created_at", "updated_at", "rating_count", "rating_score")...)
 
 type Repository struct {
 	db     *sqlz.DB
-	redis  Redis
 	logger *zap.Logger
 }
 
-func New(db *sqlz.DB, logger *zap.Logger, redis Redis) *Repository {
+func New(db *sqlz.DB, logger *zap.Logger) *Repository {
 
 	return &Repository{
 		db:     db,
 		logger: logger,
-		redis:  redis,
 	}
 }
 
@@ -46,51 +35,51 @@ func (r *Repository) List(input model.BiddersInput) ([]model.Bidder, error) {
 	var result []model.Bidder
 	var where []sqlz.WhereCondition
 
-	cachedQuery := fmt.Sprintf("%s%s%s%v%v", input.Uuid, input.Name, input.Item, input.Page.Offset, input.Page.GetLimit())
-
-	cachedResult, err := r.redis.Get(cachedQuery)
-
-	if err == nil {
-		r.logger.Debug(fmt.Sprintf("List redis hit on input %s", cachedQuery))
-		return cachedResult.([]model.Bidder), nil
-	}
-
 	if input.Name != "" {
 		where = append(where, sqlz.WhereCondition(sqlz.Eq("name", input.Name)))
 	}
 	if input.Item != "" {
 		where = append(where, sqlz.WhereCondition(sqlz.Eq("item", input.Item)))
 	}
+	if input.MinRating > 0 {
+		where = append(where, sqlz.WhereCondition(sqlz.Gte("rating_score", input.MinRating)))
+	}
+	if len(input.UserUuids) > 0 {
+		users := make([]interface{}, 0, len(input.UserUuids))
+		for _, userUuid := range input.UserUuids {
+			users = append(users, userUuid)
+		}
+		where = append(where, sqlz.WhereCondition(sqlz.In("user_uuid", users...)))
+	}
 
 	q := r.db.
-		Select("uuid", "name", "item", "created_at", "updated_at").From(dbmodel.Bidders).
+		Select(bidderColumns...).From(dbmodel.Bidders).
 		Offset(input.Page.Offset, input.Page.GetLimit())
 
 	q.Where(where...)
 
 	utils.New().DebugSelect(q, "select bidders")
 
-	if err = q.GetAll(&result); err != nil {
+	if err := q.GetAll(&result); err != nil {
 		r.logger.Error(fmt.Sprintf("BidderRepo.List failed to get db %v", err))
 		return nil, err
 	}
 
-	if err = r.redis.Set(cachedQuery, result, redisQueryTtl); err != nil {
-		r.logger.Warn(fmt.Sprintf("failed to set redis q: %s %v", cachedQuery, err))
-	}
-
 	return result, nil
 }
 
 func (r *Repository) Single(uuid string) (model.Bidder, error) {
 	var result model.Bidder
 
-	q := r.db.Select("uuid", "name", "item", "created_at", "updated_at").From(dbmodel.Bidders).
+	q := r.db.Select(bidderColumns...).From(dbmodel.Bidders).
 		Where(sqlz.WhereCondition(sqlz.Eq("uuid", uuid)))
 
 	utils.New().DebugSelect(q, "single bidder")
 
 	if err := q.GetRow(&result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, model.ErrNotFound
+		}
 		r.logger.Error("BidderRepo.Single failed finding bidder", zap.Error(err))
 		return result, err
 	}
@@ -98,49 +87,62 @@ func (r *Repository) Single(uuid string) (model.Bidder, error) {
 	return result, nil
 }
 
+// Upsert - creates the bidder when the input has no uuid and updates the set fields of the bidder otherwise,
+// it returns the uuid of the bidder. the user of a bidder is set on create only
 func (r *Repository) Upsert(input model.BiddersInput) (string, error) {
-	var create bool
-	var id string
-	if input.Uuid != "" {
-		create = true
-	}
+	now := time.Now()
+
+	if input.Uuid == "" {
+		id := uuid.New().String()
+		values := map[string]interface{}{
+			"uuid":       id,
+			"user_uuid":  input.UserUuid,
+			"item":       input.Item,
+			"name":       input.Name,
+			"created_at": now,
+			"updated_at": now,
+		}
+		for column, value := range input.Price.Values("price") {
+			values[column] = value
+		}
 
-	if create {
 		q := r.db.InsertInto(dbmodel.Bidders).
-			ValueMap(map[string]interface{}{
-				"uuid":       input.Uuid,
-				"item":       input.Item,
-				"name":       input.Name,
-				"created_at": time.Now(),
-				"updated_at": time.Now(),
-			}).Returning("id")
+			ValueMap(values)
 
 		utils.New().DebugInsert(q, "insert bidder")
 
-		if err := q.GetRow(&id); err != nil {
+		if _, err := q.Exec(); err != nil {
 			r.logger.Error("BidderRepo.Upsert failed creating bidder", zap.Error(err))
 			return "", err
 		}
-	} else {
-		valuesMap := setValuesMap(input)
-		q := r.db.
-			Update(dbmodel.Bidders).
-			SetMap(valuesMap).
-			Where(sqlz.Eq("uuid", input.Uuid))
 
-		utils.New().DebugUpdate(q, "update bidder")
+		return id, nil
+	}
 
-		if err := q.GetRow(&id); err != nil {
-			r.logger.Error("BidderRepo.Upsert failed updating bidder", zap.Error(err))
-			return "", err
-		}
+	q := r.db.
+		Update(dbmodel.Bidders).
+		SetMap(setValuesMap(input, now)).
+		Where(sqlz.Eq("uuid", input.Uuid))
+
+	utils.New().DebugUpdate(q, "update bidder")
+
+	res, err := q.Exec()
+	if err != nil {
+		r.logger.Error("BidderRepo.Upsert failed updating bidder", zap.Error(err))
+		return "", err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return "", err
+	} else if affected == 0 {
+		return "", model.ErrNotFound
 	}
 
-	return id, nil
+	return input.Uuid, nil
 }
 
-func setValuesMap(input model.BiddersInput) map[string]interface{} {
-	var valuesMap map[string]interface{}
+func setValuesMap(input model.BiddersInput, now time.Time) map[string]interface{} {
+	valuesMap := map[string]interface{}{"updated_at": now}
 
 	if input.Item != "" {
 		valuesMap["item"] = input.Item
@@ -150,6 +152,12 @@ func setValuesMap(input model.BiddersInput) map[string]interface{} {
 		valuesMap["name"] = input.Name
 	}
 
+	if input.Price.Currency != "" {
+		for column, value := range input.Price.Values("price") {
+			valuesMap[column] = value
+		}
+	}
+
 	return valuesMap
 }
 
@@ -158,8 +166,45 @@ func (r *Repository) Delete(uuid string) error {
 	q := r.db.DeleteFrom(dbmodel.Bidders).
 		Where(sqlz.Eq("uuid", uuid))
 
-	if _, err := q.Exec(); err != nil {
+	utils.New().DebugDelete(q, "delete bidder")
+
+	res, err := q.Exec()
+	if err != nil {
 		r.logger.Error("BidderRepo.Delete failed deleting bidder", zap.Error(err))
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetReputation - stores the reputation of the user on the user's bidders unless a newer one, counting more ratings,
+// is already stored. the user is required, the bidders which bid for no user have no reputation
+func (r *Repository) SetReputation(input model.ReputationInput) error {
+	if input.UserUuid == "" {
+		return fmt.Errorf("%w: userUuid is required", model.ErrInvalidInput)
+	}
+
+	q := r.db.Update(dbmodel.Bidders).
+		SetMap(map[string]interface{}{
+			"rating_count": input.Count,
+			"rating_score": input.Score,
+		}).
+		Where(sqlz.Eq("user_uuid", input.UserUuid), sqlz.Lt("rating_count", input.Count))
+
+	utils.New().DebugUpdate(q, "update bidder reputation")
+
+	if _, err := q.Exec(); err != nil {
+		r.logger.Error("BidderRepo.SetReputation failed updating reputation", zap.Error(err))
+		return err
 	}
 
 	return nil
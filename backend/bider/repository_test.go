@@ -1,52 +1,27 @@
 package bider
 
 import (
-	"errors"
-	"fmt"
 	"testing"
 	"time"
 
 	"github.com/ido50/sqlz"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 
 	"github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
 )
 
-type MockRedis struct {
-	mock mock.Mock
-}
-
-func (mdb *MockRedis) Set(key string, value interface{}, ttl time.Duration) error {
-	args := mdb.mock.Called(key, value, ttl)
-
-	return args.Error(0)
-}
-
-func (mdb *MockRedis) Get(key string) (interface{}, error) {
-	args := mdb.mock.Called(key)
-
-	return args.Get(0), args.Error(1)
-}
-
-func (mdb *MockRedis) Delete(uuid string) error {
-	args := mdb.mock.Called(uuid)
-
-	return args.Error(0)
-}
-
 func TestRepository_List(t *testing.T) {
 	logger := zap.NewNop()
-	redisMock := new(MockRedis)
 	mockDB, mockSql, err := sqlmock.New()
 	assert.NoError(t, err)
 	mockSqlz := sqlz.New(mockDB, "mysql")
 	createAt := time.Now()
 	updateAt := time.Now()
 
-	repo := New(mockSqlz, logger, redisMock)
+	repo := New(mockSqlz, logger)
 	input := model.BiddersInput{
 		Page: model.PageRequest{Offset: 0},
 		Name: "name",
@@ -55,29 +30,23 @@ func TestRepository_List(t *testing.T) {
 	expectedResult := []model.Bidder{
 		{
 			Uuid:      "mock-uuid",
+			UserUuid:  "user-uuid",
 			Name:      input.Name,
 			Item:      input.Item,
+			Price:     money.Money{Amount: 1250, Currency: money.EUR},
 			CreatedAt: createAt,
 			UpdatedAt: updateAt,
 		},
 	}
 
-	redisQuery := fmt.Sprintf("%s%s%s%v%v", input.Uuid, input.Name, input.Item, input.Page.Offset, input.Page.GetLimit())
-	rows := sqlmock.NewRows([]string{"uuid", "name", "item", "created_at", "updated_at"}).
-		AddRow("mock-uuid", input.Name, input.Item, createAt, updateAt)
-	mockSql.ExpectQuery("SELECT uuid, name, item, created_at, updated_at FROM bidders").
+	rows := sqlmock.NewRows([]string{"uuid", "user_uuid", "name", "item", "price.amount", "price.currency", "created_at", "updated_at", "rating_count", "rating_score"}).
+		AddRow("mock-uuid", "user-uuid", input.Name, input.Item, 1250, "EUR", createAt, updateAt, 0, 0)
+	mockSql.ExpectQuery("SELECT uuid, user_uuid, name, item, price_amount AS `price.amount`, price_currency AS `price.currency`, created_at, updated_at, rating_count, rating_score FROM bidders").
 		WithArgs("name", "item").
 		WillReturnRows(rows)
 
-	//redis cache miss and set valid
-	redisMock.mock.On("Get", redisQuery).Return(nil, errors.New("not found"))
-	redisMock.mock.On("Set", redisQuery, expectedResult, redisQueryTtl).Return(nil)
-
 	res, err := repo.List(input)
 
-	redisMock.mock.AssertCalled(t, "Get", redisQuery)
-	redisMock.mock.AssertCalled(t, "Set", redisQuery, expectedResult, redisQueryTtl)
-
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResult, res)
 	assert.NoError(t, mockSql.ExpectationsWereMet())
@@ -86,13 +55,12 @@ func TestRepository_List(t *testing.T) {
 
 func TestRepository_Single(t *testing.T) {
 	logger := zap.NewNop()
-	redisMock := MockRedis{mock: mock.Mock{}}
 	mockDB, mockSql, err := sqlmock.New()
 	assert.NoError(t, err)
 	mockSqlz := sqlz.New(mockDB, "mysql")
 	createAt := time.Now()
 	updateAt := time.Now()
-	repo := New(mockSqlz, logger, &redisMock)
+	repo := New(mockSqlz, logger)
 	mockUuid := "mock-uuid"
 	input := model.BiddersInput{
 		Page: model.PageRequest{Offset: 0},
@@ -101,14 +69,16 @@ func TestRepository_Single(t *testing.T) {
 	}
 	expectedResult := model.Bidder{
 		Uuid:      "mock-uuid",
+		UserUuid:  "user-uuid",
 		Name:      input.Name,
 		Item:      input.Item,
+		Price:     money.Money{Amount: 1250, Currency: money.EUR},
 		CreatedAt: createAt,
 		UpdatedAt: updateAt,
 	}
-	rows := sqlmock.NewRows([]string{"uuid", "name", "item", "created_at", "updated_at"}).
-		AddRow(mockUuid, "name", "item", createAt, updateAt)
-	mockSql.ExpectQuery("SELECT uuid, name, item, created_at, updated_at FROM bidders").
+	rows := sqlmock.NewRows([]string{"uuid", "user_uuid", "name", "item", "price.amount", "price.currency", "created_at", "updated_at", "rating_count", "rating_score"}).
+		AddRow(mockUuid, "user-uuid", "name", "item", 1250, "EUR", createAt, updateAt, 0, 0)
+	mockSql.ExpectQuery("SELECT uuid, user_uuid, name, item, price_amount AS `price.amount`, price_currency AS `price.currency`, created_at, updated_at, rating_count, rating_score FROM bidders").
 		WithArgs(mockUuid).
 		WillReturnRows(rows)
 
@@ -119,3 +89,175 @@ func TestRepository_Single(t *testing.T) {
 	assert.NoError(t, mockSql.ExpectationsWereMet())
 
 }
+
+func TestRepository_ListByRating(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+	input := model.BiddersInput{MinRating: 4.5}
+
+	rows := sqlmock.NewRows([]string{"uuid", "user_uuid", "name", "item", "price.amount", "price.currency", "created_at", "updated_at", "rating_count", "rating_score"}).
+		AddRow("mock-uuid", "user-uuid", "name", "item", 1250, "EUR", now, now, 12, 4.75)
+	mockSql.ExpectQuery("SELECT (.+) FROM bidders WHERE rating_score >= \\?").
+		WithArgs(4.5).
+		WillReturnRows(rows)
+
+	res, err := repo.List(input)
+
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, int64(12), res[0].RatingCount)
+	assert.Equal(t, 4.75, res[0].RatingScore)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestRepository_ListByUsers(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+	input := model.BiddersInput{UserUuids: []string{"user-a", "user-b"}}
+
+	rows := sqlmock.NewRows([]string{"uuid", "user_uuid", "name", "item", "price.amount", "price.currency", "created_at", "updated_at", "rating_count", "rating_score"}).
+		AddRow("bidder-a", "user-a", "name", "item", 1250, "EUR", now, now, 0, 0)
+	mockSql.ExpectQuery("SELECT (.+) FROM bidders WHERE user_uuid IN \\(\\?, \\?\\)").
+		WithArgs("user-a", "user-b").
+		WillReturnRows(rows)
+
+	res, err := repo.List(input)
+
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "user-a", res[0].UserUuid)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestRepository_SetReputation(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("UPDATE bidders SET rating_count = \\?, rating_score = \\? WHERE user_uuid = \\? AND rating_count < \\?").
+		WithArgs(3, 4.33, "user-uuid", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SetReputation(model.ReputationInput{UserUuid: "user-uuid", Count: 3, Score: 4.33})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestRepository_SetReputationWithoutUser(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	err = repo.SetReputation(model.ReputationInput{Count: 3, Score: 4.33})
+
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestRepository_SingleNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT (.+) FROM bidders").
+		WithArgs("missing-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"uuid"}))
+
+	_, err = repo.Single("missing-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestRepository_UpsertCreate(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("INSERT INTO bidders (.+user_uuid.+)").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	id, err := repo.Upsert(model.BiddersInput{UserUuid: "user-uuid", Name: "name", Item: "item", Price: money.Money{Amount: 1250, Currency: money.EUR}})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestRepository_UpsertUpdateNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("UPDATE bidders SET (.+) WHERE uuid = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = repo.Upsert(model.BiddersInput{Uuid: "missing-uuid", Name: "name"})
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("DELETE FROM bidders WHERE uuid = \\?").
+		WithArgs("missing-uuid").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete("missing-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestRepository_MoveLegacy(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	legacyDB, legacySql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	legacySql.ExpectQuery("SELECT COUNT\\(\\*\\) FROM information_schema.tables").
+		WithArgs("bidders").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	legacySql.ExpectQuery("SELECT id, name, (.+) FROM bidders").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price_amount", "price_currency", "rating_count", "rating_score", "created_at", "updated_at"}).
+			AddRow("legacy-uuid", "name", 1250, "EUR", 2, 4.5, "2024-01-02 03:04:05", "2024-01-02 03:04:05"))
+	mockSql.ExpectExec("INSERT INTO bidders (.+) ON DUPLICATE KEY UPDATE uuid = uuid").
+		WithArgs("legacy-uuid", "name", 1250, "EUR", 2, 4.5, "2024-01-02 03:04:05", "2024-01-02 03:04:05").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	legacySql.ExpectExec("DROP TABLE bidders").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.MoveLegacy(sqlz.New(legacyDB, "mysql"))
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+	assert.NoError(t, legacySql.ExpectationsWereMet())
+}
+
+func TestRepository_MoveLegacyAlreadyMoved(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	legacyDB, legacySql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	legacySql.ExpectQuery("SELECT COUNT\\(\\*\\) FROM information_schema.tables").
+		WithArgs("bidders").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	err = repo.MoveLegacy(sqlz.New(legacyDB, "mysql"))
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+	assert.NoError(t, legacySql.ExpectationsWereMet())
+}
@@ -1,9 +1,12 @@
 package bider
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 
 	"github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
 )
 
 type SService interface {
@@ -12,6 +15,7 @@ type SService interface {
 	CreateBidder(input model.BiddersInput) (string, error)
 	UpdateBidder(input model.BiddersInput) (string, error)
 	Delete(id string) error
+	HandleEvent(message []byte) error
 }
 
 type BidderRepo interface {
@@ -19,6 +23,7 @@ type BidderRepo interface {
 	Single(uuid string) (model.Bidder, error)
 	Upsert(input model.BiddersInput) (string, error)
 	Delete(id string) error
+	SetReputation(input model.ReputationInput) error
 }
 
 type Service struct {
@@ -26,6 +31,11 @@ type Service struct {
 	logger *zap.Logger
 }
 
+func NewService(repo BidderRepo, logger *zap.Logger) SService {
+
+	return &Service{repo: repo, logger: logger}
+}
+
 func (s *Service) List(input model.BiddersInput) ([]model.Bidder, error) {
 	res, err := s.repo.List(input)
 
@@ -47,7 +57,17 @@ func (s *Service) FindOne(uuid string) (model.Bidder, error) {
 	return res, nil
 }
 
+// CreateBidder - creates the bidder and returns its uuid, a price without a currency is in the default currency
 func (s *Service) CreateBidder(input model.BiddersInput) (string, error) {
+	if input.Price.Currency == "" {
+		input.Price.Currency = money.Default
+	}
+
+	if err := validateBidder(input); err != nil {
+		return "", err
+	}
+
+	input.Uuid = ""
 	result, err := s.repo.Upsert(input)
 
 	if err != nil {
@@ -57,16 +77,49 @@ func (s *Service) CreateBidder(input model.BiddersInput) (string, error) {
 
 	return result, nil
 }
+
+// UpdateBidder - updates the fields set in the input, a price is only updated together with its currency
 func (s *Service) UpdateBidder(input model.BiddersInput) (string, error) {
+	if input.Uuid == "" {
+		return "", fmt.Errorf("%w: uuid is required", model.ErrInvalidInput)
+	}
+
+	if input.Price.Currency != "" {
+		if err := validatePrice(input.Price); err != nil {
+			return "", err
+		}
+	}
+
 	result, err := s.repo.Upsert(input)
 
 	if err != nil {
-		s.logger.Error("BidderService.CreateBidder failed creating bidder", zap.Error(err))
+		s.logger.Error("BidderService.UpdateBidder failed updating bidder", zap.Error(err))
 		return "", err
 	}
 	return result, nil
 }
+
 func (s *Service) Delete(id string) error {
-	s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		s.logger.Error("BidderService.Delete failed deleting bidder", zap.String("uuid", id), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func validateBidder(input model.BiddersInput) error {
+	if input.UserUuid == "" || input.Name == "" {
+		return fmt.Errorf("%w: userUuid and name are required", model.ErrInvalidInput)
+	}
+
+	return validatePrice(input.Price)
+}
+
+func validatePrice(price money.Money) error {
+	if price.Amount < 0 || !price.Currency.Valid() {
+		return fmt.Errorf("%w: the price must not be negative and in a known currency", model.ErrInvalidInput)
+	}
+
 	return nil
 }
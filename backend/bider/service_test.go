@@ -0,0 +1,106 @@
+package bider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) List(input model.BiddersInput) ([]model.Bidder, error) {
+	args := m.Called(input)
+
+	return args.Get(0).([]model.Bidder), args.Error(1)
+}
+
+func (m *MockRepo) Single(uuid string) (model.Bidder, error) {
+	args := m.Called(uuid)
+
+	return args.Get(0).(model.Bidder), args.Error(1)
+}
+
+func (m *MockRepo) Upsert(input model.BiddersInput) (string, error) {
+	args := m.Called(input)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepo) Delete(id string) error {
+	args := m.Called(id)
+
+	return args.Error(0)
+}
+
+func (m *MockRepo) SetReputation(input model.ReputationInput) error {
+	args := m.Called(input)
+
+	return args.Error(0)
+}
+
+func TestService_CreateBidder(t *testing.T) {
+	repo := new(MockRepo)
+	service := NewService(repo, zap.NewNop())
+	input := model.BiddersInput{Uuid: "ignored", UserUuid: "user-uuid", Name: "name", Item: "item", Price: money.Money{Amount: 1250}}
+	expected := model.BiddersInput{UserUuid: "user-uuid", Name: "name", Item: "item", Price: money.Money{Amount: 1250, Currency: money.Default}}
+
+	repo.On("Upsert", expected).Return("bidder-uuid", nil)
+
+	result, err := service.CreateBidder(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bidder-uuid", result)
+	repo.AssertExpectations(t)
+}
+
+func TestService_CreateBidderInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input model.BiddersInput
+	}{
+		{name: "missing name", input: model.BiddersInput{UserUuid: "user-uuid", Item: "item"}},
+		{name: "missing user", input: model.BiddersInput{Name: "name", Item: "item"}},
+		{name: "negative price", input: model.BiddersInput{UserUuid: "user-uuid", Name: "name", Price: money.Money{Amount: -1, Currency: money.EUR}}},
+		{name: "unknown currency", input: model.BiddersInput{UserUuid: "user-uuid", Name: "name", Price: money.Money{Amount: 1, Currency: "XXX"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := new(MockRepo)
+			service := NewService(repo, zap.NewNop())
+
+			_, err := service.CreateBidder(test.input)
+
+			assert.ErrorIs(t, err, model.ErrInvalidInput)
+			repo.AssertNotCalled(t, "Upsert", mock.Anything)
+		})
+	}
+}
+
+func TestService_UpdateBidderWithoutUuid(t *testing.T) {
+	repo := new(MockRepo)
+	service := NewService(repo, zap.NewNop())
+
+	_, err := service.UpdateBidder(model.BiddersInput{Name: "name"})
+
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	repo.AssertNotCalled(t, "Upsert", mock.Anything)
+}
+
+func TestService_DeleteNotFound(t *testing.T) {
+	repo := new(MockRepo)
+	service := NewService(repo, zap.NewNop())
+
+	repo.On("Delete", "missing-uuid").Return(model.ErrNotFound)
+
+	err := service.Delete("missing-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
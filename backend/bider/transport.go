@@ -0,0 +1,154 @@
+package bider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/julienschmidt/httprouter"
+	"github.com/labstack/gommon/log"
+
+	"github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+func NewTransport(s SService, router *httprouter.Router) Transport {
+
+	transport := Transport{
+		router: router,
+		s:      s,
+	}
+	RegisterRoutes(router, s) // Register routes during initialization
+	return transport
+}
+
+type Transport struct {
+	router *httprouter.Router
+	s      SService
+}
+
+func (t *Transport) ListenAndServe(port string) {
+	log.Printf("Starting server on port %s...", port)
+	err := http.ListenAndServe(":"+port, t.router)
+	if err != nil {
+		log.Fatalf("Server failed to start: %v", err)
+	}
+}
+
+func RegisterRoutes(router *httprouter.Router, s SService) {
+	options := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	listBiddersHandler := kithttp.NewServer(
+		MakeEndpointListBidders(s),
+		decodeListBiddersRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	getBidderHandler := kithttp.NewServer(
+		MakeEndpointGetBidder(s),
+		decodeGetBidderRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	createBidderHandler := kithttp.NewServer(
+		MakeEndpointCreateBidder(s),
+		decodeUpsertBidderRequest,
+		encodeCreateBidderResponse,
+		options...,
+	)
+
+	updateBidderHandler := kithttp.NewServer(
+		MakeEndpointUpdateBidder(s),
+		decodeUpsertBidderRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	deleteBidderHandler := kithttp.NewServer(
+		MakeEndpointDeleteBidder(s),
+		decodeGetBidderRequest,
+		encodeDeleteBidderResponse,
+		options...,
+	)
+
+	router.Handler(http.MethodGet, "/bidders", listBiddersHandler)
+	router.Handler(http.MethodGet, "/bidders/:uuid", getBidderHandler)
+	router.Handler(http.MethodPost, "/bidders", createBidderHandler)
+	router.Handler(http.MethodPut, "/bidders/:uuid", updateBidderHandler)
+	router.Handler(http.MethodDelete, "/bidders/:uuid", deleteBidderHandler)
+}
+
+func encodeError(ctx context.Context, err error, writer http.ResponseWriter) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	json.NewEncoder(writer).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func decodeListBiddersRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.BiddersInput
+	queryParams := r.URL.Query()
+
+	input.Name = queryParams.Get("name")
+	input.Item = queryParams.Get("item")
+	input.MinRating, _ = strconv.ParseFloat(queryParams.Get("minRating"), 64)
+	input.Page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	input.Page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	return ListBiddersRequest{
+		input: input,
+	}, nil
+}
+
+func decodeGetBidderRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+
+	return GetBidderRequest{
+		Uuid: params.ByName("uuid"),
+	}, nil
+}
+
+// decodeUpsertBidderRequest - the uuid of the bidder to update is taken from the path, a created bidder gets a new one
+func decodeUpsertBidderRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.BiddersInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	input.Uuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return UpsertBidderRequest{
+		bidder: input,
+	}, nil
+}
+
+func encodeCreateBidderResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+
+	return json.NewEncoder(writer).Encode(response)
+}
+
+func encodeDeleteBidderResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
+	writer.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
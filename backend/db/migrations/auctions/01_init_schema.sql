@@ -0,0 +1,19 @@
+-- +goose Up
+
+create table if not exists auctions
+(
+    id                bigint auto_increment primary key,
+    uuid              char(36)     not null unique key,
+    item              varchar(255) not null default '',
+    price             bigint       not null default 0,
+    winning_price     bigint       not null default 0,
+    user_uuid         char(36)     not null,
+    bidders_count     bigint       not null default 0,
+    bidders_threshold bigint       not null default 0,
+    status            int          not null default 0,
+    expired_at        datetime     not null,
+    created_at        timestamp    not null default current_timestamp,
+    updated_at        timestamp    not null default current_timestamp,
+    index auctions_user_uuid (user_uuid),
+    index auctions_status_expired_at (status, expired_at)
+);
@@ -0,0 +1,4 @@
+-- +goose Up
+
+alter table auctions
+    add column winner_uuid char(36) not null default '' after winning_price;
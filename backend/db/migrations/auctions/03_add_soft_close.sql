@@ -0,0 +1,11 @@
+-- +goose Up
+
+alter table auctions
+    add column original_expired_at datetime null after expired_at;
+
+update auctions
+set original_expired_at = expired_at
+where original_expired_at is null;
+
+alter table auctions
+    modify column original_expired_at datetime not null;
@@ -0,0 +1,24 @@
+-- +goose Up
+
+create table if not exists bids
+(
+    id           bigint auto_increment primary key,
+    uuid         char(36)  not null unique key,
+    auction_uuid char(36)  not null,
+    bidder_uuid  char(36)  not null,
+    amount       bigint    not null,
+    proxy        boolean   not null default false,
+    created_at   timestamp(6) not null default current_timestamp(6),
+    index bids_auction_uuid (auction_uuid, id)
+);
+
+create table if not exists proxy_bids
+(
+    id           bigint auto_increment primary key,
+    auction_uuid char(36)  not null,
+    bidder_uuid  char(36)  not null,
+    max_amount   bigint    not null,
+    created_at   timestamp(6) not null default current_timestamp(6),
+    updated_at   timestamp(6) not null default current_timestamp(6),
+    unique key proxy_bids_auction_bidder (auction_uuid, bidder_uuid)
+);
@@ -0,0 +1,4 @@
+-- +goose Up
+
+alter table auctions
+    add column type int not null default 0 after status;
@@ -0,0 +1,6 @@
+-- +goose Up
+
+alter table auctions
+    add column floor_price           bigint not null default 0 after type,
+    add column price_step            bigint not null default 0 after floor_price,
+    add column step_interval_seconds bigint not null default 0 after price_step;
@@ -0,0 +1,5 @@
+-- +goose Up
+
+alter table auctions
+    add column reserve_price bigint not null default 0 after step_interval_seconds,
+    add column buy_now_price bigint not null default 0 after reserve_price;
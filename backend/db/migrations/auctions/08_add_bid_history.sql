@@ -0,0 +1,25 @@
+-- +goose Up
+
+create table if not exists bid_retractions
+(
+    id         bigint auto_increment primary key,
+    bid_uuid   char(36)     not null unique key,
+    reason     varchar(255) not null default '',
+    created_at timestamp(6) not null default current_timestamp(6)
+);
+
+-- +goose StatementBegin
+create trigger bids_no_update
+    before update
+    on bids
+    for each row
+    signal sqlstate '45000' set message_text = 'bids are append only';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger bids_no_delete
+    before delete
+    on bids
+    for each row
+    signal sqlstate '45000' set message_text = 'bids are append only';
+-- +goose StatementEnd
@@ -0,0 +1,4 @@
+-- +goose Up
+
+alter table auctions
+    add column ending_notified_at datetime null after original_expired_at;
@@ -0,0 +1,21 @@
+-- +goose Up
+
+create table if not exists auction_events
+(
+    id                  bigint auto_increment primary key,
+    uuid                char(36)     not null unique key,
+    title               varchar(255) not null default '',
+    user_uuid           char(36)     not null,
+    first_lot_closes_at datetime     not null,
+    stagger_seconds     bigint       not null default 0,
+    lots_count          bigint       not null default 0,
+    created_at          timestamp    not null default current_timestamp,
+    updated_at          timestamp    not null default current_timestamp,
+    index auction_events_user_uuid (user_uuid)
+);
+
+alter table auctions
+    add column event_uuid char(36) not null default '' after buy_now_price,
+    add column lot_number bigint   not null default 0 after event_uuid,
+    add column item_uuid  char(36) not null default '' after lot_number,
+    add index auctions_event_uuid_lot_number (event_uuid, lot_number);
@@ -0,0 +1,7 @@
+-- +goose Up
+
+alter table auctions
+    add column parent_uuid  char(36)    not null default '' after item_uuid,
+    add column origin       varchar(16) not null default '' after parent_uuid,
+    add column relist_count bigint      not null default 0 after origin,
+    add index auctions_parent_uuid (parent_uuid);
@@ -0,0 +1,25 @@
+-- +goose Up
+
+create table if not exists increment_tables
+(
+    id         bigint auto_increment primary key,
+    uuid       char(36)     not null unique key,
+    name       varchar(255) not null default '',
+    category   varchar(64)  not null default '',
+    created_at timestamp    not null default current_timestamp,
+    index increment_tables_category (category)
+);
+
+create table if not exists increment_bands
+(
+    id         bigint auto_increment primary key,
+    table_uuid char(36) not null,
+    position   int      not null,
+    below      bigint   not null default 0,
+    increment  bigint   not null,
+    unique key increment_bands_table_position (table_uuid, position)
+);
+
+alter table auctions
+    add column category             varchar(64) not null default '' after relist_count,
+    add column increment_table_uuid char(36)    not null default '' after category;
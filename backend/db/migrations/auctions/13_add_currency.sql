@@ -0,0 +1,4 @@
+-- +goose Up
+
+alter table auctions
+    add column currency char(3) not null default 'USD' after increment_table_uuid;
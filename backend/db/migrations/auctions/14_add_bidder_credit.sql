@@ -0,0 +1,21 @@
+-- +goose Up
+
+create table if not exists bidder_credit
+(
+    bidder_uuid           char(36)  not null primary key,
+    credit_limit_amount   bigint    not null default 0,
+    credit_limit_currency char(3)   not null default 'USD',
+    deposit_amount        bigint    not null default 0,
+    deposit_currency      char(3)   not null default 'USD',
+    deposit_required      boolean   not null default false,
+    updated_at            timestamp not null default current_timestamp
+);
+
+alter table auctions
+    add index auctions_winner_uuid_status (winner_uuid, status);
+
+alter table bids
+    add index bids_bidder_uuid (bidder_uuid);
+
+alter table proxy_bids
+    add index proxy_bids_bidder_uuid (bidder_uuid);
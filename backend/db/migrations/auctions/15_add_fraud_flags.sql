@@ -0,0 +1,24 @@
+-- +goose Up
+
+alter table bids
+    add column ip varchar(45) not null default '',
+    add index bids_ip_created_at (ip, created_at),
+    add index bids_created_at (created_at);
+
+create table if not exists fraud_flags
+(
+    id            bigint auto_increment primary key,
+    uuid          char(36)                                not null unique key,
+    subject       enum ('auction', 'bidder')              not null,
+    subject_uuid  char(36)                                not null,
+    `signal`      varchar(64)                             not null,
+    score         bigint                                  not null default 0,
+    details       text                                    not null,
+    status        enum ('open', 'confirmed', 'dismissed') not null default 'open',
+    reviewer_uuid char(36)                                not null default '',
+    created_at    timestamp                               not null default current_timestamp,
+    updated_at    timestamp                               not null default current_timestamp,
+    reviewed_at   timestamp                               null,
+    unique key fraud_flags_subject_signal (subject, subject_uuid, `signal`),
+    index fraud_flags_status_created_at (status, created_at)
+);
@@ -0,0 +1,26 @@
+-- +goose Up
+
+create table if not exists feedback
+(
+    id           bigint auto_increment primary key,
+    uuid         char(36)                  not null unique key,
+    auction_uuid char(36)                  not null,
+    author_uuid  char(36)                  not null,
+    subject_uuid char(36)                  not null,
+    subject_role enum ('buyer', 'seller')  not null,
+    rating       tinyint                   not null,
+    comment      varchar(1000)             not null default '',
+    created_at   timestamp                 not null default current_timestamp,
+    unique key feedback_auction_author (auction_uuid, author_uuid),
+    index feedback_subject_uuid (subject_uuid)
+);
+
+create table if not exists reputations
+(
+    user_uuid    char(36)  not null primary key,
+    seller_count bigint    not null default 0,
+    seller_sum   bigint    not null default 0,
+    buyer_count  bigint    not null default 0,
+    buyer_sum    bigint    not null default 0,
+    updated_at   timestamp not null default current_timestamp
+);
@@ -0,0 +1,6 @@
+-- +goose Up
+
+-- an expired auction is relisted at most once, clones of it are not limited
+alter table auctions
+    add column relisted_from char(36) as (if(origin = 'relist', parent_uuid, null)) stored after relist_count,
+    add unique key auctions_relisted_from (relisted_from);
@@ -0,0 +1,5 @@
+-- +goose Up
+
+-- the fraud scanner looks for the auctions changed since its last scan
+alter table auctions
+    add index auctions_updated_at (updated_at);
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- the feedback window starts at the sale, a later update of a sold auction does not move it. the auctions sold
+-- before have their last update as the closest known time of the sale
+alter table auctions
+    add column sold_at timestamp null after original_expired_at;
+
+update auctions
+set sold_at = updated_at
+where status = 1;
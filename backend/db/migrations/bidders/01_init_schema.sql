@@ -0,0 +1,20 @@
+-- +goose Up
+
+create table if not exists bidders
+(
+    id             bigint auto_increment primary key,
+    uuid           char(36)     not null unique key,
+    user_uuid      char(36)     not null default '',
+    name           varchar(255) not null default '',
+    item           varchar(255) not null default '',
+    description    varchar(255) not null default '',
+    price_amount   bigint       not null default 0,
+    price_currency char(3)      not null default 'USD',
+    rating_count   bigint       not null default 0,
+    rating_score   double       not null default 0,
+    created_at     timestamp    not null default current_timestamp,
+    updated_at     timestamp    not null default current_timestamp,
+    index bidders_name (name),
+    index bidders_item (item),
+    index bidders_rating_score (rating_score)
+);
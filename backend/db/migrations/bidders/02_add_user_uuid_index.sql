@@ -0,0 +1,4 @@
+-- +goose Up
+
+alter table bidders
+    add index bidders_user_uuid (user_uuid);
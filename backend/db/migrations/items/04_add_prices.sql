@@ -0,0 +1,18 @@
+-- +goose Up
+
+alter table items
+    add column price_amount   bigint  not null default 0,
+    add column price_currency char(3) not null default 'USD';
+
+-- the old price is a free text amount in dollars, it is kept in cents. prices which are not a plain
+-- amount can not be read and are left at 0
+update items
+set price_amount = round(cast(trim(price) as decimal(20, 2)) * 100)
+where trim(price) regexp '^[0-9]+(\\.[0-9]{1,2})?$';
+
+alter table items
+    drop column price;
+
+alter table bidders
+    add column price_amount   bigint  not null default 0,
+    add column price_currency char(3) not null default 'USD';
@@ -0,0 +1,6 @@
+-- +goose Up
+
+alter table bidders
+    add column rating_count bigint not null default 0,
+    add column rating_score double not null default 0,
+    add index bidders_rating_score (rating_score);
@@ -0,0 +1,32 @@
+-- +goose Up
+
+create table if not exists threads
+(
+    id            bigint auto_increment primary key,
+    uuid          char(36)    not null unique key,
+    scope         varchar(16) not null,
+    scope_uuid    char(36)    not null,
+    seller_uuid   char(36)    not null,
+    asker_uuid    char(36)    not null,
+    visibility    varchar(16) not null default 'public',
+    asker_unread  int         not null default 0,
+    seller_unread int         not null default 0,
+    created_at    timestamp   not null default current_timestamp,
+    updated_at    timestamp   not null default current_timestamp,
+    index threads_scope (scope, scope_uuid, updated_at),
+    index threads_asker_uuid (asker_uuid, updated_at),
+    index threads_seller_uuid (seller_uuid, updated_at)
+);
+
+create table if not exists messages
+(
+    id          bigint auto_increment primary key,
+    uuid        char(36)  not null unique key,
+    thread_uuid char(36)  not null,
+    sender_uuid char(36)  not null,
+    body        text      not null,
+    moderated   boolean   not null default false,
+    created_at  timestamp not null default current_timestamp,
+    read_at     datetime  null,
+    index messages_thread_uuid (thread_uuid, id)
+);
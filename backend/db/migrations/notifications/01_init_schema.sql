@@ -0,0 +1,25 @@
+-- +goose Up
+
+create table if not exists watchlist
+(
+    id           bigint auto_increment primary key,
+    user_uuid    char(36)  not null,
+    auction_uuid char(36)  not null,
+    created_at   timestamp not null default current_timestamp,
+    unique key watchlist_user_auction (user_uuid, auction_uuid),
+    index watchlist_auction_uuid (auction_uuid)
+);
+
+create table if not exists notifications
+(
+    id           bigint auto_increment primary key,
+    uuid         char(36)    not null unique key,
+    user_uuid    char(36)    not null,
+    auction_uuid char(36)    not null,
+    kind         varchar(32) not null,
+    amount       bigint      not null default 0,
+    expired_at   datetime    not null,
+    created_at   timestamp   not null default current_timestamp,
+    read_at      datetime    null,
+    index notifications_user_uuid (user_uuid, id)
+);
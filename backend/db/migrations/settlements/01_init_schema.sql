@@ -0,0 +1,36 @@
+-- +goose Up
+
+create table if not exists invoices
+(
+    id                bigint auto_increment primary key,
+    uuid              char(36)     not null unique key,
+    auction_uuid      char(36)     not null unique key,
+    buyer_uuid        char(36)     not null,
+    seller_uuid       char(36)     not null,
+    amount            bigint       not null,
+    fee               bigint       not null default 0,
+    currency          char(3)      not null,
+    status            varchar(16)  not null,
+    payment_reference varchar(255) not null default '',
+    created_at        timestamp    not null default current_timestamp,
+    updated_at        timestamp    not null default current_timestamp,
+    paid_at           datetime     null,
+    index invoices_buyer_uuid (buyer_uuid, id),
+    index invoices_seller_uuid (seller_uuid, id)
+);
+
+create table if not exists ledger_entries
+(
+    id               bigint auto_increment primary key,
+    uuid             char(36)     not null unique key,
+    transaction_uuid char(36)     not null,
+    invoice_uuid     char(36)     not null,
+    account          varchar(128) not null,
+    direction        varchar(8)   not null,
+    amount           bigint       not null,
+    currency         char(3)      not null,
+    memo             varchar(255) not null default '',
+    created_at       timestamp    not null default current_timestamp,
+    index ledger_entries_invoice_uuid (invoice_uuid, id),
+    index ledger_entries_account (account, id)
+);
@@ -0,0 +1,4 @@
+-- +goose Up
+
+alter table invoices
+    add column fee_rule varchar(255) not null default '' after fee;
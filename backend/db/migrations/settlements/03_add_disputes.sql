@@ -0,0 +1,34 @@
+-- +goose Up
+
+create table if not exists disputes
+(
+    id           bigint auto_increment primary key,
+    uuid         char(36)    not null unique key,
+    invoice_uuid char(36)    not null unique key,
+    auction_uuid char(36)    not null,
+    buyer_uuid   char(36)    not null,
+    seller_uuid  char(36)    not null,
+    reason       text        not null,
+    status       varchar(32) not null,
+    deadline     datetime    null,
+    created_at   timestamp   not null default current_timestamp,
+    updated_at   timestamp   not null default current_timestamp,
+    resolved_at  datetime    null,
+    index disputes_buyer_uuid (buyer_uuid, id),
+    index disputes_seller_uuid (seller_uuid, id),
+    index disputes_deadline (status, deadline)
+);
+
+create table if not exists dispute_evidence
+(
+    id            bigint auto_increment primary key,
+    uuid          char(36)     not null unique key,
+    dispute_uuid  char(36)     not null,
+    uploader_uuid char(36)     not null,
+    name          varchar(255) not null,
+    content_type  varchar(128) not null default '',
+    size          bigint       not null,
+    object_key    varchar(255) not null,
+    created_at    timestamp    not null default current_timestamp,
+    index dispute_evidence_dispute_uuid (dispute_uuid, id)
+);
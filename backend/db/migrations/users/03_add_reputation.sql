@@ -0,0 +1,5 @@
+-- +goose Up
+
+alter table users
+    add column rating_count bigint not null default 0,
+    add column rating_score double not null default 0;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- the reputation of the user as a seller and as a buyer, next to the overall one
+alter table users
+    add column seller_count bigint not null default 0,
+    add column seller_score double not null default 0,
+    add column buyer_count  bigint not null default 0,
+    add column buyer_score  double not null default 0;
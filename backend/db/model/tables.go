@@ -1,9 +1,27 @@
 package model
 
 const (
-	Users     = "users"
-	Auctions  = "auctions"
-	Bidders   = "bidders"
-	LockTable = "lock_table"
-	PgLockes  = "pg_locks"
+	Users           = "users"
+	Auctions        = "auctions"
+	Bidders         = "bidders"
+	Bids            = "bids"
+	BidRetractions  = "bid_retractions"
+	AuctionEvents   = "auction_events"
+	IncrementTables = "increment_tables"
+	IncrementBands  = "increment_bands"
+	ProxyBids       = "proxy_bids"
+	BidderCredit    = "bidder_credit"
+	FraudFlags      = "fraud_flags"
+	Feedback        = "feedback"
+	Reputations     = "reputations"
+	Watchlist       = "watchlist"
+	Notifications   = "notifications"
+	Threads         = "threads"
+	Messages        = "messages"
+	Invoices        = "invoices"
+	LedgerEntries   = "ledger_entries"
+	Disputes        = "disputes"
+	DisputeEvidence = "dispute_evidence"
+	LockTable       = "lock_table"
+	PgLockes        = "pg_locks"
 )
@@ -0,0 +1,263 @@
+package fraud
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ireuven89/hello-world/backend/fraud/model"
+)
+
+// Config - the score of every signal and the thresholds that raise them. a bidder is bidding up a seller after
+// BidUpMinAuctions closed auctions of the seller without a win, ten by default since a loyal bidder of a seller
+// loses a few of its auctions. a burst is BurstMinBids bids of BurstMinBidders bidders or more from one address
+// within BurstWindowSeconds. the findings scoring less than FlagScore are dropped
+type Config struct {
+	LinkedScore        int64 `json:"linkedScore"`
+	BidUpMinAuctions   int   `json:"bidUpMinAuctions"`
+	BidUpScore         int64 `json:"bidUpScore"`
+	BurstWindowSeconds int64 `json:"burstWindowSeconds"`
+	BurstMinBids       int   `json:"burstMinBids"`
+	BurstMinBidders    int   `json:"burstMinBidders"`
+	BurstScore         int64 `json:"burstScore"`
+	FlagScore          int64 `json:"flagScore"`
+}
+
+const (
+	defaultLinkedScore      = 80
+	defaultBidUpMinAuctions = 10
+	defaultBidUpScore       = 50
+	defaultBurstWindow      = 60
+	defaultBurstMinBids     = 5
+	defaultBurstMinBidders  = 2
+	defaultBurstScore       = 40
+	defaultFlagScore        = 50
+	// maxDetails - how many findings of the same subject and signal are described in a flag
+	maxDetails = 3
+)
+
+// WithDefaults - the config with the unset values set to their defaults
+func (c Config) WithDefaults() Config {
+	if c.LinkedScore <= 0 {
+		c.LinkedScore = defaultLinkedScore
+	}
+
+	if c.BidUpMinAuctions <= 0 {
+		c.BidUpMinAuctions = defaultBidUpMinAuctions
+	}
+
+	if c.BidUpScore <= 0 {
+		c.BidUpScore = defaultBidUpScore
+	}
+
+	if c.BurstWindowSeconds <= 0 {
+		c.BurstWindowSeconds = defaultBurstWindow
+	}
+
+	if c.BurstMinBids <= 0 {
+		c.BurstMinBids = defaultBurstMinBids
+	}
+
+	if c.BurstMinBidders <= 0 {
+		c.BurstMinBidders = defaultBurstMinBidders
+	}
+
+	if c.BurstScore <= 0 {
+		c.BurstScore = defaultBurstScore
+	}
+
+	if c.FlagScore <= 0 {
+		c.FlagScore = defaultFlagScore
+	}
+
+	return c
+}
+
+// Analyzer - scores the auctions and the bidders of a bid history
+type Analyzer struct {
+	config Config
+}
+
+func New(config Config) *Analyzer {
+
+	return &Analyzer{config: config.WithDefaults()}
+}
+
+type findingKey struct {
+	subject     model.Subject
+	subjectUuid string
+	signal      model.Signal
+}
+
+// findings - the findings of one analysis, the findings of the same subject and signal add up
+type findings struct {
+	scores  map[findingKey]int64
+	details map[findingKey][]string
+	order   []findingKey
+}
+
+func (f *findings) add(subject model.Subject, subjectUuid string, signal model.Signal, score int64, details string) {
+	key := findingKey{subject: subject, subjectUuid: subjectUuid, signal: signal}
+
+	if _, ok := f.scores[key]; !ok {
+		f.order = append(f.order, key)
+	}
+
+	f.scores[key] += score
+	if len(f.details[key]) < maxDetails {
+		f.details[key] = append(f.details[key], details)
+	}
+}
+
+// Analyze - looks for the seller linked bidders, the bidders bidding up a seller and the bid bursts of shared
+// addresses in the bid history, and returns the findings scoring at least FlagScore in the order they were found
+func (a *Analyzer) Analyze(bids []model.BidRecord) []model.Finding {
+	found := &findings{scores: make(map[findingKey]int64), details: make(map[findingKey][]string)}
+
+	a.sellerLinked(bids, found)
+	a.bidUp(bids, found)
+	a.bursts(bids, found)
+
+	var result []model.Finding
+	for _, key := range found.order {
+		if found.scores[key] < a.config.FlagScore {
+			continue
+		}
+
+		result = append(result, model.Finding{
+			Subject:     key.subject,
+			SubjectUuid: key.subjectUuid,
+			Signal:      key.signal,
+			Score:       found.scores[key],
+			Details:     strings.Join(found.details[key], "; "),
+		})
+	}
+
+	return result
+}
+
+// sellerLinked - a bidder who bid from an address any bidder of the seller of the auction used to bid anywhere,
+// the addresses are gathered by user since the seller is a user and bids through its bidders
+func (a *Analyzer) sellerLinked(bids []model.BidRecord, found *findings) {
+	addresses := make(map[string]map[string]bool)
+	for _, bid := range bids {
+		if bid.IP == "" || bid.BidderUserUuid == "" {
+			continue
+		}
+
+		if addresses[bid.BidderUserUuid] == nil {
+			addresses[bid.BidderUserUuid] = make(map[string]bool)
+		}
+		addresses[bid.BidderUserUuid][bid.IP] = true
+	}
+
+	linked := make(map[[2]string]bool)
+	for _, bid := range bids {
+		pair := [2]string{bid.AuctionUuid, bid.BidderUuid}
+		if bid.IP == "" || bid.BidderUserUuid == bid.SellerUuid || linked[pair] || !addresses[bid.SellerUuid][bid.IP] {
+			continue
+		}
+		linked[pair] = true
+
+		found.add(model.BidderSubject, bid.BidderUuid, model.SellerLinked, a.config.LinkedScore,
+			fmt.Sprintf("bid on auction %s from an address of its seller %s", bid.AuctionUuid, bid.SellerUuid))
+		found.add(model.AuctionSubject, bid.AuctionUuid, model.SellerLinked, a.config.LinkedScore,
+			fmt.Sprintf("bidder %s bid from an address of the seller", bid.BidderUuid))
+	}
+}
+
+// bidUp - a bidder who bid on BidUpMinAuctions closed auctions of the same seller or more and won none
+func (a *Analyzer) bidUp(bids []model.BidRecord, found *findings) {
+	type participation struct {
+		auctions map[string]bool
+		won      bool
+	}
+
+	pairs := make(map[[2]string]*participation)
+	var order [][2]string
+	for _, bid := range bids {
+		if !bid.Closed {
+			continue
+		}
+
+		pair := [2]string{bid.SellerUuid, bid.BidderUuid}
+		if pairs[pair] == nil {
+			pairs[pair] = &participation{auctions: make(map[string]bool)}
+			order = append(order, pair)
+		}
+
+		pairs[pair].auctions[bid.AuctionUuid] = true
+		pairs[pair].won = pairs[pair].won || bid.WinnerUuid == bid.BidderUuid
+	}
+
+	for _, pair := range order {
+		p := pairs[pair]
+		if p.won || len(p.auctions) < a.config.BidUpMinAuctions {
+			continue
+		}
+
+		found.add(model.BidderSubject, pair[1], model.BidUp, a.config.BidUpScore,
+			fmt.Sprintf("bid on %d auctions of seller %s and won none", len(p.auctions), pair[0]))
+	}
+}
+
+// bursts - BurstMinBids bids or more of BurstMinBidders bidders or more on one auction from one address
+// within the burst window
+func (a *Analyzer) bursts(bids []model.BidRecord, found *findings) {
+	window := time.Duration(a.config.BurstWindowSeconds) * time.Second
+
+	groups := make(map[[2]string][]model.BidRecord)
+	var order [][2]string
+	for _, bid := range bids {
+		if bid.IP == "" {
+			continue
+		}
+
+		key := [2]string{bid.AuctionUuid, bid.IP}
+		if groups[key] == nil {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], bid)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].CreatedAt.Before(group[j].CreatedAt) })
+
+		start := 0
+		for end := range group {
+			for group[end].CreatedAt.Sub(group[start].CreatedAt) > window {
+				start++
+			}
+
+			burst := group[start : end+1]
+			bidders := distinctBidders(burst)
+			if len(burst) < a.config.BurstMinBids || len(bidders) < a.config.BurstMinBidders {
+				continue
+			}
+
+			found.add(model.AuctionSubject, key[0], model.SharedAddressBurst, a.config.BurstScore,
+				fmt.Sprintf("%d bids of %d bidders from one address within %s", len(burst), len(bidders), window))
+			for _, bidder := range bidders {
+				found.add(model.BidderSubject, bidder, model.SharedAddressBurst, a.config.BurstScore,
+					fmt.Sprintf("bid on auction %s from an address shared with %d other bidders", key[0], len(bidders)-1))
+			}
+			break
+		}
+	}
+}
+
+func distinctBidders(bids []model.BidRecord) []string {
+	var result []string
+	seen := make(map[string]bool)
+
+	for _, bid := range bids {
+		if !seen[bid.BidderUuid] {
+			seen[bid.BidderUuid] = true
+			result = append(result, bid.BidderUuid)
+		}
+	}
+
+	return result
+}
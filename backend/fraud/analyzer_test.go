@@ -0,0 +1,146 @@
+package fraud
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ireuven89/hello-world/backend/fraud/model"
+)
+
+var start = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestAnalyzer_SellerLinked(t *testing.T) {
+	bids := []model.BidRecord{
+		{AuctionUuid: "other-auction", SellerUuid: "other-seller", BidderUuid: "seller-bidder", BidderUserUuid: "seller-uuid", IP: "10.0.0.1", CreatedAt: start},
+		{AuctionUuid: "auction-uuid", SellerUuid: "seller-uuid", BidderUuid: "shill-uuid", BidderUserUuid: "shill-user", IP: "10.0.0.1", CreatedAt: start},
+		{AuctionUuid: "auction-uuid", SellerUuid: "seller-uuid", BidderUuid: "shill-uuid", BidderUserUuid: "shill-user", IP: "10.0.0.1", CreatedAt: start.Add(time.Minute)},
+		{AuctionUuid: "auction-uuid", SellerUuid: "seller-uuid", BidderUuid: "honest-uuid", BidderUserUuid: "honest-user", IP: "10.0.0.2", CreatedAt: start},
+		{AuctionUuid: "other-auction", SellerUuid: "other-seller", BidderUuid: "seller-uuid", BidderUserUuid: "honest-user", IP: "10.0.0.3", CreatedAt: start},
+		{AuctionUuid: "auction-uuid", SellerUuid: "seller-uuid", BidderUuid: "honest-uuid", BidderUserUuid: "honest-user", IP: "10.0.0.3", CreatedAt: start},
+	}
+
+	result := New(Config{}).Analyze(bids)
+
+	assert.Equal(t, []model.Finding{
+		{Subject: model.BidderSubject, SubjectUuid: "shill-uuid", Signal: model.SellerLinked, Score: 80,
+			Details: "bid on auction auction-uuid from an address of its seller seller-uuid"},
+		{Subject: model.AuctionSubject, SubjectUuid: "auction-uuid", Signal: model.SellerLinked, Score: 80,
+			Details: "bidder shill-uuid bid from an address of the seller"},
+	}, result)
+}
+
+func TestAnalyzer_BidUp(t *testing.T) {
+	closed := func(auctionUuid, bidderUuid, winnerUuid string) model.BidRecord {
+		return model.BidRecord{AuctionUuid: auctionUuid, SellerUuid: "seller-uuid", WinnerUuid: winnerUuid, Closed: true, BidderUuid: bidderUuid, CreatedAt: start}
+	}
+
+	tests := []struct {
+		name string
+		bids []model.BidRecord
+		want int
+	}{
+		{"never wins", []model.BidRecord{
+			closed("auction-a", "shill-uuid", "buyer-uuid"),
+			closed("auction-b", "shill-uuid", "buyer-uuid"),
+			closed("auction-b", "shill-uuid", "buyer-uuid"),
+			closed("auction-c", "shill-uuid", "other-uuid"),
+		}, 1},
+		{"won once", []model.BidRecord{
+			closed("auction-a", "shill-uuid", "buyer-uuid"),
+			closed("auction-b", "shill-uuid", "shill-uuid"),
+			closed("auction-c", "shill-uuid", "other-uuid"),
+		}, 0},
+		{"too few auctions", []model.BidRecord{
+			closed("auction-a", "shill-uuid", "buyer-uuid"),
+			closed("auction-b", "shill-uuid", "buyer-uuid"),
+		}, 0},
+		{"auctions still open", []model.BidRecord{
+			closed("auction-a", "shill-uuid", "buyer-uuid"),
+			closed("auction-b", "shill-uuid", "buyer-uuid"),
+			{AuctionUuid: "auction-c", SellerUuid: "seller-uuid", BidderUuid: "shill-uuid", CreatedAt: start},
+		}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := New(Config{BidUpMinAuctions: 3}).Analyze(test.bids)
+
+			assert.Len(t, result, test.want)
+			for _, finding := range result {
+				assert.Equal(t, model.BidUp, finding.Signal)
+				assert.Equal(t, "shill-uuid", finding.SubjectUuid)
+				assert.Equal(t, "bid on 3 auctions of seller seller-uuid and won none", finding.Details)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_BidUpDefaultMinAuctions(t *testing.T) {
+	var bids []model.BidRecord
+	for i := range 9 {
+		bids = append(bids, model.BidRecord{AuctionUuid: fmt.Sprintf("auction-%d", i), SellerUuid: "seller-uuid", WinnerUuid: "buyer-uuid", Closed: true, BidderUuid: "bidder-uuid", CreatedAt: start})
+	}
+
+	assert.Empty(t, New(Config{}).Analyze(bids))
+
+	bids = append(bids, model.BidRecord{AuctionUuid: "auction-9", SellerUuid: "seller-uuid", WinnerUuid: "buyer-uuid", Closed: true, BidderUuid: "bidder-uuid", CreatedAt: start})
+
+	assert.Len(t, New(Config{}).Analyze(bids), 1)
+}
+
+func TestAnalyzer_SharedAddressBurst(t *testing.T) {
+	bid := func(bidderUuid string, at time.Duration) model.BidRecord {
+		return model.BidRecord{AuctionUuid: "auction-uuid", SellerUuid: "seller-uuid", BidderUuid: bidderUuid, IP: "10.0.0.1", CreatedAt: start.Add(at)}
+	}
+
+	tests := []struct {
+		name string
+		bids []model.BidRecord
+		want []string
+	}{
+		{"burst", []model.BidRecord{
+			bid("bidder-a", 0), bid("bidder-b", 10*time.Second), bid("bidder-a", 20*time.Second),
+			bid("bidder-b", 30*time.Second), bid("bidder-a", 40*time.Second),
+		}, []string{"auction-uuid", "bidder-a", "bidder-b"}},
+		{"spread out", []model.BidRecord{
+			bid("bidder-a", 0), bid("bidder-b", time.Minute), bid("bidder-a", 2*time.Minute),
+			bid("bidder-b", 3*time.Minute), bid("bidder-a", 4*time.Minute),
+		}, nil},
+		{"a single bidder", []model.BidRecord{
+			bid("bidder-a", 0), bid("bidder-a", 10*time.Second), bid("bidder-a", 20*time.Second),
+			bid("bidder-a", 30*time.Second), bid("bidder-a", 40*time.Second),
+		}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := New(Config{FlagScore: 40}).Analyze(test.bids)
+
+			var subjects []string
+			for _, finding := range result {
+				assert.Equal(t, model.SharedAddressBurst, finding.Signal)
+				subjects = append(subjects, finding.SubjectUuid)
+			}
+			assert.Equal(t, test.want, subjects)
+		})
+	}
+}
+
+func TestAnalyzer_ScoresAddUp(t *testing.T) {
+	bids := []model.BidRecord{
+		{AuctionUuid: "own-auction", SellerUuid: "other-seller", BidderUuid: "seller-bidder", BidderUserUuid: "seller-uuid", IP: "10.0.0.1", CreatedAt: start},
+		{AuctionUuid: "auction-a", SellerUuid: "seller-uuid", BidderUuid: "shill-uuid", BidderUserUuid: "shill-user", IP: "10.0.0.1", CreatedAt: start},
+		{AuctionUuid: "auction-b", SellerUuid: "seller-uuid", BidderUuid: "shill-uuid", BidderUserUuid: "shill-user", IP: "10.0.0.1", CreatedAt: start},
+	}
+
+	result := New(Config{LinkedScore: 30}).Analyze(bids)
+
+	assert.Len(t, result, 1)
+	assert.Equal(t, "shill-uuid", result[0].SubjectUuid)
+	assert.Equal(t, int64(60), result[0].Score)
+	assert.Equal(t, "bid on auction auction-a from an address of its seller seller-uuid; "+
+		"bid on auction auction-b from an address of its seller seller-uuid", result[0].Details)
+}
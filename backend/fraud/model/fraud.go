@@ -0,0 +1,121 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound     = errors.New("flag not found")
+	ErrInvalidInput = errors.New("invalid flag input")
+)
+
+// Subject - what a flag is about, an auction or a bidder
+type Subject string
+
+const (
+	AuctionSubject Subject = "auction"
+	BidderSubject  Subject = "bidder"
+)
+
+// Signal - a suspicious pattern found in the bid history
+type Signal string
+
+const (
+	// SellerLinked - a bidder bid from an address the seller of the auction bid from, likely the seller in person
+	SellerLinked Signal = "seller_linked"
+	// BidUp - a bidder bid on many auctions of the same seller and won none of them
+	BidUp Signal = "bid_up"
+	// SharedAddressBurst - several bidders bid on the same auction from one address within a short window
+	SharedAddressBurst Signal = "shared_address_burst"
+)
+
+// BidRecord - a bid of the history with what the analyzer needs to know about its auction.
+// SellerUuid is a user, BidderUserUuid is the user the bidder bids for, filled in from the bidders
+// since they are kept apart from the bids
+type BidRecord struct {
+	AuctionUuid    string    `db:"auction_uuid"`
+	SellerUuid     string    `db:"seller_uuid"`
+	WinnerUuid     string    `db:"winner_uuid"`
+	Closed         bool      `db:"closed"`
+	BidderUuid     string    `db:"bidder_uuid"`
+	BidderUserUuid string    `db:"-"`
+	Amount         int64     `db:"amount"`
+	IP             string    `db:"ip"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// Finding - a signal found against an auction or a bidder, Score is how suspicious it is
+type Finding struct {
+	Subject     Subject `json:"subject"`
+	SubjectUuid string  `json:"subjectUuid"`
+	Signal      Signal  `json:"signal"`
+	Score       int64   `json:"score"`
+	Details     string  `json:"details"`
+}
+
+type FlagStatus string
+
+const (
+	// Open - waiting for a review
+	Open FlagStatus = "open"
+	// Confirmed - a reviewer found the activity fraudulent
+	Confirmed FlagStatus = "confirmed"
+	// Dismissed - a reviewer found the activity legitimate, the same finding does not open the flag again
+	Dismissed FlagStatus = "dismissed"
+)
+
+func (s FlagStatus) Valid() bool {
+	return s == Open || s == Confirmed || s == Dismissed
+}
+
+// Flag - a finding stored for review, one per subject and signal
+type Flag struct {
+	ID           int64      `json:"-" db:"id"`
+	Uuid         string     `json:"uuid" db:"uuid"`
+	Subject      Subject    `json:"subject" db:"subject"`
+	SubjectUuid  string     `json:"subjectUuid" db:"subject_uuid"`
+	Signal       Signal     `json:"signal" db:"signal"`
+	Score        int64      `json:"score" db:"score"`
+	Details      string     `json:"details" db:"details"`
+	Status       FlagStatus `json:"status" db:"status"`
+	ReviewerUuid string     `json:"reviewerUuid,omitempty" db:"reviewer_uuid"`
+	CreatedAt    time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updatedAt" db:"updated_at"`
+	ReviewedAt   *time.Time `json:"reviewedAt,omitempty" db:"reviewed_at"`
+}
+
+// FlagListInput - the flags a reviewer, the actor, is looking at, every filter is optional. the actor is the user
+// of the request
+type FlagListInput struct {
+	ActorUuid   string
+	Subject     Subject
+	SubjectUuid string
+	Status      FlagStatus
+	Page        PageRequest
+}
+
+type FlagList struct {
+	Flags []Flag `json:"flags"`
+	Total int64  `json:"total"`
+}
+
+// ReviewInput - a reviewer, the actor, confirming or dismissing a flag. the actor is the user of the request
+type ReviewInput struct {
+	FlagUuid  string     `json:"-"`
+	ActorUuid string     `json:"-"`
+	Status    FlagStatus `json:"status"`
+}
+
+type PageRequest struct {
+	Offset int64
+	Limit  int64
+}
+
+func (p *PageRequest) GetLimit() int64 {
+	if p.Limit == 0 {
+		return 50
+	}
+
+	return p.Limit
+}
@@ -1,5 +1,7 @@
 package model
 
+import "github.com/ireuven89/hello-world/backend/money"
+
 type Item struct {
 	ID          int64       `json:"-" db:"id"`
 	Uuid        string      `json:"uuid" db:"uuid"`
@@ -8,6 +10,7 @@ type Item struct {
 	Category    string      `json:"category" db:"category"`
 	Name        string      `json:"name" db:"name"`
 	Description string      `json:"description" db:"description"`
+	Price       money.Money `json:"price" db:"price"`
 }
 
 type Category int
@@ -21,6 +24,8 @@ type ItemInput struct {
 	UserUuid string `json:"userUuid" db:"user_uuid"`
 	Category string `json:"category" db:"category"`
 	Name     string `json:"name" db:"name"`
+	// Price - the asking price of the item, stored in the price_amount and price_currency columns
+	Price money.Money `json:"price" db:"price"`
 }
 
 type ListInput struct {
@@ -10,6 +10,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ireuven89/hello-world/backend/item/model"
+	"github.com/ireuven89/hello-world/backend/money"
 )
 
 type ItemRepository struct {
@@ -46,7 +47,7 @@ func (r *ItemRepository) ListItems(input model.ListInput) ([]model.Item, error)
 	}
 
 	q := r.db.
-		Select("id", "uuid", "name", "link", "userUuid", "category").
+		Select(append([]string{"id", "uuid", "name", "link", "userUuid", "category"}, money.Columns("price")...)...).
 		From("items")
 
 	if input.Name != "" {
@@ -89,7 +90,7 @@ func (r *ItemRepository) GetItem(uuid string) (model.Item, error) {
 	}
 
 	q := r.db.
-		Select("id", "uuid", "mame", "link", "userUuid", "category").
+		Select(append([]string{"id", "uuid", "mame", "link", "userUuid", "category"}, money.Columns("price")...)...).
 		From("items").
 		Where(sqlz.Eq("uuid", uuid))
 
@@ -115,14 +116,19 @@ func (r *ItemRepository) Upsert(item model.ItemInput) (string, error) {
 
 	if create {
 		id := uuid.New().String()
+		values := map[string]interface{}{
+			"id":        id,
+			"name":      item.Name,
+			"user_uuid": item.UserUuid,
+			"category":  item.Category,
+		}
+		for column, value := range item.Price.Values("price") {
+			values[column] = value
+		}
+
 		q := r.db.
 			InsertInto("items").
-			ValueMap(map[string]interface{}{
-				"id":        id,
-				"name":      item.Name,
-				"user_uuid": item.UserUuid,
-				"category":  item.Category,
-			})
+			ValueMap(values)
 
 		err := q.GetRow(&id)
 
@@ -134,13 +140,19 @@ func (r *ItemRepository) Upsert(item model.ItemInput) (string, error) {
 	} else {
 		var id string
 
+		values := map[string]interface{}{
+			"user_uuid": item.UserUuid,
+			"name":      item.Name,
+			"category":  item.Category,
+		}
+		if item.Price.Currency != "" {
+			for column, value := range item.Price.Values("price") {
+				values[column] = value
+			}
+		}
+
 		q := r.db.
-			Update("items").SetMap(
-			map[string]interface{}{
-				"user_uuid": item.UserUuid,
-				"name":      item.Name,
-				"category":  item.Category,
-			}).
+			Update("items").SetMap(values).
 			Where(sqlz.Eq("id", item.Uuid))
 
 		if err := q.GetRow(&id); err != nil {
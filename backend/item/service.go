@@ -1,9 +1,12 @@
 package item
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 
 	"github.com/ireuven89/hello-world/backend/item/model"
+	"github.com/ireuven89/hello-world/backend/money"
 )
 
 type Service interface {
@@ -54,6 +57,10 @@ func (s *ServiceItem) GetItem(uuid string) (model.Item, error) {
 }
 
 func (s *ServiceItem) UpdateItem(item model.ItemInput) error {
+	if item.Price.Currency != "" && !item.Price.Currency.Valid() {
+		return fmt.Errorf("%w: %q", money.ErrUnknownCurrency, item.Price.Currency)
+	}
+
 	_, err := s.repo.Upsert(item)
 
 	if err != nil {
@@ -64,6 +71,14 @@ func (s *ServiceItem) UpdateItem(item model.ItemInput) error {
 	return err
 }
 func (s *ServiceItem) CreateItem(item model.ItemInput) (string, error) {
+	if item.Price.Currency == "" {
+		item.Price.Currency = money.Default
+	}
+
+	if !item.Price.Currency.Valid() {
+		return "", fmt.Errorf("%w: %q", money.ErrUnknownCurrency, item.Price.Currency)
+	}
+
 	id, err := s.repo.Upsert(item)
 
 	if err != nil {
@@ -0,0 +1,44 @@
+package messaging
+
+import (
+	"fmt"
+
+	"github.com/ireuven89/hello-world/backend/utils"
+)
+
+// Config - the messaging service config
+type Config struct {
+	utils.Config
+	Moderation ModerationConfig `json:"moderation"`
+}
+
+// ModerationConfig - the words no message may contain, matched as whole words regardless of case. Action is
+// what happens to a message containing one: "reject" refuses it and "mask" stores it with the words starred out
+type ModerationConfig struct {
+	Action      string   `json:"action"`
+	BannedWords []string `json:"bannedWords"`
+}
+
+const (
+	ActionReject = "reject"
+	ActionMask   = "mask"
+)
+
+// LoadConfig - loads the messaging service config of the given environment
+func LoadConfig(env string) (Config, error) {
+	var config Config
+
+	if err := utils.LoadConfigInto("messaging", env, &config); err != nil {
+		return Config{}, err
+	}
+
+	switch config.Moderation.Action {
+	case "":
+		config.Moderation.Action = ActionReject
+	case ActionReject, ActionMask:
+	default:
+		return Config{}, fmt.Errorf("unknown moderation action %q", config.Moderation.Action)
+	}
+
+	return config, nil
+}
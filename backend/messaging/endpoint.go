@@ -0,0 +1,109 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"github.com/ireuven89/hello-world/backend/messaging/model"
+)
+
+type OpenThreadRequest struct {
+	thread model.ThreadInput
+}
+
+func MakeEndpointOpenThread(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(OpenThreadRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointOpenThread failed cast request")
+		}
+
+		result, err := s.OpenThread(req.thread)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointOpenThread: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type PostRequest struct {
+	message model.MessageInput
+}
+
+func MakeEndpointPost(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(PostRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointPost failed cast request")
+		}
+
+		result, err := s.Post(req.message)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointPost: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ListThreadsRequest struct {
+	input model.ThreadListInput
+}
+
+func MakeEndpointListThreads(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListThreadsRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListThreads failed cast request")
+		}
+
+		result, err := s.ListThreads(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListThreads: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ListMessagesRequest struct {
+	input model.MessageListInput
+}
+
+func MakeEndpointListMessages(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListMessagesRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListMessages failed cast request")
+		}
+
+		result, err := s.ListMessages(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListMessages: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type MarkReadRequest struct {
+	read model.ReadInput
+}
+
+func MakeEndpointMarkRead(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(MarkReadRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointMarkRead failed cast request")
+		}
+
+		if err = s.MarkRead(req.read); err != nil {
+			return nil, fmt.Errorf("MakeEndpointMarkRead: %w", err)
+		}
+
+		return nil, nil
+	}
+}
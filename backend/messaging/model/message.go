@@ -0,0 +1,157 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput = errors.New("invalid input")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRejected     = errors.New("message rejected by moderation")
+)
+
+// MaxBodyLength - the longest message body, in characters
+const MaxBodyLength = 2000
+
+// Scope - what a thread is about, an auction or an item
+type Scope string
+
+const (
+	ScopeAuction Scope = "auction"
+	ScopeItem    Scope = "item"
+)
+
+func (s Scope) Valid() bool {
+	return s == ScopeAuction || s == ScopeItem
+}
+
+// Visibility - a public thread is a question whose answers every bidder can read, a private thread is
+// read only by the asker and the seller
+type Visibility string
+
+const (
+	Public  Visibility = "public"
+	Private Visibility = "private"
+)
+
+func (v Visibility) Valid() bool {
+	return v == Public || v == Private
+}
+
+// Thread - a conversation between a bidder, the asker, and the seller of an auction or an item. only the two
+// of them post to it, the unread counters are the messages each of them did not read yet
+type Thread struct {
+	ID           int64      `json:"-" db:"id"`
+	Uuid         string     `json:"uuid" db:"uuid"`
+	Scope        Scope      `json:"scope" db:"scope"`
+	ScopeUuid    string     `json:"scopeUuid" db:"scope_uuid"`
+	SellerUuid   string     `json:"sellerUuid" db:"seller_uuid"`
+	AskerUuid    string     `json:"askerUuid" db:"asker_uuid"`
+	Visibility   Visibility `json:"visibility" db:"visibility"`
+	AskerUnread  int64      `json:"-" db:"asker_unread"`
+	SellerUnread int64      `json:"-" db:"seller_unread"`
+	Unread       int64      `json:"unread" db:"-"`
+	CreatedAt    time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// Participant - whether the user is the asker or the seller of the thread
+func (t Thread) Participant(userUuid string) bool {
+	return userUuid != "" && (userUuid == t.AskerUuid || userUuid == t.SellerUuid)
+}
+
+// VisibleTo - whether the user may read the thread
+func (t Thread) VisibleTo(userUuid string) bool {
+	return t.Visibility == Public || t.Participant(userUuid)
+}
+
+// UnreadFor - the messages of the thread the user did not read, always 0 for a user who does not take part in it
+func (t Thread) UnreadFor(userUuid string) int64 {
+	switch userUuid {
+	case t.AskerUuid:
+		return t.AskerUnread
+	case t.SellerUuid:
+		return t.SellerUnread
+	}
+
+	return 0
+}
+
+// Message - a message of a thread, ReadAt is the read receipt and is set once the other participant read it.
+// Moderated tells the body was masked by moderation
+type Message struct {
+	ID         int64      `json:"-" db:"id"`
+	Uuid       string     `json:"uuid" db:"uuid"`
+	ThreadUuid string     `json:"threadUuid" db:"thread_uuid"`
+	SenderUuid string     `json:"senderUuid" db:"sender_uuid"`
+	Body       string     `json:"body" db:"body"`
+	Moderated  bool       `json:"moderated" db:"moderated"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	ReadAt     *time.Time `json:"readAt,omitempty" db:"read_at"`
+}
+
+// ThreadInput - a bidder asking the seller of the auction or the item, Visibility defaults to public. the asker is
+// the user of the request
+type ThreadInput struct {
+	Scope      Scope      `json:"scope"`
+	ScopeUuid  string     `json:"scopeUuid"`
+	AskerUuid  string     `json:"-"`
+	Visibility Visibility `json:"visibility"`
+	Body       string     `json:"body"`
+}
+
+// MessageInput - a reply of the asker or the seller on the thread, the sender is the user of the request
+type MessageInput struct {
+	ThreadUuid string `json:"-"`
+	SenderUuid string `json:"-"`
+	Body       string `json:"body"`
+}
+
+// ThreadListInput - the threads of an auction or an item the viewer may read, latest activity first. without a
+// scope these are the threads the viewer takes part in
+type ThreadListInput struct {
+	Scope      Scope
+	ScopeUuid  string
+	ViewerUuid string
+	Page       PageRequest
+}
+
+type ThreadList struct {
+	Threads []Thread `json:"threads"`
+	Total   int64    `json:"total"`
+}
+
+// MessageListInput - a page of the messages of a thread, oldest first
+type MessageListInput struct {
+	ThreadUuid string
+	ViewerUuid string
+	Page       PageRequest
+}
+
+type MessageList struct {
+	Thread   Thread    `json:"thread"`
+	Messages []Message `json:"messages"`
+	Total    int64     `json:"total"`
+}
+
+// ReadInput - the reader, the user of the request, marks the messages the other participant sent on the thread
+// as read
+type ReadInput struct {
+	ThreadUuid string `json:"-"`
+	ReaderUuid string `json:"-"`
+}
+
+type PageRequest struct {
+	Offset int64
+	Limit  int64
+}
+
+func (p *PageRequest) GetLimit() int64 {
+	if p.Limit == 0 {
+		return 50
+	}
+
+	return p.Limit
+}
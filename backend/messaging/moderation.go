@@ -0,0 +1,62 @@
+package messaging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ireuven89/hello-world/backend/messaging/model"
+)
+
+// Moderator - a moderation hook every message goes through before it is stored. it returns the message to
+// store, possibly with its body changed, or an error wrapping model.ErrRejected to refuse it
+type Moderator interface {
+	Moderate(message model.Message) (model.Message, error)
+}
+
+// ModeratorFunc - adapts a function to a Moderator
+type ModeratorFunc func(message model.Message) (model.Message, error)
+
+func (f ModeratorFunc) Moderate(message model.Message) (model.Message, error) {
+	return f(message)
+}
+
+// WordFilter - the banned words moderator, it rejects or masks the messages containing a banned word
+type WordFilter struct {
+	pattern *regexp.Regexp
+	mask    bool
+}
+
+func NewWordFilter(config ModerationConfig) *WordFilter {
+	words := make([]string, 0, len(config.BannedWords))
+	for _, word := range config.BannedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, regexp.QuoteMeta(word))
+		}
+	}
+
+	filter := &WordFilter{mask: config.Action == ActionMask}
+	if len(words) > 0 {
+		filter.pattern = regexp.MustCompile(`(?i)\b(` + strings.Join(words, "|") + `)\b`)
+	}
+
+	return filter
+}
+
+func (f *WordFilter) Moderate(message model.Message) (model.Message, error) {
+	if f.pattern == nil || !f.pattern.MatchString(message.Body) {
+		return message, nil
+	}
+
+	if !f.mask {
+		return model.Message{}, fmt.Errorf("%w: the message contains a banned word", model.ErrRejected)
+	}
+
+	message.Body = f.pattern.ReplaceAllStringFunc(message.Body, func(word string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(word))
+	})
+	message.Moderated = true
+
+	return message, nil
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+	"github.com/ireuven89/hello-world/backend/messaging/model"
+)
+
+var threadColumns = []string{
+	"id",
+	"uuid",
+	"scope",
+	"scope_uuid",
+	"seller_uuid",
+	"asker_uuid",
+	"visibility",
+	"asker_unread",
+	"seller_unread",
+	"created_at",
+	"updated_at",
+}
+
+var messageColumns = []string{
+	"id",
+	"uuid",
+	"thread_uuid",
+	"sender_uuid",
+	"body",
+	"moderated",
+	"created_at",
+	"read_at",
+}
+
+type MessageRepository struct {
+	db     *sqlz.DB
+	logger *zap.Logger
+}
+
+func New(db *sqlz.DB, logger *zap.Logger) *MessageRepository {
+
+	return &MessageRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateThread - inserts the thread with its first message, the seller has it unread
+func (r *MessageRepository) CreateThread(thread model.Thread, message model.Message) error {
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.InsertInto(dbmodel.Threads).
+			ValueMap(map[string]interface{}{
+				"uuid":          thread.Uuid,
+				"scope":         thread.Scope,
+				"scope_uuid":    thread.ScopeUuid,
+				"seller_uuid":   thread.SellerUuid,
+				"asker_uuid":    thread.AskerUuid,
+				"visibility":    thread.Visibility,
+				"asker_unread":  thread.AskerUnread,
+				"seller_unread": thread.SellerUnread,
+				"created_at":    thread.CreatedAt,
+				"updated_at":    thread.UpdatedAt,
+			})
+
+		utils.New().DebugInsert(q, "insert thread")
+
+		if _, err := q.Exec(); err != nil {
+			return err
+		}
+
+		return insertMessage(tx, message)
+	})
+
+	if err != nil {
+		r.logger.Error("MessageRepository.CreateThread failed creating thread", zap.String("thread", thread.Uuid), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Thread - this method queries the thread by its uuid
+func (r *MessageRepository) Thread(uuid string) (model.Thread, error) {
+	var result model.Thread
+
+	q := r.db.
+		Select(threadColumns...).
+		From(dbmodel.Threads).
+		Where(sqlz.Eq("uuid", uuid))
+
+	utils.New().DebugSelect(q, "get thread")
+
+	if err := q.GetRow(&result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Thread{}, model.ErrNotFound
+		}
+		r.logger.Error("MessageRepository.Thread failed getting thread", zap.String("uuid", uuid), zap.Error(err))
+		return model.Thread{}, err
+	}
+
+	return result, nil
+}
+
+// ListThreads - this method queries a page of the threads the viewer may read, latest activity first. within a
+// scope these are the public threads and the private threads of the viewer, without one only the threads the
+// viewer takes part in
+func (r *MessageRepository) ListThreads(input model.ThreadListInput) (model.ThreadList, error) {
+	var result model.ThreadList
+
+	participant := []sqlz.WhereCondition{sqlz.Eq("asker_uuid", input.ViewerUuid), sqlz.Eq("seller_uuid", input.ViewerUuid)}
+	conditions := []sqlz.WhereCondition{sqlz.Or(participant...)}
+
+	if input.Scope != "" {
+		conditions = []sqlz.WhereCondition{
+			sqlz.Eq("scope", input.Scope),
+			sqlz.Eq("scope_uuid", input.ScopeUuid),
+			sqlz.Or(append(participant, sqlz.Eq("visibility", model.Public))...),
+		}
+	}
+
+	q := r.db.
+		Select(threadColumns...).
+		From(dbmodel.Threads).
+		Where(conditions...).
+		OrderBy(sqlz.Desc("updated_at"), sqlz.Desc("id")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list threads")
+
+	if err := q.GetAll(&result.Threads); err != nil {
+		r.logger.Error("MessageRepository.ListThreads failed listing threads", zap.Any("input", input), zap.Error(err))
+		return model.ThreadList{}, err
+	}
+
+	total, err := r.db.
+		Select("*").
+		From(dbmodel.Threads).
+		Where(conditions...).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("MessageRepository.ListThreads failed counting threads", zap.Any("input", input), zap.Error(err))
+		return model.ThreadList{}, err
+	}
+	result.Total = total
+
+	return result, nil
+}
+
+// CreateMessage - inserts the message and counts it as unread for the other participant of the thread
+func (r *MessageRepository) CreateMessage(thread model.Thread, message model.Message) error {
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		if err := insertMessage(tx, message); err != nil {
+			return err
+		}
+
+		recipient := "seller_unread"
+		if message.SenderUuid == thread.SellerUuid {
+			recipient = "asker_unread"
+		}
+
+		q := tx.
+			Update(dbmodel.Threads).
+			Set(recipient, sqlz.Indirect(recipient+" + 1")).
+			Set("updated_at", message.CreatedAt).
+			Where(sqlz.Eq("uuid", thread.Uuid))
+
+		utils.New().DebugUpdate(q, "update thread")
+
+		_, err := q.Exec()
+		return err
+	})
+
+	if err != nil {
+		r.logger.Error("MessageRepository.CreateMessage failed creating message", zap.String("thread", thread.Uuid), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListMessages - this method queries a page of the messages of the thread, oldest first, with the number of
+// messages of the thread
+func (r *MessageRepository) ListMessages(input model.MessageListInput) (model.MessageList, error) {
+	var result model.MessageList
+
+	q := r.db.
+		Select(messageColumns...).
+		From(dbmodel.Messages).
+		Where(sqlz.Eq("thread_uuid", input.ThreadUuid)).
+		OrderBy(sqlz.Asc("id")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list messages")
+
+	if err := q.GetAll(&result.Messages); err != nil {
+		r.logger.Error("MessageRepository.ListMessages failed listing messages", zap.Any("input", input), zap.Error(err))
+		return model.MessageList{}, err
+	}
+
+	total, err := r.db.
+		Select("*").
+		From(dbmodel.Messages).
+		Where(sqlz.Eq("thread_uuid", input.ThreadUuid)).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("MessageRepository.ListMessages failed counting messages", zap.Any("input", input), zap.Error(err))
+		return model.MessageList{}, err
+	}
+	result.Total = total
+
+	return result, nil
+}
+
+// MarkRead - sets the read receipt of the messages the other participant sent on the thread and clears the
+// unread counter of the reader
+func (r *MessageRepository) MarkRead(thread model.Thread, readerUuid string, readAt time.Time) error {
+	counter := "asker_unread"
+	if readerUuid == thread.SellerUuid {
+		counter = "seller_unread"
+	}
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.
+			Update(dbmodel.Messages).
+			Set("read_at", readAt).
+			Where(sqlz.Eq("thread_uuid", thread.Uuid), sqlz.Ne("sender_uuid", readerUuid), sqlz.IsNull("read_at"))
+
+		utils.New().DebugUpdate(q, "mark messages read")
+
+		if _, err := q.Exec(); err != nil {
+			return err
+		}
+
+		update := tx.
+			Update(dbmodel.Threads).
+			Set(counter, 0).
+			Where(sqlz.Eq("uuid", thread.Uuid))
+
+		utils.New().DebugUpdate(update, "clear unread")
+
+		_, err := update.Exec()
+		return err
+	})
+
+	if err != nil {
+		r.logger.Error("MessageRepository.MarkRead failed marking messages", zap.String("thread", thread.Uuid), zap.String("reader", readerUuid), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func insertMessage(tx *sqlz.Tx, message model.Message) error {
+	q := tx.InsertInto(dbmodel.Messages).
+		ValueMap(map[string]interface{}{
+			"uuid":        message.Uuid,
+			"thread_uuid": message.ThreadUuid,
+			"sender_uuid": message.SenderUuid,
+			"body":        message.Body,
+			"moderated":   message.Moderated,
+			"created_at":  message.CreatedAt,
+		})
+
+	utils.New().DebugInsert(q, "insert message")
+
+	_, err := q.Exec()
+	return err
+}
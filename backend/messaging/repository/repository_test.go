@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/ireuven89/hello-world/backend/messaging/model"
+)
+
+func thread() model.Thread {
+	return model.Thread{
+		Uuid:       "thread-uuid",
+		Scope:      model.ScopeAuction,
+		ScopeUuid:  "auction-uuid",
+		SellerUuid: "seller-uuid",
+		AskerUuid:  "asker-uuid",
+		Visibility: model.Public,
+	}
+}
+
+func TestMessageRepository_ThreadNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT (.+) FROM threads WHERE uuid = \\?").
+		WithArgs("thread-uuid").
+		WillReturnRows(sqlmock.NewRows(threadColumns))
+
+	_, err = repo.Thread("thread-uuid")
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestMessageRepository_ListThreads(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectQuery("SELECT (.+) FROM threads WHERE scope = \\? AND scope_uuid = \\? AND \\(asker_uuid = \\? OR seller_uuid = \\? OR visibility = \\?\\) ORDER BY updated_at DESC, id DESC LIMIT 50").
+		WithArgs(model.ScopeAuction, "auction-uuid", "bidder-uuid", "bidder-uuid", model.Public).
+		WillReturnRows(sqlmock.NewRows(threadColumns).
+			AddRow(1, "thread-uuid", "auction", "auction-uuid", "seller-uuid", "asker-uuid", "public", 0, 1, now, now))
+	mockSql.ExpectQuery("SELECT COUNT\\(\\*\\) FROM threads").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	result, err := repo.ListThreads(model.ThreadListInput{Scope: model.ScopeAuction, ScopeUuid: "auction-uuid", ViewerUuid: "bidder-uuid"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+	assert.Equal(t, "thread-uuid", result.Threads[0].Uuid)
+	assert.Equal(t, int64(1), result.Threads[0].SellerUnread)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestMessageRepository_CreateMessage(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("INSERT INTO messages").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("UPDATE threads SET asker_unread = asker_unread \\+ 1, updated_at = \\? WHERE uuid = \\?").
+		WithArgs(now, "thread-uuid").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	err = repo.CreateMessage(thread(), model.Message{Uuid: "message-uuid", ThreadUuid: "thread-uuid", SenderUuid: "seller-uuid", Body: "it is new", CreatedAt: now})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestMessageRepository_MarkRead(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("UPDATE messages SET read_at = \\? WHERE thread_uuid = \\? AND sender_uuid <> \\? AND read_at IS NULL").
+		WithArgs(now, "thread-uuid", "seller-uuid").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockSql.ExpectExec("UPDATE threads SET seller_unread = \\? WHERE uuid = \\?").
+		WithArgs(0, "thread-uuid").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectCommit()
+
+	err = repo.MarkRead(thread(), "seller-uuid", now)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,68 @@
+package messaging
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	auctionmodel "github.com/ireuven89/hello-world/backend/auction/model"
+	itemmodel "github.com/ireuven89/hello-world/backend/item/model"
+	"github.com/ireuven89/hello-world/backend/messaging/model"
+)
+
+// Sellers - finds the seller of the auction or the item a thread is about
+type Sellers interface {
+	Seller(scope model.Scope, scopeUuid string) (string, error)
+}
+
+type AuctionGetter interface {
+	GetAuction(uuid string) (auctionmodel.Auction, error)
+}
+
+type ItemGetter interface {
+	GetItem(uuid string) (itemmodel.Item, error)
+}
+
+type ServiceSellers struct {
+	auctions AuctionGetter
+	items    ItemGetter
+}
+
+// NewSellers - the sellers are the owners of the auctions and the items, looked up in their services
+func NewSellers(auctions AuctionGetter, items ItemGetter) *ServiceSellers {
+
+	return &ServiceSellers{auctions: auctions, items: items}
+}
+
+func (s *ServiceSellers) Seller(scope model.Scope, scopeUuid string) (string, error) {
+	var seller string
+
+	switch scope {
+	case model.ScopeAuction:
+		auction, err := s.auctions.GetAuction(scopeUuid)
+		if errors.Is(err, auctionmodel.ErrNotFound) {
+			return "", fmt.Errorf("%w: auction %s", model.ErrNotFound, scopeUuid)
+		}
+		if err != nil {
+			return "", err
+		}
+		seller = auction.UserUuid
+	case model.ScopeItem:
+		item, err := s.items.GetItem(scopeUuid)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w: item %s", model.ErrNotFound, scopeUuid)
+		}
+		if err != nil {
+			return "", err
+		}
+		seller = item.UserUuid
+	default:
+		return "", fmt.Errorf("%w: unknown scope %q", model.ErrInvalidInput, scope)
+	}
+
+	if seller == "" {
+		return "", fmt.Errorf("%w: %s %s", model.ErrNotFound, scope, scopeUuid)
+	}
+
+	return seller, nil
+}
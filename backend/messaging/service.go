@@ -0,0 +1,231 @@
+package messaging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/messaging/model"
+)
+
+type Service interface {
+	OpenThread(input model.ThreadInput) (model.MessageList, error)
+	Post(input model.MessageInput) (model.Message, error)
+	ListThreads(input model.ThreadListInput) (model.ThreadList, error)
+	ListMessages(input model.MessageListInput) (model.MessageList, error)
+	MarkRead(input model.ReadInput) error
+}
+
+type Repository interface {
+	CreateThread(thread model.Thread, message model.Message) error
+	Thread(uuid string) (model.Thread, error)
+	ListThreads(input model.ThreadListInput) (model.ThreadList, error)
+	CreateMessage(thread model.Thread, message model.Message) error
+	ListMessages(input model.MessageListInput) (model.MessageList, error)
+	MarkRead(thread model.Thread, readerUuid string, readAt time.Time) error
+}
+
+type ServiceMessage struct {
+	repo       Repository
+	sellers    Sellers
+	moderators []Moderator
+	logger     *zap.Logger
+}
+
+// New - every message goes through the moderators in order before it is stored
+func New(repo Repository, sellers Sellers, moderators []Moderator, logger *zap.Logger) Service {
+
+	return &ServiceMessage{repo: repo, sellers: sellers, moderators: moderators, logger: logger}
+}
+
+// OpenThread - a bidder asks the seller of an auction or an item, the thread is returned with the question
+func (s *ServiceMessage) OpenThread(input model.ThreadInput) (model.MessageList, error) {
+	if input.Visibility == "" {
+		input.Visibility = model.Public
+	}
+
+	if err := validateThread(input); err != nil {
+		return model.MessageList{}, err
+	}
+
+	seller, err := s.sellers.Seller(input.Scope, input.ScopeUuid)
+	if err != nil {
+		s.logger.Error("ServiceMessage.OpenThread failed finding seller", zap.Any("input", input), zap.Error(err))
+		return model.MessageList{}, err
+	}
+
+	if seller == input.AskerUuid {
+		return model.MessageList{}, fmt.Errorf("%w: sellers do not ask about their own listings", model.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	thread := model.Thread{
+		Uuid:         uuid.New().String(),
+		Scope:        input.Scope,
+		ScopeUuid:    input.ScopeUuid,
+		SellerUuid:   seller,
+		AskerUuid:    input.AskerUuid,
+		Visibility:   input.Visibility,
+		SellerUnread: 1,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	message, err := s.moderate(thread, input.AskerUuid, input.Body, now)
+	if err != nil {
+		return model.MessageList{}, err
+	}
+
+	if err = s.repo.CreateThread(thread, message); err != nil {
+		s.logger.Error("ServiceMessage.OpenThread failed creating thread", zap.Any("input", input), zap.Error(err))
+		return model.MessageList{}, err
+	}
+
+	thread.Unread = thread.UnreadFor(input.AskerUuid)
+
+	return model.MessageList{Thread: thread, Messages: []model.Message{message}, Total: 1}, nil
+}
+
+// Post - the asker or the seller replies on the thread, on a public thread every bidder reads the answer
+func (s *ServiceMessage) Post(input model.MessageInput) (model.Message, error) {
+	if input.ThreadUuid == "" || input.SenderUuid == "" {
+		return model.Message{}, fmt.Errorf("%w: thread and senderUuid are required", model.ErrInvalidInput)
+	}
+
+	thread, err := s.repo.Thread(input.ThreadUuid)
+	if err != nil {
+		s.logger.Error("ServiceMessage.Post failed getting thread", zap.String("thread", input.ThreadUuid), zap.Error(err))
+		return model.Message{}, err
+	}
+
+	if !thread.Participant(input.SenderUuid) {
+		return model.Message{}, fmt.Errorf("%w: only the asker and the seller post on a thread", model.ErrForbidden)
+	}
+
+	message, err := s.moderate(thread, input.SenderUuid, input.Body, time.Now())
+	if err != nil {
+		return model.Message{}, err
+	}
+
+	if err = s.repo.CreateMessage(thread, message); err != nil {
+		s.logger.Error("ServiceMessage.Post failed creating message", zap.String("thread", input.ThreadUuid), zap.Error(err))
+		return model.Message{}, err
+	}
+
+	return message, nil
+}
+
+// ListThreads - the threads of an auction or an item the viewer may read, or the threads the viewer takes part
+// in, each with the number of messages the viewer did not read
+func (s *ServiceMessage) ListThreads(input model.ThreadListInput) (model.ThreadList, error) {
+	if input.ViewerUuid == "" {
+		return model.ThreadList{}, fmt.Errorf("%w: userUuid is required", model.ErrInvalidInput)
+	}
+
+	if input.Scope != "" && (!input.Scope.Valid() || input.ScopeUuid == "") {
+		return model.ThreadList{}, fmt.Errorf("%w: a scope of auction or item and a scopeUuid are required", model.ErrInvalidInput)
+	}
+
+	result, err := s.repo.ListThreads(input)
+	if err != nil {
+		s.logger.Error("ServiceMessage.ListThreads failed listing threads", zap.Any("input", input), zap.Error(err))
+		return model.ThreadList{}, err
+	}
+
+	for i := range result.Threads {
+		result.Threads[i].Unread = result.Threads[i].UnreadFor(input.ViewerUuid)
+	}
+
+	return result, nil
+}
+
+// ListMessages - the messages of a thread the viewer may read, a private thread of other users is not found
+func (s *ServiceMessage) ListMessages(input model.MessageListInput) (model.MessageList, error) {
+	thread, err := s.repo.Thread(input.ThreadUuid)
+	if err != nil {
+		s.logger.Error("ServiceMessage.ListMessages failed getting thread", zap.String("thread", input.ThreadUuid), zap.Error(err))
+		return model.MessageList{}, err
+	}
+
+	if !thread.VisibleTo(input.ViewerUuid) {
+		return model.MessageList{}, model.ErrNotFound
+	}
+
+	result, err := s.repo.ListMessages(input)
+	if err != nil {
+		s.logger.Error("ServiceMessage.ListMessages failed listing messages", zap.Any("input", input), zap.Error(err))
+		return model.MessageList{}, err
+	}
+
+	thread.Unread = thread.UnreadFor(input.ViewerUuid)
+	result.Thread = thread
+
+	return result, nil
+}
+
+// MarkRead - the reader read the thread, the messages the other participant sent get their read receipt
+func (s *ServiceMessage) MarkRead(input model.ReadInput) error {
+	thread, err := s.repo.Thread(input.ThreadUuid)
+	if err != nil {
+		s.logger.Error("ServiceMessage.MarkRead failed getting thread", zap.String("thread", input.ThreadUuid), zap.Error(err))
+		return err
+	}
+
+	if !thread.Participant(input.ReaderUuid) {
+		return fmt.Errorf("%w: only the asker and the seller read a thread", model.ErrForbidden)
+	}
+
+	if err = s.repo.MarkRead(thread, input.ReaderUuid, time.Now()); err != nil {
+		s.logger.Error("ServiceMessage.MarkRead failed marking thread", zap.Any("input", input), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// moderate - builds the message of the sender and passes it through the moderators
+func (s *ServiceMessage) moderate(thread model.Thread, senderUuid, body string, now time.Time) (model.Message, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > model.MaxBodyLength {
+		return model.Message{}, fmt.Errorf("%w: body must have between 1 and %d characters", model.ErrInvalidInput, model.MaxBodyLength)
+	}
+
+	message := model.Message{
+		Uuid:       uuid.New().String(),
+		ThreadUuid: thread.Uuid,
+		SenderUuid: senderUuid,
+		Body:       body,
+		CreatedAt:  now,
+	}
+
+	for _, moderator := range s.moderators {
+		moderated, err := moderator.Moderate(message)
+		if err != nil {
+			s.logger.Info("ServiceMessage.moderate message refused", zap.String("thread", thread.Uuid), zap.String("sender", senderUuid), zap.Error(err))
+			return model.Message{}, err
+		}
+		message = moderated
+	}
+
+	return message, nil
+}
+
+func validateThread(input model.ThreadInput) error {
+	if !input.Scope.Valid() || input.ScopeUuid == "" {
+		return fmt.Errorf("%w: a scope of auction or item and a scopeUuid are required", model.ErrInvalidInput)
+	}
+
+	if input.AskerUuid == "" {
+		return fmt.Errorf("%w: askerUuid is required", model.ErrInvalidInput)
+	}
+
+	if !input.Visibility.Valid() {
+		return fmt.Errorf("%w: visibility must be public or private", model.ErrInvalidInput)
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/messaging/model"
+)
+
+type MockRepository struct {
+	mock mock.Mock
+}
+
+func (m *MockRepository) CreateThread(thread model.Thread, message model.Message) error {
+	args := m.mock.Called(thread, message)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) Thread(uuid string) (model.Thread, error) {
+	args := m.mock.Called(uuid)
+
+	return args.Get(0).(model.Thread), args.Error(1)
+}
+
+func (m *MockRepository) ListThreads(input model.ThreadListInput) (model.ThreadList, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(model.ThreadList), args.Error(1)
+}
+
+func (m *MockRepository) CreateMessage(thread model.Thread, message model.Message) error {
+	args := m.mock.Called(thread, message)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListMessages(input model.MessageListInput) (model.MessageList, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(model.MessageList), args.Error(1)
+}
+
+func (m *MockRepository) MarkRead(thread model.Thread, readerUuid string, readAt time.Time) error {
+	args := m.mock.Called(thread, readerUuid)
+
+	return args.Error(0)
+}
+
+type MockSellers struct {
+	mock mock.Mock
+}
+
+func (m *MockSellers) Seller(scope model.Scope, scopeUuid string) (string, error) {
+	args := m.mock.Called(scope, scopeUuid)
+
+	return args.String(0), args.Error(1)
+}
+
+func privateThread() model.Thread {
+	return model.Thread{
+		Uuid:         "thread-uuid",
+		Scope:        model.ScopeAuction,
+		ScopeUuid:    "auction-uuid",
+		SellerUuid:   "seller-uuid",
+		AskerUuid:    "asker-uuid",
+		Visibility:   model.Private,
+		AskerUnread:  2,
+		SellerUnread: 1,
+	}
+}
+
+func newService(repo *MockRepository, moderation ModerationConfig) Service {
+	sellers := &MockSellers{}
+	sellers.mock.On("Seller", model.ScopeAuction, "auction-uuid").Return("seller-uuid", nil)
+
+	return New(repo, sellers, []Moderator{NewWordFilter(moderation)}, zap.NewNop())
+}
+
+func TestServiceMessage_OpenThread(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("CreateThread", mock.Anything, mock.Anything).Return(nil)
+	service := newService(repo, ModerationConfig{})
+
+	result, err := service.OpenThread(model.ThreadInput{Scope: model.ScopeAuction, ScopeUuid: "auction-uuid", AskerUuid: "asker-uuid", Body: " is it new? "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "seller-uuid", result.Thread.SellerUuid)
+	assert.Equal(t, model.Public, result.Thread.Visibility)
+	assert.Equal(t, int64(1), result.Thread.SellerUnread)
+	assert.Equal(t, "is it new?", result.Messages[0].Body)
+	assert.Equal(t, result.Thread.Uuid, result.Messages[0].ThreadUuid)
+}
+
+func TestServiceMessage_OpenThreadRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   model.ThreadInput
+		wantErr error
+	}{
+		{"unknown scope", model.ThreadInput{Scope: "user", ScopeUuid: "auction-uuid", AskerUuid: "asker-uuid", Body: "hi"}, model.ErrInvalidInput},
+		{"unknown visibility", model.ThreadInput{Scope: model.ScopeAuction, ScopeUuid: "auction-uuid", AskerUuid: "asker-uuid", Visibility: "secret", Body: "hi"}, model.ErrInvalidInput},
+		{"seller asks", model.ThreadInput{Scope: model.ScopeAuction, ScopeUuid: "auction-uuid", AskerUuid: "seller-uuid", Body: "hi"}, model.ErrInvalidInput},
+		{"empty body", model.ThreadInput{Scope: model.ScopeAuction, ScopeUuid: "auction-uuid", AskerUuid: "asker-uuid", Body: "  "}, model.ErrInvalidInput},
+		{"banned word", model.ThreadInput{Scope: model.ScopeAuction, ScopeUuid: "auction-uuid", AskerUuid: "asker-uuid", Body: "pay by Wire Transfer?"}, model.ErrRejected},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			service := newService(repo, ModerationConfig{Action: ActionReject, BannedWords: []string{"wire transfer"}})
+
+			_, err := service.OpenThread(test.input)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			repo.mock.AssertNotCalled(t, "CreateThread", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestServiceMessage_Post(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Thread", "thread-uuid").Return(privateThread(), nil)
+	repo.mock.On("CreateMessage", privateThread(), mock.Anything).Return(nil)
+	service := newService(repo, ModerationConfig{Action: ActionMask, BannedWords: []string{"scam"}})
+
+	result, err := service.Post(model.MessageInput{ThreadUuid: "thread-uuid", SenderUuid: "seller-uuid", Body: "not a SCAM, it is new"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "not a ****, it is new", result.Body)
+	assert.True(t, result.Moderated)
+	assert.Equal(t, "seller-uuid", result.SenderUuid)
+}
+
+func TestServiceMessage_PostNotParticipant(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Thread", "thread-uuid").Return(privateThread(), nil)
+	service := newService(repo, ModerationConfig{})
+
+	_, err := service.Post(model.MessageInput{ThreadUuid: "thread-uuid", SenderUuid: "bidder-uuid", Body: "me too"})
+
+	assert.ErrorIs(t, err, model.ErrForbidden)
+	repo.mock.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestServiceMessage_ListMessages(t *testing.T) {
+	public := privateThread()
+	public.Visibility = model.Public
+
+	tests := []struct {
+		name    string
+		thread  model.Thread
+		viewer  string
+		wantErr error
+	}{
+		{"bidder reads a public thread", public, "bidder-uuid", nil},
+		{"asker reads a private thread", privateThread(), "asker-uuid", nil},
+		{"bidder reads a private thread", privateThread(), "bidder-uuid", model.ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := model.MessageListInput{ThreadUuid: "thread-uuid", ViewerUuid: test.viewer}
+			repo := &MockRepository{}
+			repo.mock.On("Thread", "thread-uuid").Return(test.thread, nil)
+			repo.mock.On("ListMessages", input).Return(model.MessageList{Total: 3}, nil)
+			service := newService(repo, ModerationConfig{})
+
+			result, err := service.ListMessages(input)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			if test.wantErr == nil {
+				assert.Equal(t, int64(3), result.Total)
+				assert.Equal(t, test.thread.UnreadFor(test.viewer), result.Thread.Unread)
+			}
+		})
+	}
+}
+
+func TestServiceMessage_ListThreads(t *testing.T) {
+	input := model.ThreadListInput{ViewerUuid: "seller-uuid"}
+	repo := &MockRepository{}
+	repo.mock.On("ListThreads", input).Return(model.ThreadList{Threads: []model.Thread{privateThread()}, Total: 1}, nil)
+	service := newService(repo, ModerationConfig{})
+
+	result, err := service.ListThreads(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Threads[0].Unread)
+}
+
+func TestServiceMessage_MarkRead(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Thread", "thread-uuid").Return(privateThread(), nil)
+	repo.mock.On("MarkRead", privateThread(), "asker-uuid").Return(nil)
+	service := newService(repo, ModerationConfig{})
+
+	assert.NoError(t, service.MarkRead(model.ReadInput{ThreadUuid: "thread-uuid", ReaderUuid: "asker-uuid"}))
+	assert.ErrorIs(t, service.MarkRead(model.ReadInput{ThreadUuid: "thread-uuid", ReaderUuid: "bidder-uuid"}), model.ErrForbidden)
+	repo.mock.AssertNumberOfCalls(t, "MarkRead", 1)
+}
+
+func TestWordFilter_Moderate(t *testing.T) {
+	filter := NewWordFilter(ModerationConfig{Action: ActionReject, BannedWords: []string{"scam"}})
+
+	_, err := filter.Moderate(model.Message{Body: "is this a scam?"})
+	assert.ErrorIs(t, err, model.ErrRejected)
+
+	result, err := filter.Moderate(model.Message{Body: "scampi for dinner"})
+	assert.NoError(t, err)
+	assert.Equal(t, "scampi for dinner", result.Body)
+	assert.False(t, result.Moderated)
+}
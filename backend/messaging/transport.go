@@ -0,0 +1,233 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/julienschmidt/httprouter"
+	"github.com/labstack/gommon/log"
+
+	"github.com/ireuven89/hello-world/backend/authenticating"
+	"github.com/ireuven89/hello-world/backend/messaging/model"
+)
+
+func NewTransport(s Service, router *httprouter.Router, verifier authenticating.Verifier) Transport {
+
+	transport := Transport{
+		router: router,
+		s:      s,
+	}
+	RegisterRoutes(router, s, verifier) // Register routes during initialization
+	return transport
+}
+
+type Transport struct {
+	router *httprouter.Router
+	s      Service
+}
+
+func (t *Transport) ListenAndServe(port string) {
+	log.Printf("Starting server on port %s...", port)
+	err := http.ListenAndServe(":"+port, t.router)
+	if err != nil {
+		log.Fatalf("Server failed to start: %v", err)
+	}
+}
+
+// RegisterRoutes - verifier checks the token of the requests, every request acts as the user of its token
+func RegisterRoutes(router *httprouter.Router, s Service, verifier authenticating.Verifier) {
+	options := []kithttp.ServerOption{
+		kithttp.ServerBefore(authenticating.VerifyActor(verifier)),
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	openThreadHandler := kithttp.NewServer(
+		MakeEndpointOpenThread(s),
+		decodeOpenThreadRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listThreadsHandler := kithttp.NewServer(
+		MakeEndpointListThreads(s),
+		decodeListThreadsRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	inboxHandler := kithttp.NewServer(
+		MakeEndpointListThreads(s),
+		decodeInboxRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listMessagesHandler := kithttp.NewServer(
+		MakeEndpointListMessages(s),
+		decodeListMessagesRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	postHandler := kithttp.NewServer(
+		MakeEndpointPost(s),
+		decodePostRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	markReadHandler := kithttp.NewServer(
+		MakeEndpointMarkRead(s),
+		decodeMarkReadRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	router.Handler(http.MethodPost, "/threads", openThreadHandler)
+	router.Handler(http.MethodGet, "/threads", listThreadsHandler)
+	router.Handler(http.MethodGet, "/threads/:uuid/messages", listMessagesHandler)
+	router.Handler(http.MethodPost, "/threads/:uuid/messages", postHandler)
+	router.Handler(http.MethodPost, "/threads/:uuid/read", markReadHandler)
+	router.Handler(http.MethodGet, "/users/:userUuid/threads", inboxHandler)
+}
+
+func encodeError(ctx context.Context, err error, writer http.ResponseWriter) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrInvalidInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, authenticating.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, model.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, model.ErrRejected):
+		status = http.StatusUnprocessableEntity
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	json.NewEncoder(writer).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func decodeOpenThreadRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.ThreadInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.AskerUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	return OpenThreadRequest{
+		thread: input,
+	}, nil
+}
+
+func decodeListThreadsRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.ThreadListInput
+	queryParams := r.URL.Query()
+
+	if input.ViewerUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.Scope = model.Scope(queryParams.Get("scope"))
+	input.ScopeUuid = queryParams.Get("scopeUuid")
+	input.Page = decodePage(r)
+
+	return ListThreadsRequest{
+		input: input,
+	}, nil
+}
+
+// decodeInboxRequest - a user reads the user's own inbox only
+func decodeInboxRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	viewer, err := authenticating.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewer != httprouter.ParamsFromContext(ctx).ByName("userUuid") {
+		return nil, model.ErrForbidden
+	}
+
+	return ListThreadsRequest{
+		input: model.ThreadListInput{
+			ViewerUuid: viewer,
+			Page:       decodePage(r),
+		},
+	}, nil
+}
+
+func decodeListMessagesRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	viewer, err := authenticating.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ListMessagesRequest{
+		input: model.MessageListInput{
+			ThreadUuid: httprouter.ParamsFromContext(ctx).ByName("uuid"),
+			ViewerUuid: viewer,
+			Page:       decodePage(r),
+		},
+	}, nil
+}
+
+func decodePostRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.MessageInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.SenderUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.ThreadUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return PostRequest{
+		message: input,
+	}, nil
+}
+
+func decodeMarkReadRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.ReadInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ReaderUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.ThreadUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return MarkReadRequest{
+		read: input,
+	}, nil
+}
+
+func decodePage(r *http.Request) model.PageRequest {
+	var page model.PageRequest
+	queryParams := r.URL.Query()
+
+	page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	return page
+}
@@ -0,0 +1,71 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Currency - an ISO 4217 currency code
+type Currency string
+
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	GBP Currency = "GBP"
+	ILS Currency = "ILS"
+	JPY Currency = "JPY"
+	CHF Currency = "CHF"
+	CAD Currency = "CAD"
+	AUD Currency = "AUD"
+	KWD Currency = "KWD"
+)
+
+// Default - the currency of amounts stored before currencies were introduced
+const Default = USD
+
+type currencyInfo struct {
+	digits int
+	symbol string
+}
+
+var currencies = map[Currency]currencyInfo{
+	USD: {digits: 2, symbol: "$"},
+	EUR: {digits: 2, symbol: "€"},
+	GBP: {digits: 2, symbol: "£"},
+	ILS: {digits: 2, symbol: "₪"},
+	JPY: {digits: 0, symbol: "¥"},
+	CHF: {digits: 2, symbol: "CHF"},
+	CAD: {digits: 2, symbol: "CA$"},
+	AUD: {digits: 2, symbol: "A$"},
+	KWD: {digits: 3, symbol: "KD"},
+}
+
+// ParseCurrency - returns the known currency of the code, codes are case insensitive
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+
+	if !currency.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+
+	return currency, nil
+}
+
+func (c Currency) Valid() bool {
+	_, ok := currencies[c]
+
+	return ok
+}
+
+// Digits - the number of minor unit digits of the currency, 2 for cents
+func (c Currency) Digits() int {
+	return currencies[c].digits
+}
+
+func (c Currency) Symbol() string {
+	if info, ok := currencies[c]; ok {
+		return info.symbol
+	}
+
+	return string(c)
+}
@@ -0,0 +1,98 @@
+package money
+
+import (
+	"strings"
+)
+
+// locale - how a locale writes amounts
+type locale struct {
+	group       string
+	decimal     string
+	symbolFirst bool
+	spaced      bool
+}
+
+var locales = map[string]locale{
+	"en-US": {group: ",", decimal: ".", symbolFirst: true},
+	"en-GB": {group: ",", decimal: ".", symbolFirst: true},
+	"he-IL": {group: ",", decimal: ".", symbolFirst: false, spaced: true},
+	"de-DE": {group: ".", decimal: ",", symbolFirst: false, spaced: true},
+	"fr-FR": {group: " ", decimal: ",", symbolFirst: false, spaced: true},
+	"de-CH": {group: "’", decimal: ".", symbolFirst: true, spaced: true},
+	"ja-JP": {group: ",", decimal: ".", symbolFirst: true},
+}
+
+// DefaultLocale - the locale used for unknown locales
+const DefaultLocale = "en-US"
+
+// Format - writes the amount the way the locale does, "$1,234.56" in en-US and "1.234,56 €" in de-DE.
+// the language alone picks the first matching locale, unknown locales are written as en-US
+func (m Money) Format(tag string) string {
+	l := findLocale(tag)
+	whole, fraction := m.split()
+
+	var b strings.Builder
+	if m.Amount < 0 {
+		b.WriteString("-")
+	}
+
+	number := group(whole, l.group)
+	if fraction != "" {
+		number += l.decimal + fraction
+	}
+
+	symbol := m.Currency.Symbol()
+	separator := ""
+	if l.spaced {
+		separator = " "
+	}
+
+	if l.symbolFirst {
+		b.WriteString(symbol + separator + number)
+	} else {
+		b.WriteString(number + separator + symbol)
+	}
+
+	return b.String()
+}
+
+func findLocale(tag string) locale {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+
+	for name, l := range locales {
+		if strings.EqualFold(name, tag) {
+			return l
+		}
+	}
+
+	language, _, _ := strings.Cut(tag, "-")
+	for _, name := range []string{"en-US", "en-GB", "he-IL", "de-DE", "fr-FR", "ja-JP"} {
+		if strings.EqualFold(strings.SplitN(name, "-", 2)[0], language) {
+			return locales[name]
+		}
+	}
+
+	return locales[DefaultLocale]
+}
+
+// group - inserts the group separator every three digits from the right
+func group(digits string, separator string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+
+	return b.String()
+}
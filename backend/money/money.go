@@ -0,0 +1,167 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+	ErrInvalidAmount    = errors.New("invalid amount")
+)
+
+// Money - an amount in the minor units of its currency, 1234 USD is $12.34
+type Money struct {
+	Amount   int64    `json:"amount" db:"amount"`
+	Currency Currency `json:"currency" db:"currency"`
+}
+
+// New - returns the amount of minor units in the currency
+func New(amount int64, currency Currency) (Money, error) {
+	if !currency.Valid() {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Parse - reads a decimal amount like "12.34" in the currency, with no more fractional digits than the currency has
+func Parse(value string, currency Currency) (Money, error) {
+	if !currency.Valid() {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(value, "-"), ".")
+
+	digits := currency.Digits()
+	if whole == "" || len(fraction) > digits {
+		return Money{}, fmt.Errorf("%w: %q in %s", ErrInvalidAmount, value, currency)
+	}
+
+	units, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", digits-len(fraction)), 10, 64)
+	if err != nil || strings.ContainsAny(whole+fraction, "+-") {
+		return Money{}, fmt.Errorf("%w: %q in %s", ErrInvalidAmount, value, currency)
+	}
+
+	if negative {
+		units = -units
+	}
+
+	return Money{Amount: units, Currency: currency}, nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.same(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.same(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Compare - -1, 0 or 1 when m is lower, equal or higher than other
+func (m Money) Compare(other Money) (int, error) {
+	if err := m.same(other); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Decimal - the amount in major units with the digits of the currency, "12.34"
+func (m Money) Decimal() string {
+	whole, fraction := m.split()
+
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+
+	if fraction == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + fraction
+}
+
+// String - the amount and its currency code, "12.34 USD"
+func (m Money) String() string {
+	return m.Decimal() + " " + string(m.Currency)
+}
+
+func (m Money) same(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	return nil
+}
+
+// split - the absolute amount split into its major and minor unit digits
+func (m Money) split() (string, string) {
+	amount := m.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+
+	digits := m.Currency.Digits()
+	units := strconv.FormatInt(amount, 10)
+	if digits == 0 {
+		return units, ""
+	}
+
+	if len(units) <= digits {
+		units = strings.Repeat("0", digits-len(units)+1) + units
+	}
+
+	return units[:len(units)-digits], units[len(units)-digits:]
+}
+
+// Columns - the select expressions of a money stored in the <name>_amount and <name>_currency columns,
+// aliased so they scan into a Money field tagged db:"<name>"
+func Columns(name string) []string {
+	return []string{
+		fmt.Sprintf("%s_amount AS `%s.amount`", name, name),
+		fmt.Sprintf("%s_currency AS `%s.currency`", name, name),
+	}
+}
+
+// ColumnsIn - the select expressions of a money stored in the <name> column with its currency in a currency
+// column shared with other amounts, aliased so they scan into a Money field tagged db:"<name>"
+func ColumnsIn(name string, currency string) []string {
+	return []string{
+		fmt.Sprintf("%s AS `%s.amount`", name, name),
+		fmt.Sprintf("%s AS `%s.currency`", currency, name),
+	}
+}
+
+// Values - the column values of a money stored in the <name>_amount and <name>_currency columns
+func (m Money) Values(name string) map[string]interface{} {
+	return map[string]interface{}{
+		name + "_amount":   m.Amount,
+		name + "_currency": m.Currency,
+	}
+}
@@ -0,0 +1,112 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		currency Currency
+		want     int64
+		err      error
+	}{
+		{"cents", "12.34", USD, 1234, nil},
+		{"whole", "12", USD, 1200, nil},
+		{"one digit", "0.5", EUR, 50, nil},
+		{"no minor units", "500", JPY, 500, nil},
+		{"three digits", "1.005", KWD, 1005, nil},
+		{"negative", "-1.25", USD, -125, nil},
+		{"too many digits", "1.234", USD, 0, ErrInvalidAmount},
+		{"fraction of yen", "1.5", JPY, 0, ErrInvalidAmount},
+		{"not a number", "abc", USD, 0, ErrInvalidAmount},
+		{"unknown currency", "1", Currency("XXX"), 0, ErrUnknownCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.value, tt.currency)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, Money{Amount: tt.want, Currency: tt.currency}, m)
+		})
+	}
+}
+
+func TestArithmeticRefusesMixedCurrencies(t *testing.T) {
+	dollars := Money{Amount: 1000, Currency: USD}
+	euros := Money{Amount: 500, Currency: EUR}
+
+	_, err := dollars.Add(euros)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = dollars.Sub(euros)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = dollars.Compare(euros)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	sum, err := dollars.Add(Money{Amount: 250, Currency: USD})
+	assert.NoError(t, err)
+	assert.Equal(t, Money{Amount: 1250, Currency: USD}, sum)
+
+	diff, err := dollars.Sub(Money{Amount: 1250, Currency: USD})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-250), diff.Amount)
+
+	cmp, err := dollars.Compare(Money{Amount: 999, Currency: USD})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+}
+
+func TestParseCurrency(t *testing.T) {
+	currency, err := ParseCurrency(" eur ")
+	assert.NoError(t, err)
+	assert.Equal(t, EUR, currency)
+
+	_, err = ParseCurrency("euro")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		money  Money
+		locale string
+		want   string
+	}{
+		{Money{123456, USD}, "en-US", "$1,234.56"},
+		{Money{5, USD}, "en-US", "$0.05"},
+		{Money{-123456, USD}, "en-US", "-$1,234.56"},
+		{Money{123456, EUR}, "de-DE", "1.234,56 €"},
+		{Money{123456789, EUR}, "fr-FR", "1 234 567,89 €"},
+		{Money{123456, ILS}, "he-IL", "1,234.56 ₪"},
+		{Money{1234567, JPY}, "ja-JP", "¥1,234,567"},
+		{Money{123456, GBP}, "en_GB", "£1,234.56"},
+		{Money{123456, EUR}, "de", "1.234,56 €"},
+		{Money{123456, USD}, "xx-YY", "$1,234.56"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.money.Format(tt.locale))
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "12.34 USD", Money{1234, USD}.String())
+	assert.Equal(t, "1.005 KWD", Money{1005, KWD}.String())
+	assert.Equal(t, "500 JPY", Money{500, JPY}.String())
+}
+
+func TestColumns(t *testing.T) {
+	assert.Equal(t, []string{"price_amount AS `price.amount`", "price_currency AS `price.currency`"}, Columns("price"))
+	assert.Equal(t, map[string]interface{}{"price_amount": int64(1234), "price_currency": EUR}, Money{1234, EUR}.Values("price"))
+}
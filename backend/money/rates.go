@@ -0,0 +1,178 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+// RateProvider - gives the rate to convert amounts of one currency to another
+type RateProvider interface {
+	Rate(from, to Currency) (Rate, error)
+}
+
+// Rate - one unit of From is worth Value units of To, as of AsOf
+type Rate struct {
+	From  Currency  `json:"from"`
+	To    Currency  `json:"to"`
+	Value float64   `json:"value"`
+	AsOf  time.Time `json:"asOf"`
+}
+
+// Conversion - an amount converted from another currency, conversions are indicative only:
+// prices and bids are placed and settled in their own currency
+type Conversion struct {
+	Money      Money     `json:"money"`
+	Rate       float64   `json:"rate"`
+	AsOf       time.Time `json:"asOf"`
+	Indicative bool      `json:"indicative"`
+}
+
+// Convert - converts m with the rate, rounded to the minor units of the target currency
+func (r Rate) Convert(m Money) (Conversion, error) {
+	if m.Currency != r.From {
+		return Conversion{}, fmt.Errorf("%w: rate from %s applied to %s", ErrCurrencyMismatch, r.From, m.Currency)
+	}
+
+	scale := math.Pow10(r.To.Digits() - r.From.Digits())
+
+	return Conversion{
+		Money:      Money{Amount: int64(math.Round(float64(m.Amount) * r.Value * scale)), Currency: r.To},
+		Rate:       r.Value,
+		AsOf:       r.AsOf,
+		Indicative: true,
+	}, nil
+}
+
+// Convert - converts m to the currency with a rate of the provider, an amount already in the currency is kept as is
+func Convert(rates RateProvider, m Money, to Currency) (Conversion, error) {
+	if m.Currency == to {
+		return Conversion{Money: m, Rate: 1, AsOf: time.Now(), Indicative: true}, nil
+	}
+
+	rate, err := rates.Rate(m.Currency, to)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	return rate.Convert(m)
+}
+
+// RatesFile - the rates of every currency against Base, how many units of the currency one unit of Base is worth
+type RatesFile struct {
+	Base  Currency             `json:"base"`
+	AsOf  time.Time            `json:"asOf"`
+	Rates map[Currency]float64 `json:"rates"`
+}
+
+// StaticProvider - serves fixed rates against a base currency, rates between two other currencies are crossed
+// through the base. used for tests and offline use
+type StaticProvider struct {
+	rates RatesFile
+}
+
+func NewStaticProvider(rates RatesFile) *StaticProvider {
+	if rates.Rates == nil {
+		rates.Rates = map[Currency]float64{}
+	}
+	rates.Rates[rates.Base] = 1
+
+	return &StaticProvider{rates: rates}
+}
+
+// NewFileProvider - loads the rates of a RatesFile written as json
+func NewFileProvider(path string) (*StaticProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rates RatesFile
+	if err = json.NewDecoder(file).Decode(&rates); err != nil {
+		return nil, fmt.Errorf("failed decoding rates file %s: %w", path, err)
+	}
+
+	if !rates.Base.Valid() {
+		return nil, fmt.Errorf("rates file %s: %w: base %q", path, ErrUnknownCurrency, rates.Base)
+	}
+
+	return NewStaticProvider(rates), nil
+}
+
+func (p *StaticProvider) Rate(from, to Currency) (Rate, error) {
+	fromBase, okFrom := p.rates.Rates[from]
+	toBase, okTo := p.rates.Rates[to]
+
+	if !okFrom || !okTo || fromBase <= 0 {
+		return Rate{}, fmt.Errorf("%w: %s to %s", ErrRateUnavailable, from, to)
+	}
+
+	return Rate{From: from, To: to, Value: toBase / fromBase, AsOf: p.rates.AsOf}, nil
+}
+
+// Cache - a key value store with expiry, redis in production
+type Cache interface {
+	Get(key string) (interface{}, error)
+	Set(key string, value interface{}, ttl time.Duration) error
+}
+
+// CachedProvider - keeps the rates of the provider in the cache for ttl so the provider is asked
+// at most once per pair every ttl
+type CachedProvider struct {
+	provider RateProvider
+	cache    Cache
+	ttl      time.Duration
+	logger   *zap.Logger
+}
+
+func NewCachedProvider(provider RateProvider, cache Cache, ttl time.Duration, logger *zap.Logger) *CachedProvider {
+
+	return &CachedProvider{provider: provider, cache: cache, ttl: ttl, logger: logger}
+}
+
+func (p *CachedProvider) Rate(from, to Currency) (Rate, error) {
+	key := fmt.Sprintf("rates:%s:%s", from, to)
+
+	if cached, err := p.cache.Get(key); err == nil {
+		var rate Rate
+		if err = json.Unmarshal(cachedBytes(cached), &rate); err == nil {
+			return rate, nil
+		}
+		p.logger.Warn("CachedProvider.Rate failed decoding cached rate", zap.String("key", key), zap.Error(err))
+	}
+
+	rate, err := p.provider.Rate(from, to)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	encoded, err := json.Marshal(rate)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	if err = p.cache.Set(key, encoded, p.ttl); err != nil {
+		p.logger.Warn("CachedProvider.Rate failed caching rate", zap.String("key", key), zap.Error(err))
+	}
+
+	return rate, nil
+}
+
+func cachedBytes(value interface{}) []byte {
+	switch v := value.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockCache struct {
+	mock mock.Mock
+}
+
+func (m *MockCache) Get(key string) (interface{}, error) {
+	args := m.mock.Called(key)
+
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockCache) Set(key string, value interface{}, ttl time.Duration) error {
+	args := m.mock.Called(key, value, ttl)
+
+	return args.Error(0)
+}
+
+func testRates() *StaticProvider {
+	return NewStaticProvider(RatesFile{
+		Base:  USD,
+		AsOf:  time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Rates: map[Currency]float64{EUR: 0.8, JPY: 150, KWD: 0.3},
+	})
+}
+
+func TestStaticProvider_Rate(t *testing.T) {
+	rates := testRates()
+
+	rate, err := rates.Rate(USD, EUR)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.8, rate.Value)
+
+	rate, err = rates.Rate(EUR, JPY)
+	assert.NoError(t, err)
+	assert.InDelta(t, 187.5, rate.Value, 1e-9)
+
+	_, err = rates.Rate(USD, GBP)
+	assert.ErrorIs(t, err, ErrRateUnavailable)
+}
+
+func TestConvert(t *testing.T) {
+	rates := testRates()
+
+	tests := []struct {
+		name  string
+		money Money
+		to    Currency
+		want  Money
+	}{
+		{"dollars to euros", Money{1000, USD}, EUR, Money{800, EUR}},
+		{"dollars to yen drops the minor units", Money{1234, USD}, JPY, Money{1851, JPY}},
+		{"yen to dollars", Money{1500, JPY}, USD, Money{1000, USD}},
+		{"dollars to dinars adds a digit", Money{1000, USD}, KWD, Money{3000, KWD}},
+		{"same currency", Money{1000, USD}, USD, Money{1000, USD}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversion, err := Convert(rates, tt.money, tt.to)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, conversion.Money)
+			assert.True(t, conversion.Indicative)
+		})
+	}
+
+	_, err := Rate{From: EUR, To: USD, Value: 1.25}.Convert(Money{100, USD})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestNewFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"base":"EUR","asOf":"2026-10-01T00:00:00Z","rates":{"USD":1.25}}`), 0o600))
+
+	rates, err := NewFileProvider(path)
+	assert.NoError(t, err)
+
+	rate, err := rates.Rate(USD, EUR)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.8, rate.Value)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"base":"XXX","rates":{}}`), 0o600))
+	_, err = NewFileProvider(path)
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	_, err = NewFileProvider("../money/rates.json")
+	assert.NoError(t, err)
+}
+
+func TestCachedProvider_Rate(t *testing.T) {
+	cache := &MockCache{}
+	provider := NewCachedProvider(testRates(), cache, time.Minute, zap.NewNop())
+	cached, _ := json.Marshal(Rate{From: USD, To: EUR, Value: 0.5})
+
+	cache.mock.On("Get", "rates:USD:EUR").Return(string(cached), nil)
+	cache.mock.On("Get", "rates:USD:JPY").Return(nil, errors.New("redis: nil"))
+	cache.mock.On("Set", "rates:USD:JPY", mock.Anything, time.Minute).Return(nil)
+
+	rate, err := provider.Rate(USD, EUR)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, rate.Value)
+
+	rate, err = provider.Rate(USD, JPY)
+	assert.NoError(t, err)
+	assert.Equal(t, 150.0, rate.Value)
+	cache.mock.AssertCalled(t, "Set", "rates:USD:JPY", mock.Anything, time.Minute)
+	cache.mock.AssertNotCalled(t, "Set", "rates:USD:EUR", mock.Anything, mock.Anything)
+}
+
+func TestRegionCurrency(t *testing.T) {
+	assert.Equal(t, EUR, RegionCurrency("eu"))
+	assert.Equal(t, ILS, RegionCurrency("IL"))
+	assert.Equal(t, USD, RegionCurrency("North"))
+}
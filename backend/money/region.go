@@ -0,0 +1,28 @@
+package money
+
+import "strings"
+
+// regionCurrencies - the currency shown by default to the users of a region, users.User.Region
+var regionCurrencies = map[string]Currency{
+	"US": USD,
+	"EU": EUR,
+	"DE": EUR,
+	"FR": EUR,
+	"GB": GBP,
+	"UK": GBP,
+	"IL": ILS,
+	"JP": JPY,
+	"CH": CHF,
+	"CA": CAD,
+	"AU": AUD,
+	"KW": KWD,
+}
+
+// RegionCurrency - the preferred currency of a region, Default for unknown regions
+func RegionCurrency(region string) Currency {
+	if currency, ok := regionCurrencies[strings.ToUpper(strings.TrimSpace(region))]; ok {
+		return currency
+	}
+
+	return Default
+}
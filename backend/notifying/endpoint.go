@@ -0,0 +1,109 @@
+package notifying
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"github.com/ireuven89/hello-world/backend/notifying/model"
+)
+
+type WatchRequest struct {
+	watch model.WatchInput
+}
+
+func MakeEndpointWatch(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(WatchRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointWatch failed cast request")
+		}
+
+		if err = s.Watch(req.watch); err != nil {
+			return nil, fmt.Errorf("MakeEndpointWatch: %w", err)
+		}
+
+		return nil, nil
+	}
+}
+
+func MakeEndpointUnwatch(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(WatchRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointUnwatch failed cast request")
+		}
+
+		if err = s.Unwatch(req.watch); err != nil {
+			return nil, fmt.Errorf("MakeEndpointUnwatch: %w", err)
+		}
+
+		return nil, nil
+	}
+}
+
+type WatchlistRequest struct {
+	UserUuid string
+}
+
+func MakeEndpointWatchlist(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(WatchlistRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointWatchlist failed cast request")
+		}
+
+		result, err := s.Watchlist(req.UserUuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointWatchlist: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ListNotificationsRequest struct {
+	input model.ListInput
+}
+
+type ListNotificationsResponse struct {
+	list model.NotificationList
+}
+
+func MakeEndpointListNotifications(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListNotificationsRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListNotifications failed cast request")
+		}
+
+		result, err := s.ListNotifications(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListNotifications: %w", err)
+		}
+
+		return ListNotificationsResponse{
+			list: result,
+		}, nil
+	}
+}
+
+type MarkReadRequest struct {
+	read model.ReadInput
+}
+
+func MakeEndpointMarkRead(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(MarkReadRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointMarkRead failed cast request")
+		}
+
+		if err = s.MarkRead(req.read); err != nil {
+			return nil, fmt.Errorf("MakeEndpointMarkRead: %w", err)
+		}
+
+		return nil, nil
+	}
+}
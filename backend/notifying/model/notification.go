@@ -0,0 +1,76 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput = errors.New("invalid input")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+type Kind string
+
+const (
+	Outbid     Kind = "outbid"
+	EndingSoon Kind = "ending_soon"
+	Won        Kind = "won"
+)
+
+// Watch - an auction on the watchlist of a user
+type Watch struct {
+	ID          int64     `json:"-" db:"id"`
+	UserUuid    string    `json:"userUuid" db:"user_uuid"`
+	AuctionUuid string    `json:"auctionUuid" db:"auction_uuid"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Notification - tells a user something happened on an auction, Amount is the winning price at that moment
+type Notification struct {
+	ID          int64      `json:"-" db:"id"`
+	Uuid        string     `json:"uuid" db:"uuid"`
+	UserUuid    string     `json:"userUuid" db:"user_uuid"`
+	AuctionUuid string     `json:"auctionUuid" db:"auction_uuid"`
+	Kind        Kind       `json:"kind" db:"kind"`
+	Amount      int64      `json:"amount" db:"amount"`
+	ExpiredAt   time.Time  `json:"expiredAt" db:"expired_at"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	ReadAt      *time.Time `json:"readAt,omitempty" db:"read_at"`
+}
+
+type WatchInput struct {
+	UserUuid    string `json:"-"`
+	AuctionUuid string `json:"-"`
+}
+
+type ListInput struct {
+	UserUuid string
+	Unread   bool
+	Page     PageRequest
+}
+
+type NotificationList struct {
+	Notifications []Notification
+	Total         int64
+}
+
+// ReadInput - marks the given notifications of the user as read, all of them when Uuids is empty
+type ReadInput struct {
+	UserUuid string   `json:"-"`
+	Uuids    []string `json:"uuids"`
+}
+
+type PageRequest struct {
+	Offset int64
+	Limit  int64
+}
+
+func (p *PageRequest) GetLimit() int64 {
+	if p.Limit == 0 {
+		return 50
+	}
+
+	return p.Limit
+}
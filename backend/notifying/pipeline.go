@@ -0,0 +1,136 @@
+package notifying
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"go.uber.org/zap"
+
+	auctionmodel "github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/notifying/model"
+)
+
+// HandleEvent - turns a consumed auction event into notifications. the outbid bidders are told on every bid event,
+// the watchers are told when the auction is ending soon and the winner is told once the auction is sold.
+// the new leader of a bid event starts watching the auction so it hears when the auction is ending soon.
+// events carry bidders, the watchlists and the notifications belong to the users the bidders bid for
+func (s *ServiceNotification) HandleEvent(message []byte) error {
+	var event auctionmodel.Event
+
+	if err := json.Unmarshal(message, &event); err != nil {
+		s.logger.Error("ServiceNotification.HandleEvent failed decoding event", zap.ByteString("message", message), zap.Error(err))
+		return err
+	}
+
+	var recipients []string
+	var kind model.Kind
+
+	switch event.Type {
+	case auctionmodel.EventBid, auctionmodel.EventExtended:
+		winner, err := s.user(event.WinnerUuid)
+		if err != nil {
+			s.logger.Error("ServiceNotification.HandleEvent failed finding the winner", zap.Any("event", event), zap.Error(err))
+			return err
+		}
+
+		if winner != "" {
+			if err := s.repo.Watch(model.WatchInput{UserUuid: winner, AuctionUuid: event.AuctionUuid}); err != nil {
+				s.logger.Error("ServiceNotification.HandleEvent failed watching auction", zap.Any("event", event), zap.Error(err))
+				return err
+			}
+		}
+
+		if recipients, err = s.outbid(event, winner); err != nil {
+			s.logger.Error("ServiceNotification.HandleEvent failed finding the outbid users", zap.Any("event", event), zap.Error(err))
+			return err
+		}
+		kind = model.Outbid
+	case auctionmodel.EventEndingSoon:
+		watchers, err := s.repo.Watchers(event.AuctionUuid)
+		if err != nil {
+			s.logger.Error("ServiceNotification.HandleEvent failed listing watchers", zap.Any("event", event), zap.Error(err))
+			return err
+		}
+		kind, recipients = model.EndingSoon, watchers
+	case auctionmodel.EventClosed:
+		if event.Status != auctionmodel.Sold {
+			break
+		}
+
+		winner, err := s.user(event.WinnerUuid)
+		if err != nil {
+			s.logger.Error("ServiceNotification.HandleEvent failed finding the winner", zap.Any("event", event), zap.Error(err))
+			return err
+		}
+
+		if winner != "" {
+			kind, recipients = model.Won, []string{winner}
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	notifications := make([]model.Notification, 0, len(recipients))
+	for _, userUuid := range recipients {
+		notifications = append(notifications, model.Notification{
+			UserUuid:    userUuid,
+			AuctionUuid: event.AuctionUuid,
+			Kind:        kind,
+			Amount:      event.WinningPrice,
+			ExpiredAt:   event.ExpiredAt,
+		})
+	}
+
+	if err := s.repo.CreateNotifications(notifications); err != nil {
+		s.logger.Error("ServiceNotification.HandleEvent failed creating notifications", zap.Any("event", event), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// outbid - the users of the bidders a bid event leaves behind the winner: the previous leader, and the bidder
+// who placed the bid when a proxy auto-bid beat it at once. the user of the winner is never told it was outbid
+func (s *ServiceNotification) outbid(event auctionmodel.Event, winner string) ([]string, error) {
+	var recipients []string
+
+	for _, bidderUuid := range []string{event.PreviousWinnerUuid, event.BidderUuid} {
+		if bidderUuid == event.WinnerUuid {
+			continue
+		}
+
+		userUuid, err := s.user(bidderUuid)
+		if err != nil {
+			return nil, err
+		}
+
+		if userUuid == "" || userUuid == winner || slices.Contains(recipients, userUuid) {
+			continue
+		}
+		recipients = append(recipients, userUuid)
+	}
+
+	return recipients, nil
+}
+
+// user - the user a bidder bids for, empty when there is no bidder or the bidder is gone or bids for no user
+func (s *ServiceNotification) user(bidderUuid string) (string, error) {
+	if bidderUuid == "" {
+		return "", nil
+	}
+
+	bidder, err := s.bidders.FindOne(bidderUuid)
+	if errors.Is(err, bidermodel.ErrNotFound) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return bidder.UserUuid, nil
+}
@@ -0,0 +1,229 @@
+package notifying
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	auctionmodel "github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/notifying/model"
+)
+
+func encodeEvent(t *testing.T, event auctionmodel.Event) []byte {
+	message, err := json.Marshal(event)
+	assert.NoError(t, err)
+
+	return message
+}
+
+// stubBidders - the user each known bidder bids for, other bidders are not found
+type stubBidders map[string]string
+
+func (b stubBidders) FindOne(uuid string) (bidermodel.Bidder, error) {
+	userUuid, ok := b[uuid]
+	if !ok {
+		return bidermodel.Bidder{}, bidermodel.ErrNotFound
+	}
+
+	return bidermodel.Bidder{Uuid: uuid, UserUuid: userUuid}, nil
+}
+
+var knownBidders = stubBidders{"bidder-a": "user-a", "bidder-a2": "user-a", "bidder-b": "user-b"}
+
+// failingBidders - the bidders lookup is down
+type failingBidders struct{}
+
+func (failingBidders) FindOne(uuid string) (bidermodel.Bidder, error) {
+	return bidermodel.Bidder{}, errors.New("bidders are down")
+}
+
+func TestServiceNotification_HandleEventOutbid(t *testing.T) {
+	expiredAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+	repo.mock.On("Watch", model.WatchInput{UserUuid: "user-a", AuctionUuid: "auction-uuid"}).Return(nil)
+	repo.mock.On("CreateNotifications", []model.Notification{
+		{UserUuid: "user-b", AuctionUuid: "auction-uuid", Kind: model.Outbid, Amount: 150, ExpiredAt: expiredAt},
+	}).Return(nil)
+
+	err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+		Type:               auctionmodel.EventBid,
+		AuctionUuid:        "auction-uuid",
+		Status:             auctionmodel.InProgress,
+		WinnerUuid:         "bidder-a",
+		PreviousWinnerUuid: "bidder-b",
+		WinningPrice:       150,
+		ExpiredAt:          expiredAt,
+	}))
+
+	assert.NoError(t, err)
+	repo.mock.AssertExpectations(t)
+}
+
+func TestServiceNotification_HandleEventOutbidByProxy(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+	repo.mock.On("Watch", model.WatchInput{UserUuid: "user-a", AuctionUuid: "auction-uuid"}).Return(nil)
+	repo.mock.On("CreateNotifications", []model.Notification{
+		{UserUuid: "user-b", AuctionUuid: "auction-uuid", Kind: model.Outbid, Amount: 160},
+	}).Return(nil)
+
+	err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+		Type:               auctionmodel.EventBid,
+		AuctionUuid:        "auction-uuid",
+		Status:             auctionmodel.InProgress,
+		WinnerUuid:         "bidder-a",
+		PreviousWinnerUuid: "bidder-a",
+		BidderUuid:         "bidder-b",
+		WinningPrice:       160,
+	}))
+
+	assert.NoError(t, err)
+	repo.mock.AssertExpectations(t)
+}
+
+func TestServiceNotification_HandleEventLeaderRaisesOwnBid(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+	repo.mock.On("Watch", mock.Anything).Return(nil)
+
+	err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+		Type:               auctionmodel.EventExtended,
+		AuctionUuid:        "auction-uuid",
+		WinnerUuid:         "bidder-a",
+		PreviousWinnerUuid: "bidder-a",
+		WinningPrice:       150,
+	}))
+
+	assert.NoError(t, err)
+	repo.mock.AssertNotCalled(t, "CreateNotifications", mock.Anything)
+}
+
+func TestServiceNotification_HandleEventOutbidBySameUser(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+	repo.mock.On("Watch", model.WatchInput{UserUuid: "user-a", AuctionUuid: "auction-uuid"}).Return(nil)
+
+	err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+		Type:               auctionmodel.EventBid,
+		AuctionUuid:        "auction-uuid",
+		WinnerUuid:         "bidder-a2",
+		PreviousWinnerUuid: "bidder-a",
+		WinningPrice:       150,
+	}))
+
+	assert.NoError(t, err)
+	repo.mock.AssertNotCalled(t, "CreateNotifications", mock.Anything)
+}
+
+func TestServiceNotification_HandleEventUnknownBidder(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+
+	err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+		Type:               auctionmodel.EventBid,
+		AuctionUuid:        "auction-uuid",
+		WinnerUuid:         "bidder-gone",
+		PreviousWinnerUuid: "bidder-gone-too",
+		WinningPrice:       150,
+	}))
+
+	assert.NoError(t, err)
+	repo.mock.AssertNotCalled(t, "Watch", mock.Anything)
+	repo.mock.AssertNotCalled(t, "CreateNotifications", mock.Anything)
+}
+
+func TestServiceNotification_HandleEventEndingSoon(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+	repo.mock.On("Watchers", "auction-uuid").Return([]string{"user-a", "user-b"}, nil)
+	repo.mock.On("CreateNotifications", mock.MatchedBy(func(notifications []model.Notification) bool {
+		return len(notifications) == 2 &&
+			notifications[0].UserUuid == "user-a" && notifications[0].Kind == model.EndingSoon &&
+			notifications[1].UserUuid == "user-b" && notifications[1].Kind == model.EndingSoon
+	})).Return(nil)
+
+	err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+		Type:        auctionmodel.EventEndingSoon,
+		AuctionUuid: "auction-uuid",
+		Status:      auctionmodel.InProgress,
+	}))
+
+	assert.NoError(t, err)
+	repo.mock.AssertExpectations(t)
+}
+
+func TestServiceNotification_HandleEventClosed(t *testing.T) {
+	t.Run("sold auction tells the winner", func(t *testing.T) {
+		repo := &MockRepository{}
+		service := New(repo, knownBidders, zap.NewNop())
+		repo.mock.On("CreateNotifications", mock.MatchedBy(func(notifications []model.Notification) bool {
+			return len(notifications) == 1 && notifications[0].UserUuid == "user-a" &&
+				notifications[0].Kind == model.Won && notifications[0].Amount == 300
+		})).Return(nil)
+
+		err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+			Type:         auctionmodel.EventClosed,
+			AuctionUuid:  "auction-uuid",
+			Status:       auctionmodel.Sold,
+			WinnerUuid:   "bidder-a",
+			WinningPrice: 300,
+		}))
+
+		assert.NoError(t, err)
+		repo.mock.AssertExpectations(t)
+	})
+
+	t.Run("expired auction tells nobody", func(t *testing.T) {
+		repo := &MockRepository{}
+		service := New(repo, knownBidders, zap.NewNop())
+
+		err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+			Type:         auctionmodel.EventClosed,
+			AuctionUuid:  "auction-uuid",
+			Status:       auctionmodel.Expired,
+			WinnerUuid:   "bidder-a",
+			WinningPrice: 300,
+		}))
+
+		assert.NoError(t, err)
+		repo.mock.AssertNotCalled(t, "CreateNotifications", mock.Anything)
+	})
+}
+
+func TestServiceNotification_HandleEventErrors(t *testing.T) {
+	t.Run("malformed message", func(t *testing.T) {
+		service := New(&MockRepository{}, knownBidders, zap.NewNop())
+
+		err := service.HandleEvent([]byte("not json"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("bidders lookup fails", func(t *testing.T) {
+		repo := &MockRepository{}
+		service := New(repo, failingBidders{}, zap.NewNop())
+
+		err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{Type: auctionmodel.EventClosed, AuctionUuid: "auction-uuid", Status: auctionmodel.Sold, WinnerUuid: "bidder-a"}))
+
+		assert.Error(t, err)
+		repo.mock.AssertNotCalled(t, "CreateNotifications", mock.Anything)
+	})
+
+	t.Run("watchers lookup fails", func(t *testing.T) {
+		repo := &MockRepository{}
+		service := New(repo, knownBidders, zap.NewNop())
+		repo.mock.On("Watchers", "auction-uuid").Return(nil, errors.New("db is down"))
+
+		err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{Type: auctionmodel.EventEndingSoon, AuctionUuid: "auction-uuid"}))
+
+		assert.Error(t, err)
+		repo.mock.AssertNotCalled(t, "CreateNotifications", mock.Anything)
+	})
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+	"github.com/ireuven89/hello-world/backend/notifying/model"
+)
+
+// duplicateEntry - the mysql error number of a unique key violation
+const duplicateEntry = 1062
+
+var watchColumns = []string{
+	"id",
+	"user_uuid",
+	"auction_uuid",
+	"created_at",
+}
+
+var notificationColumns = []string{
+	"id",
+	"uuid",
+	"user_uuid",
+	"auction_uuid",
+	"kind",
+	"amount",
+	"expired_at",
+	"created_at",
+	"read_at",
+}
+
+type NotificationRepository struct {
+	db     *sqlz.DB
+	logger *zap.Logger
+}
+
+func New(db *sqlz.DB, logger *zap.Logger) *NotificationRepository {
+
+	return &NotificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Watch - adds the auction to the watchlist of the user, watching an auction twice is a no-op
+func (r *NotificationRepository) Watch(input model.WatchInput) error {
+	q := r.db.
+		InsertInto(dbmodel.Watchlist).
+		ValueMap(map[string]interface{}{
+			"user_uuid":    input.UserUuid,
+			"auction_uuid": input.AuctionUuid,
+			"created_at":   time.Now(),
+		})
+
+	utils.New().DebugInsert(q, "insert watch")
+
+	if _, err := q.Exec(); err != nil && !isDuplicate(err) {
+		r.logger.Error("NotificationRepository.Watch failed inserting watch", zap.Any("input", input), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Unwatch - removes the auction from the watchlist of the user
+func (r *NotificationRepository) Unwatch(input model.WatchInput) error {
+	q := r.db.
+		DeleteFrom(dbmodel.Watchlist).
+		Where(sqlz.Eq("user_uuid", input.UserUuid), sqlz.Eq("auction_uuid", input.AuctionUuid))
+
+	utils.New().DebugDelete(q, "delete watch")
+
+	res, err := q.Exec()
+	if err != nil {
+		r.logger.Error("NotificationRepository.Unwatch failed deleting watch", zap.Any("input", input), zap.Error(err))
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// Watchlist - this method queries the watched auctions of a user, latest first
+func (r *NotificationRepository) Watchlist(userUuid string) ([]model.Watch, error) {
+	var result []model.Watch
+
+	q := r.db.
+		Select(watchColumns...).
+		From(dbmodel.Watchlist).
+		Where(sqlz.Eq("user_uuid", userUuid)).
+		OrderBy(sqlz.Desc("id"))
+
+	utils.New().DebugSelect(q, "list watchlist")
+
+	if err := q.GetAll(&result); err != nil {
+		r.logger.Error("NotificationRepository.Watchlist failed listing watchlist", zap.String("userUuid", userUuid), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Watchers - returns the uuids of the users watching the auction
+func (r *NotificationRepository) Watchers(auctionUuid string) ([]string, error) {
+	var result []string
+
+	q := r.db.
+		Select("user_uuid").
+		From(dbmodel.Watchlist).
+		Where(sqlz.Eq("auction_uuid", auctionUuid))
+
+	utils.New().DebugSelect(q, "list watchers")
+
+	if err := q.GetAll(&result); err != nil {
+		r.logger.Error("NotificationRepository.Watchers failed listing watchers", zap.String("auctionUuid", auctionUuid), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateNotifications - inserts the notifications in a single statement
+func (r *NotificationRepository) CreateNotifications(notifications []model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	values := make([][]interface{}, 0, len(notifications))
+	for _, notification := range notifications {
+		values = append(values, []interface{}{
+			uuid.New().String(),
+			notification.UserUuid,
+			notification.AuctionUuid,
+			notification.Kind,
+			notification.Amount,
+			notification.ExpiredAt,
+			now,
+		})
+	}
+
+	q := r.db.
+		InsertInto(dbmodel.Notifications).
+		Columns("uuid", "user_uuid", "auction_uuid", "kind", "amount", "expired_at", "created_at").
+		ValueMultiple(values)
+
+	utils.New().DebugInsert(q, "insert notifications")
+
+	if _, err := q.Exec(); err != nil {
+		r.logger.Error("NotificationRepository.CreateNotifications failed inserting notifications", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListNotifications - this method queries a page of the notifications of a user, latest first
+func (r *NotificationRepository) ListNotifications(input model.ListInput) (model.NotificationList, error) {
+	var result model.NotificationList
+
+	where := []sqlz.WhereCondition{sqlz.Eq("user_uuid", input.UserUuid)}
+	if input.Unread {
+		where = append(where, sqlz.IsNull("read_at"))
+	}
+
+	q := r.db.
+		Select(notificationColumns...).
+		From(dbmodel.Notifications).
+		Where(where...).
+		OrderBy(sqlz.Desc("id")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list notifications")
+
+	if err := q.GetAll(&result.Notifications); err != nil {
+		r.logger.Error("NotificationRepository.ListNotifications failed listing notifications", zap.Error(err))
+		return model.NotificationList{}, err
+	}
+
+	total, err := r.db.
+		Select("*").
+		From(dbmodel.Notifications).
+		Where(where...).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("NotificationRepository.ListNotifications failed counting notifications", zap.Error(err))
+		return model.NotificationList{}, err
+	}
+	result.Total = total
+
+	return result, nil
+}
+
+// MarkRead - marks the unread notifications of the user as read
+func (r *NotificationRepository) MarkRead(input model.ReadInput) error {
+	where := []sqlz.WhereCondition{sqlz.Eq("user_uuid", input.UserUuid), sqlz.IsNull("read_at")}
+	if len(input.Uuids) > 0 {
+		uuids := make([]interface{}, 0, len(input.Uuids))
+		for _, id := range input.Uuids {
+			uuids = append(uuids, id)
+		}
+		where = append(where, sqlz.In("uuid", uuids...))
+	}
+
+	q := r.db.
+		Update(dbmodel.Notifications).
+		Set("read_at", time.Now()).
+		Where(where...)
+
+	utils.New().DebugUpdate(q, "mark notifications read")
+
+	if _, err := q.Exec(); err != nil {
+		r.logger.Error("NotificationRepository.MarkRead failed updating notifications", zap.Any("input", input), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == duplicateEntry
+}
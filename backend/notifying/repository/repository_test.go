@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/ireuven89/hello-world/backend/notifying/model"
+)
+
+func TestNotificationRepository_WatchTwice(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("INSERT INTO watchlist").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+	err = repo.Watch(model.WatchInput{UserUuid: "user-uuid", AuctionUuid: "auction-uuid"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_WatchFails(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("INSERT INTO watchlist").
+		WillReturnError(errors.New("db is down"))
+
+	err = repo.Watch(model.WatchInput{UserUuid: "user-uuid", AuctionUuid: "auction-uuid"})
+
+	assert.Error(t, err)
+}
+
+func TestNotificationRepository_UnwatchNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("DELETE FROM watchlist WHERE user_uuid = \\? AND auction_uuid = \\?").
+		WithArgs("user-uuid", "auction-uuid").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Unwatch(model.WatchInput{UserUuid: "user-uuid", AuctionUuid: "auction-uuid"})
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_Watchers(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT user_uuid FROM watchlist WHERE auction_uuid = \\?").
+		WithArgs("auction-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid"}).AddRow("user-a").AddRow("user-b"))
+
+	watchers, err := repo.Watchers("auction-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user-a", "user-b"}, watchers)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_CreateNotifications(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("INSERT INTO notifications \\(uuid, user_uuid, auction_uuid, kind, amount, expired_at, created_at\\) VALUES \\(.+\\), \\(.+\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.CreateNotifications([]model.Notification{
+		{UserUuid: "user-a", AuctionUuid: "auction-uuid", Kind: model.EndingSoon},
+		{UserUuid: "user-b", AuctionUuid: "auction-uuid", Kind: model.EndingSoon},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_ListUnreadNotifications(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectQuery("SELECT (.+) FROM notifications WHERE user_uuid = \\? AND read_at IS NULL ORDER BY id DESC LIMIT 10 OFFSET 20").
+		WithArgs("user-uuid").
+		WillReturnRows(sqlmock.NewRows(notificationColumns).
+			AddRow(1, "notification-uuid", "user-uuid", "auction-uuid", model.Outbid, 150, now, now, nil))
+	mockSql.ExpectQuery("SELECT COUNT\\(\\*\\) FROM notifications WHERE user_uuid = \\? AND read_at IS NULL").
+		WithArgs("user-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+
+	result, err := repo.ListNotifications(model.ListInput{UserUuid: "user-uuid", Unread: true, Page: model.PageRequest{Offset: 20, Limit: 10}})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Notifications, 1)
+	assert.Equal(t, model.Outbid, result.Notifications[0].Kind)
+	assert.Nil(t, result.Notifications[0].ReadAt)
+	assert.Equal(t, int64(21), result.Total)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_MarkRead(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("UPDATE notifications SET read_at = \\? WHERE user_uuid = \\? AND read_at IS NULL AND uuid IN \\(\\?, \\?\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.MarkRead(model.ReadInput{UserUuid: "user-uuid", Uuids: []string{"notification-a", "notification-b"}})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,110 @@
+package notifying
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/notifying/model"
+)
+
+type Service interface {
+	Watch(input model.WatchInput) error
+	Unwatch(input model.WatchInput) error
+	Watchlist(userUuid string) ([]model.Watch, error)
+	ListNotifications(input model.ListInput) (model.NotificationList, error)
+	MarkRead(input model.ReadInput) error
+	HandleEvent(message []byte) error
+}
+
+type Repository interface {
+	Watch(input model.WatchInput) error
+	Unwatch(input model.WatchInput) error
+	Watchlist(userUuid string) ([]model.Watch, error)
+	Watchers(auctionUuid string) ([]string, error)
+	CreateNotifications(notifications []model.Notification) error
+	ListNotifications(input model.ListInput) (model.NotificationList, error)
+	MarkRead(input model.ReadInput) error
+}
+
+// Bidders - finds the user a bidder of an auction event bids for
+type Bidders interface {
+	FindOne(uuid string) (bidermodel.Bidder, error)
+}
+
+type ServiceNotification struct {
+	repo    Repository
+	bidders Bidders
+	logger  *zap.Logger
+}
+
+func New(repo Repository, bidders Bidders, logger *zap.Logger) Service {
+
+	return &ServiceNotification{repo: repo, bidders: bidders, logger: logger}
+}
+
+func (s *ServiceNotification) Watch(input model.WatchInput) error {
+	if err := validateWatch(input); err != nil {
+		return err
+	}
+
+	if err := s.repo.Watch(input); err != nil {
+		s.logger.Error("ServiceNotification.Watch failed watching auction", zap.Any("input", input), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *ServiceNotification) Unwatch(input model.WatchInput) error {
+	if err := validateWatch(input); err != nil {
+		return err
+	}
+
+	if err := s.repo.Unwatch(input); err != nil {
+		s.logger.Error("ServiceNotification.Unwatch failed unwatching auction", zap.Any("input", input), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *ServiceNotification) Watchlist(userUuid string) ([]model.Watch, error) {
+	result, err := s.repo.Watchlist(userUuid)
+
+	if err != nil {
+		s.logger.Error("ServiceNotification.Watchlist failed listing watchlist", zap.String("userUuid", userUuid), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *ServiceNotification) ListNotifications(input model.ListInput) (model.NotificationList, error) {
+	result, err := s.repo.ListNotifications(input)
+
+	if err != nil {
+		s.logger.Error("ServiceNotification.ListNotifications failed listing notifications", zap.Any("input", input), zap.Error(err))
+		return model.NotificationList{}, err
+	}
+
+	return result, nil
+}
+
+func (s *ServiceNotification) MarkRead(input model.ReadInput) error {
+	if err := s.repo.MarkRead(input); err != nil {
+		s.logger.Error("ServiceNotification.MarkRead failed marking notifications", zap.Any("input", input), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func validateWatch(input model.WatchInput) error {
+	if input.UserUuid == "" || input.AuctionUuid == "" {
+		return fmt.Errorf("%w: user and auction are required", model.ErrInvalidInput)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package notifying
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/notifying/model"
+)
+
+type MockRepository struct {
+	mock mock.Mock
+}
+
+func (m *MockRepository) Watch(input model.WatchInput) error {
+	args := m.mock.Called(input)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) Unwatch(input model.WatchInput) error {
+	args := m.mock.Called(input)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) Watchlist(userUuid string) ([]model.Watch, error) {
+	args := m.mock.Called(userUuid)
+	watches, _ := args.Get(0).([]model.Watch)
+
+	return watches, args.Error(1)
+}
+
+func (m *MockRepository) Watchers(auctionUuid string) ([]string, error) {
+	args := m.mock.Called(auctionUuid)
+	watchers, _ := args.Get(0).([]string)
+
+	return watchers, args.Error(1)
+}
+
+func (m *MockRepository) CreateNotifications(notifications []model.Notification) error {
+	args := m.mock.Called(notifications)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListNotifications(input model.ListInput) (model.NotificationList, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(model.NotificationList), args.Error(1)
+}
+
+func (m *MockRepository) MarkRead(input model.ReadInput) error {
+	args := m.mock.Called(input)
+
+	return args.Error(0)
+}
+
+func TestServiceNotification_Watch(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+	input := model.WatchInput{UserUuid: "user-uuid", AuctionUuid: "auction-uuid"}
+	repo.mock.On("Watch", input).Return(nil)
+
+	err := service.Watch(input)
+
+	assert.NoError(t, err)
+	repo.mock.AssertExpectations(t)
+}
+
+func TestServiceNotification_WatchInvalidInput(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+
+	err := service.Watch(model.WatchInput{UserUuid: "user-uuid"})
+
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	repo.mock.AssertNotCalled(t, "Watch", mock.Anything)
+}
+
+func TestServiceNotification_UnwatchNotFound(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+	input := model.WatchInput{UserUuid: "user-uuid", AuctionUuid: "auction-uuid"}
+	repo.mock.On("Unwatch", input).Return(model.ErrNotFound)
+
+	err := service.Unwatch(input)
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestServiceNotification_ListNotificationsError(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, knownBidders, zap.NewNop())
+	input := model.ListInput{UserUuid: "user-uuid"}
+	repo.mock.On("ListNotifications", input).Return(model.NotificationList{}, errors.New("db is down"))
+
+	_, err := service.ListNotifications(input)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+package notifying
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/julienschmidt/httprouter"
+	"github.com/labstack/gommon/log"
+
+	"github.com/ireuven89/hello-world/backend/authenticating"
+	"github.com/ireuven89/hello-world/backend/notifying/model"
+)
+
+func NewTransport(s Service, router *httprouter.Router, verifier authenticating.Verifier) Transport {
+
+	transport := Transport{
+		router: router,
+		s:      s,
+	}
+	RegisterRoutes(router, s, verifier) // Register routes during initialization
+	return transport
+}
+
+type Transport struct {
+	router *httprouter.Router
+	s      Service
+}
+
+func (t *Transport) ListenAndServe(port string) {
+	log.Printf("Starting server on port %s...", port)
+	err := http.ListenAndServe(":"+port, t.router)
+	if err != nil {
+		log.Fatalf("Server failed to start: %v", err)
+	}
+}
+
+// RegisterRoutes - verifier checks the token of the requests, a user reaches only its own watchlist and notifications
+func RegisterRoutes(router *httprouter.Router, s Service, verifier authenticating.Verifier) {
+	options := []kithttp.ServerOption{
+		kithttp.ServerBefore(authenticating.VerifyActor(verifier)),
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	watchHandler := kithttp.NewServer(
+		MakeEndpointWatch(s),
+		decodeWatchRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	unwatchHandler := kithttp.NewServer(
+		MakeEndpointUnwatch(s),
+		decodeWatchRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	watchlistHandler := kithttp.NewServer(
+		MakeEndpointWatchlist(s),
+		decodeWatchlistRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listNotificationsHandler := kithttp.NewServer(
+		MakeEndpointListNotifications(s),
+		decodeListNotificationsRequest,
+		encodeListNotificationsResponse,
+		options...,
+	)
+
+	markReadHandler := kithttp.NewServer(
+		MakeEndpointMarkRead(s),
+		decodeMarkReadRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	router.Handler(http.MethodGet, "/users/:userUuid/watchlist", watchlistHandler)
+	router.Handler(http.MethodPut, "/users/:userUuid/watchlist/:auctionUuid", watchHandler)
+	router.Handler(http.MethodDelete, "/users/:userUuid/watchlist/:auctionUuid", unwatchHandler)
+	router.Handler(http.MethodGet, "/users/:userUuid/notifications", listNotificationsHandler)
+	router.Handler(http.MethodPost, "/users/:userUuid/notifications/read", markReadHandler)
+}
+
+func encodeError(ctx context.Context, err error, writer http.ResponseWriter) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrInvalidInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, authenticating.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, model.ErrForbidden):
+		status = http.StatusForbidden
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	json.NewEncoder(writer).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func decodeWatchRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	userUuid, err := ownUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return WatchRequest{
+		watch: model.WatchInput{
+			UserUuid:    userUuid,
+			AuctionUuid: httprouter.ParamsFromContext(ctx).ByName("auctionUuid"),
+		},
+	}, nil
+}
+
+func decodeWatchlistRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	userUuid, err := ownUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return WatchlistRequest{
+		UserUuid: userUuid,
+	}, nil
+}
+
+func decodeListNotificationsRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.ListInput
+	queryParams := r.URL.Query()
+
+	if input.UserUuid, err = ownUser(ctx); err != nil {
+		return nil, err
+	}
+	input.Unread, _ = strconv.ParseBool(queryParams.Get("unread"))
+	input.Page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	input.Page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	return ListNotificationsRequest{
+		input: input,
+	}, nil
+}
+
+func encodeListNotificationsResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
+	res, ok := response.(ListNotificationsResponse)
+
+	if !ok {
+		return errors.New("encodeListNotificationsResponse failed to parse response")
+	}
+
+	formatted := map[string]interface{}{
+		"notifications": res.list.Notifications,
+		"total":         res.list.Total,
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(writer).Encode(formatted)
+}
+
+func decodeMarkReadRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.ReadInput
+
+	if r.ContentLength != 0 {
+		if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.UserUuid, err = ownUser(ctx); err != nil {
+		return nil, err
+	}
+
+	return MarkReadRequest{
+		read: input,
+	}, nil
+}
+
+// ownUser - the user of the request, which must be the user of the route
+func ownUser(ctx context.Context) (string, error) {
+	userUuid, err := authenticating.Actor(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if userUuid != httprouter.ParamsFromContext(ctx).ByName("userUuid") {
+		return "", model.ErrForbidden
+	}
+
+	return userUuid, nil
+}
@@ -62,3 +62,39 @@ func (s *Service) Get(key string) (interface{}, error) {
 
 	return result, nil
 }
+
+// Publish - publishes the message to every subscriber of the channel on all instances
+func (s *Service) Publish(channel string, message []byte) error {
+
+	if err := s.client.Publish(ctx, channel, message).Err(); err != nil {
+		s.logger.Error(fmt.Sprintf("failed publishing to redis channel %s %v", channel, err))
+		return err
+	}
+
+	return nil
+}
+
+// Subscribe - hands every message of the channel to handle until stop is closed
+func (s *Service) Subscribe(channel string, stop chan struct{}, handle func(message []byte)) error {
+	sub := s.client.Subscribe(ctx, channel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		s.logger.Error(fmt.Sprintf("failed subscribing to redis channel %s %v", channel, err))
+		return err
+	}
+
+	messages := sub.Channel()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			handle([]byte(message.Payload))
+		}
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"fmt"
 	"os"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/julienschmidt/httprouter"
@@ -10,17 +11,28 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/ireuven89/hello-world/backend/auction"
+	auctionrepo "github.com/ireuven89/hello-world/backend/auction/repository"
 	"github.com/ireuven89/hello-world/backend/authenticating"
 	authrepo "github.com/ireuven89/hello-world/backend/authenticating/repository"
 	"github.com/ireuven89/hello-world/backend/aws"
+	"github.com/ireuven89/hello-world/backend/bider"
 	"github.com/ireuven89/hello-world/backend/db"
 	"github.com/ireuven89/hello-world/backend/elastic"
 	"github.com/ireuven89/hello-world/backend/environment"
 	"github.com/ireuven89/hello-world/backend/item"
 	itemrepo "github.com/ireuven89/hello-world/backend/item/repository"
+	"github.com/ireuven89/hello-world/backend/messaging"
+	messagerepo "github.com/ireuven89/hello-world/backend/messaging/repository"
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/notifying"
+	notifyrepo "github.com/ireuven89/hello-world/backend/notifying/repository"
 	"github.com/ireuven89/hello-world/backend/publishing"
 	"github.com/ireuven89/hello-world/backend/redis"
 	"github.com/ireuven89/hello-world/backend/routes"
+	"github.com/ireuven89/hello-world/backend/settling"
+	settlerepo "github.com/ireuven89/hello-world/backend/settling/repository"
+	"github.com/ireuven89/hello-world/backend/streaming"
 	"github.com/ireuven89/hello-world/backend/subscribing"
 	"github.com/ireuven89/hello-world/backend/users"
 	userrepo "github.com/ireuven89/hello-world/backend/users/repository"
@@ -30,6 +42,11 @@ import (
 type Server struct {
 	UserService users.Service
 	ItemService item.Service
+	Auctions    auction.Service
+	Notifier    notifying.Service
+	Settlements settling.Service
+	Messages    messaging.Service
+	Bidders     bider.SService
 	Logger      *zap.Logger
 	Echo        *echo.Echo
 	Elastic     elastic.Service
@@ -38,6 +55,7 @@ type Server struct {
 	Sub         subscribing.SService
 	Redis       *redis.Service
 	Auth        authenticating.Service
+	Stop        chan struct{}
 }
 
 func New() (*Server, error) {
@@ -100,6 +118,82 @@ func New() (*Server, error) {
 	itemTransport := item.NewTransport(itemService, itemRouter)
 	go itemTransport.ListenAndServe(itemConfig.ServicePort)
 
+	//publishing
+	publiserr, err := publishing.New(logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	//streaming
+	stop := make(chan struct{})
+	auctionHub := streaming.NewHub(logger)
+	auctionBus := streaming.NewBus(redisClient, auctionHub, logger)
+	go auctionBus.Run(stop)
+	auctionEvents := streaming.NewFanout(publiserr, auctionBus)
+
+	//bidding
+	bidderConfig, err := utils.LoadConfig("bider", os.Getenv("env"))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to load bider config %v", err))
+		return nil, err
+	}
+	biddersDB, biddersMigrationDir, err := bider.MustNewDB()
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to initiate bidders db %v", err))
+		return nil, err
+	}
+
+	biddersMigration := db.New(biddersDB, logger, biddersMigrationDir)
+	if err = biddersMigration.Run(); err != nil {
+		return nil, err
+	}
+	bidderRepo := bider.New(biddersDB, logger)
+	if err = bidderRepo.MoveLegacy(itemsDB); err != nil {
+		return nil, err
+	}
+	bidderService := bider.NewService(bidderRepo, logger)
+	bidderRouter := httprouter.New()
+	bidderTransport := bider.NewTransport(bidderService, bidderRouter)
+	go bidderTransport.ListenAndServe(bidderConfig.ServicePort)
+
+	//auctioning
+	auctionConfig, err := auction.LoadConfig(os.Getenv("env"))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to load auction config %v", err))
+		return nil, err
+	}
+	auctionsDB, auctionsMigrationDir, err := auction.MustNewDB()
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to initiate auctions db %v", err))
+		return nil, err
+	}
+
+	auctionsMigration := db.New(auctionsDB, logger, auctionsMigrationDir)
+	if err = auctionsMigration.Run(); err != nil {
+		return nil, err
+	}
+	auctionRepo := auctionrepo.New(auctionsDB, logger)
+	auctionService := auction.New(auctionRepo, auctionEvents, bidderService, auctionConfig, logger)
+	rates, err := money.NewFileProvider(auctionConfig.Rates.File)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to load exchange rates %v", err))
+		return nil, err
+	}
+	cachedRates := money.NewCachedProvider(rates, redisClient, time.Duration(auctionConfig.Rates.CacheTtlSeconds)*time.Second, logger)
+	proxies, err := auction.ParseProxies(auctionConfig.Fraud.TrustedProxies)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to parse trusted proxies %v", err))
+		return nil, err
+	}
+	auctionRouter := httprouter.New()
+	auctionTransport := auction.NewTransport(auctionService, auctionRouter, cachedRates, authService, proxies)
+	go auctionTransport.ListenAndServe(auctionConfig.ServicePort)
+	auctionScheduler := auction.NewScheduler(auctionRepo, auctionEvents, auctionConfig.Scheduler, logger)
+	go auctionScheduler.Run(stop)
+	fraudScanner := auction.NewFraudScanner(auctionRepo, bidderService, auctionConfig.Fraud, logger)
+	go fraudScanner.Run(stop)
+
 	//userring
 	usersDB, userMigrationDir, err := users.MustNewDB()
 	if err != nil {
@@ -118,12 +212,84 @@ func New() (*Server, error) {
 	transport := users.NewTransport(usersService, userRouter)
 	go transport.ListenAndServe("7000")
 
-	//publishing
-	publiserr, err := publishing.New(logger)
+	//notifying
+	notifyConfig, err := utils.LoadConfig("notifying", os.Getenv("env"))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to load notifying config %v", err))
+		return nil, err
+	}
+	notificationsDB, notificationsMigrationDir, err := notifying.MustNewDB()
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to initiate notifications db %v", err))
+		return nil, err
+	}
+
+	notificationsMigration := db.New(notificationsDB, logger, notificationsMigrationDir)
+	if err = notificationsMigration.Run(); err != nil {
+		return nil, err
+	}
+	notifyRepo := notifyrepo.New(notificationsDB, logger)
+	notifyService := notifying.New(notifyRepo, bidderService, logger)
+	notifyRouter := httprouter.New()
+	notifyTransport := notifying.NewTransport(notifyService, notifyRouter, authService)
+	go notifyTransport.ListenAndServe(notifyConfig.ServicePort)
+
+	//settling
+	settleConfig, err := settling.LoadConfig(os.Getenv("env"))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to load settling config %v", err))
+		return nil, err
+	}
+	settlementsDB, settlementsMigrationDir, err := settling.MustNewDB()
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to initiate settlements db %v", err))
+		return nil, err
+	}
 
+	settlementsMigration := db.New(settlementsDB, logger, settlementsMigrationDir)
+	if err = settlementsMigration.Run(); err != nil {
+		return nil, err
+	}
+	paymentProvider, err := settling.NewPaymentProvider(settleConfig)
 	if err != nil {
 		return nil, err
 	}
+	fees, err := settling.NewFeeCalculator(settleConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to load fee rules %v", err))
+		return nil, err
+	}
+	settleRepo := settlerepo.New(settlementsDB, logger)
+	settleService := settling.New(settleRepo, paymentProvider, fees, awsClient, bidderService, settleConfig.Disputes, logger)
+	settleRouter := httprouter.New()
+	settleTransport := settling.NewTransport(settleService, settleRouter, authService)
+	go settleTransport.ListenAndServe(settleConfig.ServicePort)
+	disputeScheduler := settling.NewDisputeScheduler(settleService, settleConfig.Disputes, logger)
+	go disputeScheduler.Run(stop)
+
+	//messaging
+	messageConfig, err := messaging.LoadConfig(os.Getenv("env"))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to load messaging config %v", err))
+		return nil, err
+	}
+	messagesDB, messagesMigrationDir, err := messaging.MustNewDB()
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to initiate messages db %v", err))
+		return nil, err
+	}
+
+	messagesMigration := db.New(messagesDB, logger, messagesMigrationDir)
+	if err = messagesMigration.Run(); err != nil {
+		return nil, err
+	}
+	messageRepo := messagerepo.New(messagesDB, logger)
+	sellers := messaging.NewSellers(auctionService, itemService)
+	moderators := []messaging.Moderator{messaging.NewWordFilter(messageConfig.Moderation)}
+	messageService := messaging.New(messageRepo, sellers, moderators, logger)
+	messageRouter := httprouter.New()
+	messageTransport := messaging.NewTransport(messageService, messageRouter, authService)
+	go messageTransport.ListenAndServe(messageConfig.ServicePort)
 
 	//subscribing
 	subscriberr, err := subscribing.New(logger)
@@ -131,6 +297,11 @@ func New() (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	subscriberr.AddHandler(notifyService.HandleEvent)
+	subscriberr.AddHandler(settleService.HandleEvent)
+	subscriberr.AddHandler(usersService.HandleEvent)
+	subscriberr.AddHandler(bidderService.HandleEvent)
+	go subscriberr.Subscribe(stop)
 
 	echoServer := echo.New()
 	if err != nil {
@@ -143,8 +314,9 @@ func New() (*Server, error) {
 	}
 
 	routes.AssignRoutes(echoServer)
+	streaming.RegisterRoutes(echoServer, auctionHub)
 
 	logger.Info("Server has been initialized")
 
-	return &Server{Auth: authService, Redis: redisClient, ItemService: itemService, UserService: usersService, Logger: logger, Echo: echoServer, AWSClient: awsClient, Elastic: es, Sub: subscriberr, Pub: publiserr}, nil
+	return &Server{Auth: authService, Redis: redisClient, ItemService: itemService, Auctions: auctionService, Notifier: notifyService, Settlements: settleService, Messages: messageService, Bidders: bidderService, UserService: usersService, Logger: logger, Echo: echoServer, AWSClient: awsClient, Elastic: es, Sub: subscriberr, Pub: publiserr, Stop: stop}, nil
 }
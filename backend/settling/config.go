@@ -0,0 +1,94 @@
+package settling
+
+import (
+	"fmt"
+
+	"github.com/ireuven89/hello-world/backend/settling/model"
+	"github.com/ireuven89/hello-world/backend/utils"
+)
+
+// Config - FeeBasisPoints is the platform fee of the sales no fee rule matches, 500 is 5%.
+// PaymentProvider names the payment gateway, only the fake provider exists for now
+type Config struct {
+	utils.Config
+	FeeBasisPoints  int64          `json:"feeBasisPoints"`
+	Fees            FeesConfig     `json:"fees"`
+	PaymentProvider string         `json:"paymentProvider"`
+	Disputes        DisputesConfig `json:"disputes"`
+}
+
+// DisputesConfig - a paid invoice is disputed within OpenWindowSeconds of its payment. the buyer attaches evidence
+// for BuyerWindowSeconds, then the seller has SellerWindowSeconds to answer. the scheduler moves the disputes past
+// their deadline on every IntervalSeconds, BatchSize at a time. evidence files up to MaxEvidenceBytes are kept in
+// EvidenceBucket and AdminUuids are the users deciding the escalated disputes
+type DisputesConfig struct {
+	OpenWindowSeconds   int64    `json:"openWindowSeconds"`
+	BuyerWindowSeconds  int64    `json:"buyerWindowSeconds"`
+	SellerWindowSeconds int64    `json:"sellerWindowSeconds"`
+	IntervalSeconds     int64    `json:"intervalSeconds"`
+	BatchSize           int64    `json:"batchSize"`
+	EvidenceBucket      string   `json:"evidenceBucket"`
+	MaxEvidenceBytes    int64    `json:"maxEvidenceBytes"`
+	AdminUuids          []string `json:"adminUuids"`
+}
+
+// WithDefaults - fills the unset durations and limits with their defaults
+func (c DisputesConfig) WithDefaults() DisputesConfig {
+	if c.OpenWindowSeconds <= 0 {
+		c.OpenWindowSeconds = 30 * 24 * 3600
+	}
+	if c.BuyerWindowSeconds <= 0 {
+		c.BuyerWindowSeconds = 3 * 24 * 3600
+	}
+	if c.SellerWindowSeconds <= 0 {
+		c.SellerWindowSeconds = 7 * 24 * 3600
+	}
+	if c.IntervalSeconds <= 0 {
+		c.IntervalSeconds = 60
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.MaxEvidenceBytes <= 0 {
+		c.MaxEvidenceBytes = 10 << 20
+	}
+
+	return c
+}
+
+// FeesConfig - the fee rules in matching order and the fee tier of the sellers, keyed by seller uuid.
+// the sellers who are not listed are in DefaultTier
+type FeesConfig struct {
+	DefaultTier string            `json:"defaultTier"`
+	SellerTiers map[string]string `json:"sellerTiers"`
+	Rules       []model.FeeRule   `json:"rules"`
+}
+
+const maxBasisPoints = 10000
+
+// LoadConfig - loads the settling service config of the given environment
+func LoadConfig(env string) (Config, error) {
+	var config Config
+
+	if err := utils.LoadConfigInto("settling", env, &config); err != nil {
+		return Config{}, err
+	}
+
+	if config.FeeBasisPoints < 0 || config.FeeBasisPoints > maxBasisPoints {
+		return Config{}, fmt.Errorf("feeBasisPoints must be between 0 and %d", maxBasisPoints)
+	}
+
+	config.Disputes = config.Disputes.WithDefaults()
+
+	return config, nil
+}
+
+// NewPaymentProvider - returns the payment provider named by the config
+func NewPaymentProvider(config Config) (PaymentProvider, error) {
+	switch config.PaymentProvider {
+	case "", "fake":
+		return NewFakeProvider(), nil
+	}
+
+	return nil, fmt.Errorf("unknown payment provider %q", config.PaymentProvider)
+}
@@ -0,0 +1,47 @@
+package settling
+
+import (
+	"database/sql"
+	"path/filepath"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ido50/sqlz"
+
+	"github.com/ireuven89/hello-world/backend/environment"
+)
+
+// MustNewDB - returns the db connection, the migrations directory of the db, and an error if anything failed
+func MustNewDB() (*sqlz.DB, string, error) {
+	cfg := mysql.Config{
+		User:      environment.Variables.SettlementsDbUser,
+		Passwd:    environment.Variables.SettlementsDbPassword,
+		Addr:      environment.Variables.SettlementsDbHost,
+		DBName:    "settlements",
+		Net:       "tcp",
+		ParseTime: true,
+	}
+	settlementsDB, err := sql.Open("mysql", cfg.FormatDSN())
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	//ping check
+	if err = settlementsDB.Ping(); err != nil {
+		return nil, "", err
+	}
+
+	//create lock table if not exists
+	if _, err = settlementsDB.Exec("create table if not exists lock_table(lock_row int)"); err != nil {
+		return nil, "", err
+	}
+
+	//set migration dir
+	migrationDir, err := filepath.Abs("./db/migrations/settlements")
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return sqlz.New(settlementsDB, "mysql"), migrationDir, nil
+}
@@ -0,0 +1,348 @@
+package settling
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+// OpenDispute - the buyer disputes a paid invoice within the dispute window, the buyer then has the evidence
+// window to attach evidence before the dispute goes to the seller
+func (s *ServiceSettlement) OpenDispute(input model.DisputeInput) (model.Dispute, error) {
+	input.Reason = strings.TrimSpace(input.Reason)
+	if err := validateDispute(input); err != nil {
+		return model.Dispute{}, err
+	}
+
+	invoice, err := s.invoice(input.InvoiceUuid)
+	if err != nil {
+		return model.Dispute{}, err
+	}
+
+	if invoice.BuyerUuid != input.BuyerUuid {
+		return model.Dispute{}, model.ErrForbidden
+	}
+
+	now := time.Now()
+	if invoice.Status != model.Paid || invoice.PaidAt == nil {
+		return model.Dispute{}, fmt.Errorf("%w: only paid invoices are disputed", model.ErrNotDisputable)
+	}
+
+	if now.After(invoice.PaidAt.Add(seconds(s.disputes.OpenWindowSeconds))) {
+		return model.Dispute{}, fmt.Errorf("%w: the dispute window ended", model.ErrNotDisputable)
+	}
+
+	deadline := now.Add(seconds(s.disputes.BuyerWindowSeconds))
+	dispute := model.Dispute{
+		Uuid:        uuid.New().String(),
+		InvoiceUuid: invoice.Uuid,
+		AuctionUuid: invoice.AuctionUuid,
+		BuyerUuid:   invoice.BuyerUuid,
+		SellerUuid:  invoice.SellerUuid,
+		Reason:      input.Reason,
+		Status:      model.DisputeOpened,
+		Deadline:    &deadline,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err = s.repo.CreateDispute(dispute); err != nil {
+		if !errors.Is(err, model.ErrDisputeExists) {
+			s.logger.Error("ServiceSettlement.OpenDispute failed creating dispute", zap.Any("input", input), zap.Error(err))
+		}
+		return model.Dispute{}, err
+	}
+
+	return dispute, nil
+}
+
+// GetDispute - the dispute with its evidence as seen by a party of the dispute or an admin
+func (s *ServiceSettlement) GetDispute(uuid string, viewerUuid string) (model.Dispute, error) {
+	result, err := s.repo.Dispute(uuid)
+	if err != nil {
+		s.logger.Error("ServiceSettlement.GetDispute failed getting dispute", zap.String("uuid", uuid), zap.Error(err))
+		return model.Dispute{}, err
+	}
+
+	if _, ok := s.partyOf(result, viewerUuid, ""); !ok {
+		return model.Dispute{}, model.ErrForbidden
+	}
+
+	if result.Evidence, err = s.repo.Evidence(uuid); err != nil {
+		s.logger.Error("ServiceSettlement.GetDispute failed listing evidence", zap.String("uuid", uuid), zap.Error(err))
+		return model.Dispute{}, err
+	}
+
+	return result, nil
+}
+
+func (s *ServiceSettlement) ListDisputes(input model.DisputeListInput) (model.DisputeList, error) {
+	if input.UserUuid == "" {
+		return model.DisputeList{}, fmt.Errorf("%w: userUuid is required", model.ErrInvalidInput)
+	}
+
+	result, err := s.repo.ListDisputes(input)
+	if err != nil {
+		s.logger.Error("ServiceSettlement.ListDisputes failed listing disputes", zap.Any("input", input), zap.Error(err))
+		return model.DisputeList{}, err
+	}
+
+	return result, nil
+}
+
+// ActOnDispute - a party of the dispute, or an admin, moves it on as the dispute state machine allows
+func (s *ServiceSettlement) ActOnDispute(input model.DisputeActionInput) (model.Dispute, error) {
+	dispute, err := s.repo.Dispute(input.DisputeUuid)
+	if err != nil {
+		s.logger.Error("ServiceSettlement.ActOnDispute failed getting dispute", zap.String("uuid", input.DisputeUuid), zap.Error(err))
+		return model.Dispute{}, err
+	}
+
+	party, ok := s.partyOf(dispute, input.ActorUuid, model.Transitions[input.Action].Party)
+	if !ok {
+		return model.Dispute{}, model.ErrForbidden
+	}
+
+	to, err := dispute.Next(input.Action, party)
+	if err != nil {
+		return model.Dispute{}, err
+	}
+
+	return s.moveDispute(dispute, to, time.Now())
+}
+
+// AddEvidence - a party of an unresolved dispute, or an admin, attaches a file to it. the file is stored first
+// and removed again when the evidence could not be saved
+func (s *ServiceSettlement) AddEvidence(input model.EvidenceInput) (model.Evidence, error) {
+	if err := s.validateEvidence(input); err != nil {
+		return model.Evidence{}, err
+	}
+
+	dispute, err := s.repo.Dispute(input.DisputeUuid)
+	if err != nil {
+		s.logger.Error("ServiceSettlement.AddEvidence failed getting dispute", zap.String("uuid", input.DisputeUuid), zap.Error(err))
+		return model.Evidence{}, err
+	}
+
+	if _, ok := s.partyOf(dispute, input.UploaderUuid, ""); !ok {
+		return model.Evidence{}, model.ErrForbidden
+	}
+
+	if dispute.Status.Resolved() {
+		return model.Evidence{}, model.ErrDisputeClosed
+	}
+
+	evidence := model.Evidence{
+		Uuid:         uuid.New().String(),
+		DisputeUuid:  dispute.Uuid,
+		UploaderUuid: input.UploaderUuid,
+		Name:         input.Name,
+		ContentType:  input.ContentType,
+		Size:         input.Size,
+		CreatedAt:    time.Now(),
+	}
+	evidence.Key = fmt.Sprintf("disputes/%s/%s", dispute.Uuid, evidence.Uuid)
+
+	if err = s.storage.PutObject(evidence.Key, s.disputes.EvidenceBucket, input.File); err != nil {
+		s.logger.Error("ServiceSettlement.AddEvidence failed storing file", zap.String("key", evidence.Key), zap.Error(err))
+		return model.Evidence{}, err
+	}
+
+	if err = s.repo.CreateEvidence(evidence); err != nil {
+		if deleteErr := s.storage.DeleteObject(evidence.Key, s.disputes.EvidenceBucket); deleteErr != nil {
+			s.logger.Warn("ServiceSettlement.AddEvidence failed removing stored file", zap.String("key", evidence.Key), zap.Error(deleteErr))
+		}
+		return model.Evidence{}, err
+	}
+
+	return evidence, nil
+}
+
+// ExpireDisputes - moves on a batch of the disputes whose deadline passed. a dispute moved by one of its parties
+// in the meantime is left as it is
+func (s *ServiceSettlement) ExpireDisputes(now time.Time) error {
+	due, err := s.repo.DueDisputes(now, s.disputes.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, dispute := range due {
+		to, ok := dispute.Status.Expired()
+		if !ok {
+			continue
+		}
+
+		if _, err = s.moveDispute(dispute, to, now); err != nil && !errors.Is(err, model.ErrDisputeConflict) {
+			s.logger.Error("ServiceSettlement.ExpireDisputes failed moving dispute", zap.String("dispute", dispute.Uuid), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// moveDispute - saves the dispute in its new status with the deadline of that status, a refund reverses the sale
+// and the payment of the invoice in the ledger
+func (s *ServiceSettlement) moveDispute(dispute model.Dispute, to model.DisputeStatus, now time.Time) (model.Dispute, error) {
+	from := dispute.Status
+	dispute.Status, dispute.UpdatedAt, dispute.Deadline = to, now, nil
+
+	switch {
+	case to == model.AwaitingSeller:
+		deadline := now.Add(seconds(s.disputes.SellerWindowSeconds))
+		dispute.Deadline = &deadline
+	case to.Resolved():
+		dispute.ResolvedAt = &now
+	}
+
+	var refund []model.Entry
+	if to == model.ResolvedRefund {
+		invoice, err := s.invoice(dispute.InvoiceUuid)
+		if err != nil {
+			return model.Dispute{}, err
+		}
+
+		refund = refundEntries(invoice, s.provider.Name(), dispute.Uuid)
+		if err = model.Balanced(refund); err != nil {
+			return model.Dispute{}, err
+		}
+	}
+
+	if err := s.repo.TransitionDispute(dispute, from, refund); err != nil {
+		return model.Dispute{}, err
+	}
+
+	return dispute, nil
+}
+
+// partyOf - the part the user plays in the dispute, an admin who is also a party acts as an admin only for the
+// actions reserved to admins
+func (s *ServiceSettlement) partyOf(dispute model.Dispute, userUuid string, wanted model.Party) (model.Party, bool) {
+	if userUuid == "" {
+		return "", false
+	}
+
+	if wanted == model.PartyAdmin && s.isAdmin(userUuid) {
+		return model.PartyAdmin, true
+	}
+
+	switch userUuid {
+	case dispute.BuyerUuid:
+		return model.PartyBuyer, true
+	case dispute.SellerUuid:
+		return model.PartySeller, true
+	}
+
+	if s.isAdmin(userUuid) {
+		return model.PartyAdmin, true
+	}
+
+	return "", false
+}
+
+func (s *ServiceSettlement) isAdmin(userUuid string) bool {
+	for _, admin := range s.disputes.AdminUuids {
+		if admin == userUuid {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *ServiceSettlement) validateEvidence(input model.EvidenceInput) error {
+	if input.DisputeUuid == "" || input.UploaderUuid == "" || input.File == nil {
+		return fmt.Errorf("%w: dispute, uploaderUuid and a file are required", model.ErrInvalidInput)
+	}
+
+	if input.Size <= 0 || input.Size > s.disputes.MaxEvidenceBytes {
+		return fmt.Errorf("%w: the file must be between 1 and %d bytes", model.ErrInvalidInput, s.disputes.MaxEvidenceBytes)
+	}
+
+	return nil
+}
+
+func validateDispute(input model.DisputeInput) error {
+	if input.InvoiceUuid == "" || input.BuyerUuid == "" {
+		return fmt.Errorf("%w: invoice and buyerUuid are required", model.ErrInvalidInput)
+	}
+
+	if input.Reason == "" || utf8.RuneCountInString(input.Reason) > model.MaxReasonLength {
+		return fmt.Errorf("%w: the reason must have between 1 and %d characters", model.ErrInvalidInput, model.MaxReasonLength)
+	}
+
+	return nil
+}
+
+// refundEntries - the ledger transaction of a refund: the seller gives back the proceeds, the platform gives back
+// the fee and the buyer is paid back through the payment provider
+func refundEntries(invoice model.Invoice, provider string, disputeUuid string) []model.Entry {
+	transaction := uuid.New().String()
+	entry := func(account string, direction model.Direction, amount int64) model.Entry {
+		return model.Entry{
+			TransactionUuid: transaction,
+			InvoiceUuid:     invoice.Uuid,
+			Account:         account,
+			Direction:       direction,
+			Amount:          amount,
+			Currency:        invoice.Currency,
+			Memo:            "refund of dispute " + disputeUuid,
+		}
+	}
+
+	entries := []model.Entry{
+		entry(model.SellerAccount(invoice.SellerUuid), model.Debit, invoice.SellerProceeds()),
+	}
+
+	if invoice.Fee > 0 {
+		entries = append(entries, entry(model.PlatformFees, model.Debit, invoice.Fee))
+	}
+
+	return append(entries, entry(model.PaymentsAccount(provider), model.Credit, invoice.Amount))
+}
+
+func seconds(value int64) time.Duration {
+	return time.Duration(value) * time.Second
+}
+
+// DisputeScheduler - moves on the disputes whose deadline passed
+type DisputeScheduler struct {
+	service  Service
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewDisputeScheduler(service Service, config DisputesConfig, logger *zap.Logger) *DisputeScheduler {
+
+	return &DisputeScheduler{
+		service:  service,
+		interval: seconds(config.WithDefaults().IntervalSeconds),
+		logger:   logger,
+	}
+}
+
+// Run - enforces the dispute deadlines on every tick until stop is closed
+func (s *DisputeScheduler) Run(stop chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("dispute scheduler started", zap.Duration("interval", s.interval))
+
+	for {
+		select {
+		case <-stop:
+			s.logger.Info("dispute scheduler stopped")
+			return
+		case now := <-ticker.C:
+			if err := s.service.ExpireDisputes(now); err != nil {
+				s.logger.Error("DisputeScheduler.Run failed expiring disputes", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package settling
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+type MockStorage struct {
+	mock mock.Mock
+}
+
+func (m *MockStorage) PutObject(key, bucket string, file *os.File) error {
+	args := m.mock.Called(key, bucket, file)
+
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetObject(key, bucket string) (interface{}, error) {
+	args := m.mock.Called(key, bucket)
+
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockStorage) DeleteObject(key, bucket string) error {
+	args := m.mock.Called(key, bucket)
+
+	return args.Error(0)
+}
+
+var disputesConfig = DisputesConfig{
+	OpenWindowSeconds:   3600 * 24,
+	BuyerWindowSeconds:  3600,
+	SellerWindowSeconds: 3600 * 2,
+	EvidenceBucket:      "evidence",
+	MaxEvidenceBytes:    1024,
+	AdminUuids:          []string{"admin-uuid"},
+}
+
+func paidInvoice(paidAt time.Time) model.Invoice {
+	return model.Invoice{
+		Uuid:        "invoice-uuid",
+		AuctionUuid: "auction-uuid",
+		BuyerUuid:   "buyer-uuid",
+		SellerUuid:  "seller-uuid",
+		Amount:      10000,
+		Fee:         500,
+		Currency:    money.USD,
+		Status:      model.Paid,
+		PaidAt:      &paidAt,
+	}
+}
+
+func dispute(status model.DisputeStatus) model.Dispute {
+	return model.Dispute{
+		Uuid:        "dispute-uuid",
+		InvoiceUuid: "invoice-uuid",
+		BuyerUuid:   "buyer-uuid",
+		SellerUuid:  "seller-uuid",
+		Status:      status,
+	}
+}
+
+func TestServiceSettlement_OpenDispute(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Invoice", "invoice-uuid").Return(paidInvoice(time.Now().Add(-time.Hour)), nil)
+	repo.mock.On("CreateDispute", mock.Anything).Return(nil)
+	service := New(repo, NewFakeProvider(), flatFees(0), &MockStorage{}, nil, disputesConfig, zap.NewNop())
+
+	result, err := service.OpenDispute(model.DisputeInput{InvoiceUuid: "invoice-uuid", BuyerUuid: "buyer-uuid", Reason: " not as described "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.DisputeOpened, result.Status)
+	assert.Equal(t, "seller-uuid", result.SellerUuid)
+	assert.Equal(t, "not as described", result.Reason)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *result.Deadline, time.Minute)
+}
+
+func TestServiceSettlement_OpenDisputeRejected(t *testing.T) {
+	pending := paidInvoice(time.Now())
+	pending.Status, pending.PaidAt = model.Pending, nil
+
+	tests := []struct {
+		name    string
+		invoice model.Invoice
+		input   model.DisputeInput
+		wantErr error
+	}{
+		{"no reason", paidInvoice(time.Now()), model.DisputeInput{BuyerUuid: "buyer-uuid"}, model.ErrInvalidInput},
+		{"not the buyer", paidInvoice(time.Now()), model.DisputeInput{BuyerUuid: "seller-uuid", Reason: "broken"}, model.ErrForbidden},
+		{"not paid", pending, model.DisputeInput{BuyerUuid: "buyer-uuid", Reason: "broken"}, model.ErrNotDisputable},
+		{"window ended", paidInvoice(time.Now().Add(-25 * time.Hour)), model.DisputeInput{BuyerUuid: "buyer-uuid", Reason: "broken"}, model.ErrNotDisputable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Invoice", "invoice-uuid").Return(test.invoice, nil)
+			service := New(repo, NewFakeProvider(), flatFees(0), &MockStorage{}, nil, disputesConfig, zap.NewNop())
+			test.input.InvoiceUuid = "invoice-uuid"
+
+			_, err := service.OpenDispute(test.input)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			repo.mock.AssertNotCalled(t, "CreateDispute", mock.Anything)
+		})
+	}
+}
+
+func TestServiceSettlement_GetDispute(t *testing.T) {
+	tests := []struct {
+		name    string
+		viewer  string
+		wantErr error
+	}{
+		{"buyer", "buyer-uuid", nil},
+		{"seller", "seller-uuid", nil},
+		{"admin", "admin-uuid", nil},
+		{"stranger", "bidder-uuid", model.ErrForbidden},
+		{"no viewer", "", model.ErrForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Dispute", "dispute-uuid").Return(dispute(model.DisputeOpened), nil)
+			repo.mock.On("Evidence", "dispute-uuid").Return([]model.Evidence{}, nil)
+			service := New(repo, NewFakeProvider(), flatFees(0), &MockStorage{}, nil, disputesConfig, zap.NewNop())
+
+			_, err := service.GetDispute("dispute-uuid", test.viewer)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			if test.wantErr != nil {
+				repo.mock.AssertNotCalled(t, "Evidence", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestServiceSettlement_ActOnDispute(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     model.DisputeStatus
+		actor      string
+		action     model.DisputeAction
+		wantStatus model.DisputeStatus
+		wantErr    error
+	}{
+		{"buyer submits", model.DisputeOpened, "buyer-uuid", model.SubmitDispute, model.AwaitingSeller, nil},
+		{"seller contests", model.AwaitingSeller, "seller-uuid", model.ContestDispute, model.DisputeEscalated, nil},
+		{"buyer withdraws", model.DisputeEscalated, "buyer-uuid", model.WithdrawDispute, model.ResolvedNoRefund, nil},
+		{"admin rejects", model.DisputeEscalated, "admin-uuid", model.RejectDispute, model.ResolvedNoRefund, nil},
+		{"seller submits", model.DisputeOpened, "seller-uuid", model.SubmitDispute, "", model.ErrForbidden},
+		{"seller refunds", model.DisputeEscalated, "seller-uuid", model.RefundDispute, "", model.ErrForbidden},
+		{"stranger withdraws", model.DisputeOpened, "bidder-uuid", model.WithdrawDispute, "", model.ErrForbidden},
+		{"seller contests too early", model.DisputeOpened, "seller-uuid", model.ContestDispute, "", model.ErrInvalidInput},
+		{"buyer withdraws resolved", model.ResolvedRefund, "buyer-uuid", model.WithdrawDispute, "", model.ErrDisputeClosed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Dispute", "dispute-uuid").Return(dispute(test.status), nil)
+			repo.mock.On("TransitionDispute", mock.Anything, test.status, []model.Entry(nil)).Return(nil)
+			service := New(repo, NewFakeProvider(), flatFees(0), &MockStorage{}, nil, disputesConfig, zap.NewNop())
+
+			result, err := service.ActOnDispute(model.DisputeActionInput{DisputeUuid: "dispute-uuid", ActorUuid: test.actor, Action: test.action})
+
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Equal(t, test.wantStatus, result.Status)
+			if test.wantErr != nil {
+				repo.mock.AssertNotCalled(t, "TransitionDispute", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestServiceSettlement_AcceptDisputeRefunds(t *testing.T) {
+	repo := &MockRepository{}
+	repo.mock.On("Dispute", "dispute-uuid").Return(dispute(model.AwaitingSeller), nil)
+	repo.mock.On("Invoice", "invoice-uuid").Return(paidInvoice(time.Now()), nil)
+	repo.mock.On("TransitionDispute", mock.Anything, model.AwaitingSeller, mock.Anything).Return(nil)
+	service := New(repo, NewFakeProvider(), flatFees(0), &MockStorage{}, nil, disputesConfig, zap.NewNop())
+
+	result, err := service.ActOnDispute(model.DisputeActionInput{DisputeUuid: "dispute-uuid", ActorUuid: "seller-uuid", Action: model.AcceptDispute})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ResolvedRefund, result.Status)
+	assert.NotNil(t, result.ResolvedAt)
+	assert.Nil(t, result.Deadline)
+
+	refund := repo.mock.Calls[2].Arguments.Get(2).([]model.Entry)
+	assert.NoError(t, model.Balanced(refund))
+	assert.Equal(t, model.SellerAccount("seller-uuid"), refund[0].Account)
+	assert.Equal(t, int64(9500), refund[0].Amount)
+	assert.Equal(t, model.PlatformFees, refund[1].Account)
+	assert.Equal(t, int64(500), refund[1].Amount)
+	assert.Equal(t, model.PaymentsAccount("fake"), refund[2].Account)
+	assert.Equal(t, model.Credit, refund[2].Direction)
+}
+
+func TestServiceSettlement_ExpireDisputes(t *testing.T) {
+	now := time.Now()
+	repo := &MockRepository{}
+	repo.mock.On("DueDisputes", now, int64(100)).Return([]model.Dispute{dispute(model.DisputeOpened), dispute(model.AwaitingSeller)}, nil)
+	repo.mock.On("Invoice", "invoice-uuid").Return(paidInvoice(now), nil)
+	repo.mock.On("TransitionDispute", mock.Anything, model.DisputeOpened, []model.Entry(nil)).Return(nil)
+	repo.mock.On("TransitionDispute", mock.Anything, model.AwaitingSeller, mock.Anything).Return(model.ErrDisputeConflict)
+	service := New(repo, NewFakeProvider(), flatFees(0), &MockStorage{}, nil, disputesConfig, zap.NewNop())
+
+	assert.NoError(t, service.ExpireDisputes(now))
+
+	submitted := repo.mock.Calls[1].Arguments.Get(0).(model.Dispute)
+	assert.Equal(t, model.AwaitingSeller, submitted.Status)
+	assert.Equal(t, now.Add(2*time.Hour), *submitted.Deadline)
+
+	refunded := repo.mock.Calls[3].Arguments.Get(0).(model.Dispute)
+	assert.Equal(t, model.ResolvedRefund, refunded.Status)
+}
+
+func TestServiceSettlement_AddEvidence(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "evidence")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	repo := &MockRepository{}
+	repo.mock.On("Dispute", "dispute-uuid").Return(dispute(model.AwaitingSeller), nil)
+	repo.mock.On("CreateEvidence", mock.Anything).Return(errors.New("db is down"))
+	storage := &MockStorage{}
+	storage.mock.On("PutObject", mock.Anything, "evidence", file).Return(nil)
+	storage.mock.On("DeleteObject", mock.Anything, "evidence").Return(nil)
+	service := New(repo, NewFakeProvider(), flatFees(0), storage, nil, disputesConfig, zap.NewNop())
+
+	_, err = service.AddEvidence(model.EvidenceInput{DisputeUuid: "dispute-uuid", UploaderUuid: "seller-uuid", Name: "receipt.pdf", Size: 512, File: file})
+
+	assert.Error(t, err)
+	key := storage.mock.Calls[0].Arguments.String(0)
+	assert.Contains(t, key, "disputes/dispute-uuid/")
+	storage.mock.AssertCalled(t, "DeleteObject", key, "evidence")
+}
+
+func TestServiceSettlement_AddEvidenceRejected(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "evidence")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	tests := []struct {
+		name    string
+		status  model.DisputeStatus
+		input   model.EvidenceInput
+		wantErr error
+	}{
+		{"too large", model.DisputeOpened, model.EvidenceInput{UploaderUuid: "buyer-uuid", Size: 2048, File: file}, model.ErrInvalidInput},
+		{"no file", model.DisputeOpened, model.EvidenceInput{UploaderUuid: "buyer-uuid", Size: 10}, model.ErrInvalidInput},
+		{"not a party", model.DisputeOpened, model.EvidenceInput{UploaderUuid: "bidder-uuid", Size: 10, File: file}, model.ErrForbidden},
+		{"resolved", model.ResolvedNoRefund, model.EvidenceInput{UploaderUuid: "buyer-uuid", Size: 10, File: file}, model.ErrDisputeClosed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Dispute", "dispute-uuid").Return(dispute(test.status), nil)
+			storage := &MockStorage{}
+			service := New(repo, NewFakeProvider(), flatFees(0), storage, nil, disputesConfig, zap.NewNop())
+			test.input.DisputeUuid = "dispute-uuid"
+
+			_, err := service.AddEvidence(test.input)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			storage.mock.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
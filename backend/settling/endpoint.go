@@ -0,0 +1,239 @@
+package settling
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+type GetInvoiceRequest struct {
+	Uuid       string
+	ViewerUuid string
+}
+
+func MakeEndpointGetInvoice(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetInvoiceRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointGetInvoice failed cast request")
+		}
+
+		result, err := s.GetInvoice(req.Uuid, req.ViewerUuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointGetInvoice: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type AuctionInvoiceRequest struct {
+	AuctionUuid string
+	ViewerUuid  string
+}
+
+func MakeEndpointAuctionInvoice(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(AuctionInvoiceRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointAuctionInvoice failed cast request")
+		}
+
+		result, err := s.AuctionInvoice(req.AuctionUuid, req.ViewerUuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointAuctionInvoice: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ListInvoicesRequest struct {
+	input model.InvoiceListInput
+}
+
+type ListInvoicesResponse struct {
+	list model.InvoiceList
+}
+
+func MakeEndpointListInvoices(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListInvoicesRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListInvoices failed cast request")
+		}
+
+		result, err := s.ListInvoices(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListInvoices: %w", err)
+		}
+
+		return ListInvoicesResponse{
+			list: result,
+		}, nil
+	}
+}
+
+func MakeEndpointLedger(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetInvoiceRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointLedger failed cast request")
+		}
+
+		result, err := s.Ledger(req.Uuid, req.ViewerUuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointLedger: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type PayInvoiceRequest struct {
+	pay model.PayInput
+}
+
+func MakeEndpointPayInvoice(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(PayInvoiceRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointPayInvoice failed cast request")
+		}
+
+		result, err := s.PayInvoice(req.pay)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointPayInvoice: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type PreviewFeeRequest struct {
+	input model.FeeInput
+}
+
+func MakeEndpointPreviewFee(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(PreviewFeeRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointPreviewFee failed cast request")
+		}
+
+		result, err := s.PreviewFee(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointPreviewFee: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type OpenDisputeRequest struct {
+	dispute model.DisputeInput
+}
+
+func MakeEndpointOpenDispute(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(OpenDisputeRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointOpenDispute failed cast request")
+		}
+
+		result, err := s.OpenDispute(req.dispute)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointOpenDispute: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type GetDisputeRequest struct {
+	Uuid       string
+	ViewerUuid string
+}
+
+func MakeEndpointGetDispute(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetDisputeRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointGetDispute failed cast request")
+		}
+
+		result, err := s.GetDispute(req.Uuid, req.ViewerUuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointGetDispute: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type ListDisputesRequest struct {
+	input model.DisputeListInput
+}
+
+func MakeEndpointListDisputes(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(ListDisputesRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointListDisputes failed cast request")
+		}
+
+		result, err := s.ListDisputes(req.input)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointListDisputes: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type DisputeActionRequest struct {
+	action model.DisputeActionInput
+}
+
+func MakeEndpointDisputeAction(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(DisputeActionRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointDisputeAction failed cast request")
+		}
+
+		result, err := s.ActOnDispute(req.action)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointDisputeAction: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+type AddEvidenceRequest struct {
+	evidence model.EvidenceInput
+}
+
+// MakeEndpointAddEvidence - the uploaded file was copied to a temporary file, it is removed once stored
+func MakeEndpointAddEvidence(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(AddEvidenceRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointAddEvidence failed cast request")
+		}
+		defer os.Remove(req.evidence.File.Name())
+		defer req.evidence.File.Close()
+
+		result, err := s.AddEvidence(req.evidence)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointAddEvidence: %w", err)
+		}
+
+		return result, nil
+	}
+}
@@ -0,0 +1,280 @@
+package settling
+
+import (
+	"fmt"
+
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+const (
+	// DefaultFeeRule - the rule name of the fees priced by FeeBasisPoints when no configured rule matches
+	DefaultFeeRule = "default"
+	// DefaultTier - the tier of the sellers who are not given one
+	DefaultTier = "standard"
+)
+
+// FeeCalculator - prices the platform fee of a sale by the configured fee rules
+type FeeCalculator struct {
+	rules       []feeRule
+	basisPoints int64
+	defaultTier string
+	sellerTiers map[string]string
+}
+
+// feeRule - a configured rule with its amounts parsed to minor units, a zero amount is unset
+type feeRule struct {
+	model.FeeRule
+	minPrice, maxPrice int64
+	fixed              int64
+	bands              []feeBand
+	min, max           int64
+}
+
+type feeBand struct {
+	upTo        int64
+	basisPoints int64
+}
+
+// NewFeeCalculator - parses and validates the fee rules of the config
+func NewFeeCalculator(config Config) (*FeeCalculator, error) {
+	calculator := &FeeCalculator{
+		basisPoints: config.FeeBasisPoints,
+		defaultTier: config.Fees.DefaultTier,
+		sellerTiers: config.Fees.SellerTiers,
+	}
+
+	if calculator.defaultTier == "" {
+		calculator.defaultTier = DefaultTier
+	}
+
+	for i, rule := range config.Fees.Rules {
+		parsed, err := parseFeeRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("fee rule %d %q: %w", i, rule.Name, err)
+		}
+		calculator.rules = append(calculator.rules, parsed)
+	}
+
+	return calculator, nil
+}
+
+// Tier - the fee tier of the seller
+func (c *FeeCalculator) Tier(sellerUuid string) string {
+	if tier, ok := c.sellerTiers[sellerUuid]; ok {
+		return tier
+	}
+
+	return c.defaultTier
+}
+
+// Quote - prices the fee of the sale by the first matching rule, or by FeeBasisPoints when none matches.
+// the fee is rounded down to the minor unit and never exceeds the price
+func (c *FeeCalculator) Quote(input model.FeeInput) (model.FeeQuote, error) {
+	if input.Price.Amount <= 0 || !input.Price.Currency.Valid() {
+		return model.FeeQuote{}, fmt.Errorf("%w: a positive price in a known currency is required", model.ErrInvalidInput)
+	}
+
+	quote := model.FeeQuote{
+		Rule:  DefaultFeeRule,
+		Tier:  c.Tier(input.SellerUuid),
+		Price: input.Price,
+	}
+
+	fee := percentage(input.Price.Amount, c.basisPoints)
+	for _, rule := range c.rules {
+		if rule.matches(input, quote.Tier) {
+			quote.Rule = rule.Name
+			fee = rule.fee(input.Price.Amount)
+			break
+		}
+	}
+
+	fee = min(max(fee, 0), input.Price.Amount)
+	quote.Fee = money.Money{Amount: fee, Currency: input.Price.Currency}
+	quote.SellerProceeds = money.Money{Amount: input.Price.Amount - fee, Currency: input.Price.Currency}
+
+	return quote, nil
+}
+
+func (r feeRule) matches(input model.FeeInput, tier string) bool {
+	if r.Category != "" && r.Category != input.Category {
+		return false
+	}
+
+	if r.Tier != "" && r.Tier != tier {
+		return false
+	}
+
+	if r.Currency != "" && r.Currency != input.Price.Currency {
+		return false
+	}
+
+	price := input.Price.Amount
+
+	return price >= r.minPrice && (r.maxPrice == 0 || price < r.maxPrice)
+}
+
+func (r feeRule) fee(price int64) int64 {
+	var fee int64
+
+	switch r.Type {
+	case model.FeePercentage:
+		fee = percentage(price, r.BasisPoints)
+	case model.FeeFixed:
+		fee = r.fixed
+	case model.FeeTiered:
+		fee = tiered(price, r.bands)
+	}
+
+	if r.min > 0 {
+		fee = max(fee, r.min)
+	}
+
+	if r.max > 0 {
+		fee = min(fee, r.max)
+	}
+
+	return fee
+}
+
+func percentage(amount int64, basisPoints int64) int64 {
+	return amount * basisPoints / maxBasisPoints
+}
+
+// tiered - charges every band the part of the price that falls in it, rounded down once on the total
+func tiered(price int64, bands []feeBand) int64 {
+	var total, lower int64
+
+	for _, band := range bands {
+		upper := price
+		if band.upTo > 0 {
+			upper = min(price, band.upTo)
+		}
+
+		if upper > lower {
+			total += (upper - lower) * band.basisPoints
+		}
+
+		lower = band.upTo
+		if band.upTo == 0 || band.upTo >= price {
+			break
+		}
+	}
+
+	return total / maxBasisPoints
+}
+
+func parseFeeRule(rule model.FeeRule) (feeRule, error) {
+	parsed := feeRule{FeeRule: rule}
+
+	if rule.Name == "" {
+		return feeRule{}, fmt.Errorf("a name is required")
+	}
+
+	if rule.Currency != "" && !rule.Currency.Valid() {
+		return feeRule{}, money.ErrUnknownCurrency
+	}
+
+	amounts := []struct {
+		value  string
+		target *int64
+	}{
+		{rule.MinPrice, &parsed.minPrice},
+		{rule.MaxPrice, &parsed.maxPrice},
+		{rule.Fixed, &parsed.fixed},
+		{rule.Min, &parsed.min},
+		{rule.Max, &parsed.max},
+	}
+	for _, amount := range amounts {
+		var err error
+		if *amount.target, err = parseAmount(amount.value, rule.Currency); err != nil {
+			return feeRule{}, err
+		}
+	}
+
+	if parsed.max > 0 && parsed.min > parsed.max {
+		return feeRule{}, fmt.Errorf("min is above max")
+	}
+
+	switch rule.Type {
+	case model.FeePercentage:
+		if err := validBasisPoints(rule.BasisPoints); err != nil {
+			return feeRule{}, err
+		}
+	case model.FeeFixed:
+		if parsed.fixed == 0 {
+			return feeRule{}, fmt.Errorf("a fixed fee is required")
+		}
+	case model.FeeTiered:
+		bands, err := parseBands(rule.Bands, rule.Currency)
+		if err != nil {
+			return feeRule{}, err
+		}
+		parsed.bands = bands
+	default:
+		return feeRule{}, fmt.Errorf("unknown fee type %q", rule.Type)
+	}
+
+	return parsed, nil
+}
+
+// parseBands - the bands must go up and the last one must cover the rest of the price
+func parseBands(bands []model.FeeBand, currency money.Currency) ([]feeBand, error) {
+	if len(bands) == 0 {
+		return nil, fmt.Errorf("a tiered fee needs bands")
+	}
+
+	result := make([]feeBand, 0, len(bands))
+	var previous int64
+	for i, band := range bands {
+		upTo, err := parseAmount(band.UpTo, currency)
+		if err != nil {
+			return nil, err
+		}
+
+		last := i == len(bands)-1
+		if last != (upTo == 0) || (!last && upTo <= previous) {
+			return nil, fmt.Errorf("bands must go up and only the last band is open")
+		}
+
+		if err = validBasisPoints(band.BasisPoints); err != nil {
+			return nil, err
+		}
+
+		result = append(result, feeBand{upTo: upTo, basisPoints: band.BasisPoints})
+		previous = upTo
+	}
+
+	return result, nil
+}
+
+// parseAmount - parses a positive decimal of the rule currency, an empty value is unset
+func parseAmount(value string, currency money.Currency) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	if currency == "" {
+		return 0, fmt.Errorf("amounts need the rule currency")
+	}
+
+	amount, err := money.Parse(value, currency)
+	if err != nil {
+		return 0, err
+	}
+
+	if amount.Amount <= 0 {
+		return 0, fmt.Errorf("%w: %q must be positive", money.ErrInvalidAmount, value)
+	}
+
+	return amount.Amount, nil
+}
+
+func validBasisPoints(basisPoints int64) error {
+	if basisPoints < 0 || basisPoints > maxBasisPoints {
+		return fmt.Errorf("basisPoints must be between 0 and %d", maxBasisPoints)
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package settling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+func testFeesConfig() Config {
+	return Config{
+		FeeBasisPoints: 500,
+		Fees: FeesConfig{
+			SellerTiers: map[string]string{"pro-seller": "pro"},
+			Rules: []model.FeeRule{
+				{Name: "low value", Currency: money.USD, MaxPrice: "10.00", Type: model.FeeFixed, Fixed: "0.50"},
+				{Name: "pro sellers", Tier: "pro", Currency: money.USD, Type: model.FeeTiered, Bands: []model.FeeBand{
+					{UpTo: "1000.00", BasisPoints: 400},
+					{BasisPoints: 200},
+				}},
+				{Name: "electronics", Category: "electronics", Currency: money.USD, Type: model.FeePercentage, BasisPoints: 800, Max: "250.00"},
+				{Name: "art", Category: "art", Type: model.FeePercentage, BasisPoints: 1000},
+			},
+		},
+	}
+}
+
+func TestFeeCalculator_Quote(t *testing.T) {
+	fees, err := NewFeeCalculator(testFeesConfig())
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    model.FeeInput
+		wantRule string
+		wantTier string
+		wantFee  int64
+	}{
+		{
+			name:     "no rule matches",
+			input:    model.FeeInput{SellerUuid: "seller", Category: "books", Price: money.Money{Amount: 10000, Currency: money.USD}},
+			wantRule: DefaultFeeRule,
+			wantTier: DefaultTier,
+			wantFee:  500,
+		},
+		{
+			name:     "fixed fee under a price",
+			input:    model.FeeInput{SellerUuid: "seller", Category: "electronics", Price: money.Money{Amount: 999, Currency: money.USD}},
+			wantRule: "low value",
+			wantTier: DefaultTier,
+			wantFee:  50,
+		},
+		{
+			name:     "price range excludes its max",
+			input:    model.FeeInput{SellerUuid: "seller", Category: "books", Price: money.Money{Amount: 1000, Currency: money.USD}},
+			wantRule: DefaultFeeRule,
+			wantTier: DefaultTier,
+			wantFee:  50,
+		},
+		{
+			name:     "tiered fee of a seller tier",
+			input:    model.FeeInput{SellerUuid: "pro-seller", Category: "electronics", Price: money.Money{Amount: 150000, Currency: money.USD}},
+			wantRule: "pro sellers",
+			wantTier: "pro",
+			wantFee:  5000,
+		},
+		{
+			name:     "tiered fee inside the first band",
+			input:    model.FeeInput{SellerUuid: "pro-seller", Price: money.Money{Amount: 50000, Currency: money.USD}},
+			wantRule: "pro sellers",
+			wantTier: "pro",
+			wantFee:  2000,
+		},
+		{
+			name:     "percentage of a category",
+			input:    model.FeeInput{SellerUuid: "seller", Category: "electronics", Price: money.Money{Amount: 100000, Currency: money.USD}},
+			wantRule: "electronics",
+			wantTier: DefaultTier,
+			wantFee:  8000,
+		},
+		{
+			name:     "capped percentage",
+			input:    model.FeeInput{SellerUuid: "seller", Category: "electronics", Price: money.Money{Amount: 1000000, Currency: money.USD}},
+			wantRule: "electronics",
+			wantTier: DefaultTier,
+			wantFee:  25000,
+		},
+		{
+			name:     "rules with amounts only match their currency",
+			input:    model.FeeInput{SellerUuid: "seller", Category: "electronics", Price: money.Money{Amount: 500, Currency: money.EUR}},
+			wantRule: DefaultFeeRule,
+			wantTier: DefaultTier,
+			wantFee:  25,
+		},
+		{
+			name:     "percentage rules match any currency",
+			input:    model.FeeInput{SellerUuid: "seller", Category: "art", Price: money.Money{Amount: 12345, Currency: money.JPY}},
+			wantRule: "art",
+			wantTier: DefaultTier,
+			wantFee:  1234,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quote, err := fees.Quote(test.input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantRule, quote.Rule)
+			assert.Equal(t, test.wantTier, quote.Tier)
+			assert.Equal(t, money.Money{Amount: test.wantFee, Currency: test.input.Price.Currency}, quote.Fee)
+			assert.Equal(t, test.input.Price.Amount-test.wantFee, quote.SellerProceeds.Amount)
+		})
+	}
+}
+
+func TestFeeCalculator_QuoteNeverExceedsThePrice(t *testing.T) {
+	fees, err := NewFeeCalculator(Config{Fees: FeesConfig{Rules: []model.FeeRule{
+		{Name: "minimum", Type: model.FeePercentage, BasisPoints: 100, Currency: money.USD, Min: "5.00"},
+	}}})
+	assert.NoError(t, err)
+
+	quote, err := fees.Quote(model.FeeInput{SellerUuid: "seller", Price: money.Money{Amount: 300, Currency: money.USD}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(300), quote.Fee.Amount)
+	assert.Equal(t, int64(0), quote.SellerProceeds.Amount)
+
+	_, err = fees.Quote(model.FeeInput{SellerUuid: "seller", Price: money.Money{Currency: money.USD}})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestNewFeeCalculator_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule model.FeeRule
+	}{
+		{"no name", model.FeeRule{Type: model.FeePercentage, BasisPoints: 100}},
+		{"unknown type", model.FeeRule{Name: "rule", Type: "sliding"}},
+		{"amount without currency", model.FeeRule{Name: "rule", Type: model.FeeFixed, Fixed: "1.00"}},
+		{"unknown currency", model.FeeRule{Name: "rule", Type: model.FeePercentage, Currency: "XYZ"}},
+		{"too many decimals", model.FeeRule{Name: "rule", Type: model.FeeFixed, Fixed: "1.005", Currency: money.USD}},
+		{"basis points above 100%", model.FeeRule{Name: "rule", Type: model.FeePercentage, BasisPoints: 10001}},
+		{"min above max", model.FeeRule{Name: "rule", Type: model.FeePercentage, Currency: money.USD, Min: "5.00", Max: "1.00"}},
+		{"no bands", model.FeeRule{Name: "rule", Type: model.FeeTiered}},
+		{"closed last band", model.FeeRule{Name: "rule", Type: model.FeeTiered, Currency: money.USD, Bands: []model.FeeBand{{UpTo: "10.00", BasisPoints: 100}}}},
+		{"bands going down", model.FeeRule{Name: "rule", Type: model.FeeTiered, Currency: money.USD, Bands: []model.FeeBand{
+			{UpTo: "10.00", BasisPoints: 100},
+			{UpTo: "5.00", BasisPoints: 100},
+			{BasisPoints: 100},
+		}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewFeeCalculator(Config{Fees: FeesConfig{Rules: []model.FeeRule{test.rule}}})
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestServiceSettlement_SettlePricesTheFeeByRule(t *testing.T) {
+	fees, err := NewFeeCalculator(testFeesConfig())
+	assert.NoError(t, err)
+	repo := &MockRepository{}
+	echoInvoice(repo)
+	service := New(repo, NewFakeProvider(), fees, nil, nil, DisputesConfig{}, zap.NewNop())
+
+	invoice, err := service.Settle(model.SettleInput{
+		AuctionUuid: "auction-uuid",
+		BuyerUuid:   "buyer-uuid",
+		SellerUuid:  "seller-uuid",
+		Category:    "electronics",
+		Amount:      money.Money{Amount: 100000, Currency: money.USD},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "electronics", invoice.FeeRule)
+	assert.Equal(t, int64(8000), invoice.Fee)
+	entries := repo.mock.Calls[0].Arguments.Get(1).([]model.Entry)
+	assert.NoError(t, model.Balanced(entries))
+}
+
+func TestServiceSettlement_PreviewFee(t *testing.T) {
+	fees, err := NewFeeCalculator(testFeesConfig())
+	assert.NoError(t, err)
+	repo := &MockRepository{}
+	service := New(repo, NewFakeProvider(), fees, nil, nil, DisputesConfig{}, zap.NewNop())
+
+	quote, err := service.PreviewFee(model.FeeInput{SellerUuid: "pro-seller", Category: "art", Price: money.Money{Amount: 200000, Currency: money.USD}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pro sellers", quote.Rule)
+	assert.Equal(t, int64(6000), quote.Fee.Amount)
+	assert.Equal(t, int64(194000), quote.SellerProceeds.Amount)
+	repo.mock.AssertNotCalled(t, "CreateInvoice", mock.Anything, mock.Anything)
+
+	_, err = service.PreviewFee(model.FeeInput{Price: money.Money{Amount: 100, Currency: money.USD}})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	ErrDisputeExists   = errors.New("the invoice is already disputed")
+	ErrDisputeClosed   = errors.New("the dispute is resolved")
+	ErrDisputeConflict = errors.New("the dispute changed, reload it and retry")
+	ErrNotDisputable   = errors.New("the invoice can not be disputed")
+)
+
+// Refunded - the invoice of a dispute resolved with a refund, the sale and the payment are reversed in the ledger
+const Refunded InvoiceStatus = "refunded"
+
+// MaxReasonLength - the longest reason a dispute may carry, in characters
+const MaxReasonLength = 2000
+
+type DisputeStatus string
+
+const (
+	// DisputeOpened - the buyer opened the dispute and may attach evidence until the deadline
+	DisputeOpened DisputeStatus = "opened"
+	// AwaitingSeller - the seller accepts the refund or contests it until the deadline
+	AwaitingSeller DisputeStatus = "awaiting_seller"
+	// DisputeEscalated - the seller contested, an admin decides
+	DisputeEscalated DisputeStatus = "escalated"
+	ResolvedRefund   DisputeStatus = "resolved_refund"
+	ResolvedNoRefund DisputeStatus = "resolved_no_refund"
+)
+
+// Resolved - whether the dispute reached a final status
+func (s DisputeStatus) Resolved() bool {
+	return s == ResolvedRefund || s == ResolvedNoRefund
+}
+
+// Expired - the status a dispute moves to when its deadline passes: the evidence window of the buyer closes and
+// the dispute waits for the seller, a seller who did not answer in time loses the dispute
+func (s DisputeStatus) Expired() (DisputeStatus, bool) {
+	switch s {
+	case DisputeOpened:
+		return AwaitingSeller, true
+	case AwaitingSeller:
+		return ResolvedRefund, true
+	}
+
+	return "", false
+}
+
+// Party - the part a user plays in a dispute
+type Party string
+
+const (
+	PartyBuyer  Party = "buyer"
+	PartySeller Party = "seller"
+	PartyAdmin  Party = "admin"
+)
+
+type DisputeAction string
+
+const (
+	SubmitDispute   DisputeAction = "submit"
+	AcceptDispute   DisputeAction = "accept"
+	ContestDispute  DisputeAction = "contest"
+	WithdrawDispute DisputeAction = "withdraw"
+	RefundDispute   DisputeAction = "refund"
+	RejectDispute   DisputeAction = "reject"
+)
+
+// Transition - the party allowed to take an action, the statuses it is taken from and the status it leads to
+type Transition struct {
+	Party Party
+	From  []DisputeStatus
+	To    DisputeStatus
+}
+
+var unresolved = []DisputeStatus{DisputeOpened, AwaitingSeller, DisputeEscalated}
+
+// Transitions - the dispute state machine, the deadlines move disputes on as told by DisputeStatus.Expired
+var Transitions = map[DisputeAction]Transition{
+	SubmitDispute:   {Party: PartyBuyer, From: []DisputeStatus{DisputeOpened}, To: AwaitingSeller},
+	AcceptDispute:   {Party: PartySeller, From: []DisputeStatus{AwaitingSeller}, To: ResolvedRefund},
+	ContestDispute:  {Party: PartySeller, From: []DisputeStatus{AwaitingSeller}, To: DisputeEscalated},
+	WithdrawDispute: {Party: PartyBuyer, From: unresolved, To: ResolvedNoRefund},
+	RefundDispute:   {Party: PartyAdmin, From: unresolved, To: ResolvedRefund},
+	RejectDispute:   {Party: PartyAdmin, From: unresolved, To: ResolvedNoRefund},
+}
+
+// Next - the status the party taking the action moves the dispute to
+func (d Dispute) Next(action DisputeAction, party Party) (DisputeStatus, error) {
+	transition, ok := Transitions[action]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown action %q", ErrInvalidInput, action)
+	}
+
+	if d.Status.Resolved() {
+		return "", ErrDisputeClosed
+	}
+
+	if transition.Party != party {
+		return "", fmt.Errorf("%w: only the %s may %s", ErrForbidden, transition.Party, action)
+	}
+
+	for _, from := range transition.From {
+		if d.Status == from {
+			return transition.To, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: can not %s a dispute which is %s", ErrInvalidInput, action, d.Status)
+}
+
+// Dispute - the buyer of a paid invoice disputing the sale, one per invoice. Deadline is when the dispute moves
+// on by itself, it is empty once the dispute waits for an admin or is resolved
+type Dispute struct {
+	ID          int64         `json:"-" db:"id"`
+	Uuid        string        `json:"uuid" db:"uuid"`
+	InvoiceUuid string        `json:"invoiceUuid" db:"invoice_uuid"`
+	AuctionUuid string        `json:"auctionUuid" db:"auction_uuid"`
+	BuyerUuid   string        `json:"buyerUuid" db:"buyer_uuid"`
+	SellerUuid  string        `json:"sellerUuid" db:"seller_uuid"`
+	Reason      string        `json:"reason" db:"reason"`
+	Status      DisputeStatus `json:"status" db:"status"`
+	Deadline    *time.Time    `json:"deadline,omitempty" db:"deadline"`
+	CreatedAt   time.Time     `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updatedAt" db:"updated_at"`
+	ResolvedAt  *time.Time    `json:"resolvedAt,omitempty" db:"resolved_at"`
+	Evidence    []Evidence    `json:"evidence,omitempty" db:"-"`
+}
+
+// Evidence - a file attached to a dispute by one of its parties, the file is kept in object storage under Key
+type Evidence struct {
+	ID           int64     `json:"-" db:"id"`
+	Uuid         string    `json:"uuid" db:"uuid"`
+	DisputeUuid  string    `json:"disputeUuid" db:"dispute_uuid"`
+	UploaderUuid string    `json:"uploaderUuid" db:"uploader_uuid"`
+	Name         string    `json:"name" db:"name"`
+	ContentType  string    `json:"contentType" db:"content_type"`
+	Size         int64     `json:"size" db:"size"`
+	Key          string    `json:"key" db:"object_key"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+}
+
+// DisputeInput - the buyer, the user of the request, disputing a paid invoice
+type DisputeInput struct {
+	InvoiceUuid string `json:"-"`
+	BuyerUuid   string `json:"-"`
+	Reason      string `json:"reason"`
+}
+
+// DisputeActionInput - a party of the dispute, or an admin, taking an action on it as the user of the request
+type DisputeActionInput struct {
+	DisputeUuid string        `json:"-"`
+	ActorUuid   string        `json:"-"`
+	Action      DisputeAction `json:"action"`
+}
+
+// EvidenceInput - a file to attach to a dispute, File is read from its start
+type EvidenceInput struct {
+	DisputeUuid  string
+	UploaderUuid string
+	Name         string
+	ContentType  string
+	Size         int64
+	File         *os.File
+}
+
+// DisputeListInput - the disputes where the user is the buyer or the seller, newest first
+type DisputeListInput struct {
+	UserUuid string
+	Status   DisputeStatus
+	Page     PageRequest
+}
+
+type DisputeList struct {
+	Disputes []Dispute `json:"disputes"`
+	Total    int64     `json:"total"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+type FeeType string
+
+const (
+	FeePercentage FeeType = "percentage"
+	FeeFixed      FeeType = "fixed"
+	FeeTiered     FeeType = "tiered"
+)
+
+// FeeRule - a platform fee rule as configured. the rules are matched in order and the first rule matching
+// the category, the seller tier and the final price of a sale sets its fee, an empty condition matches every sale.
+// the amounts are decimals in Currency, a rule with amounts only matches the sales in its currency.
+// the price range includes MinPrice and excludes MaxPrice, Min and Max cap the fee whatever its type
+type FeeRule struct {
+	Name        string         `json:"name"`
+	Category    string         `json:"category"`
+	Tier        string         `json:"tier"`
+	Currency    money.Currency `json:"currency"`
+	MinPrice    string         `json:"minPrice"`
+	MaxPrice    string         `json:"maxPrice"`
+	Type        FeeType        `json:"type"`
+	BasisPoints int64          `json:"basisPoints"`
+	Fixed       string         `json:"fixed"`
+	Bands       []FeeBand      `json:"bands"`
+	Min         string         `json:"min"`
+	Max         string         `json:"max"`
+}
+
+// FeeBand - a band of a tiered fee, the part of the price up to UpTo is charged BasisPoints.
+// the last band has no UpTo and covers the rest of the price
+type FeeBand struct {
+	UpTo        string `json:"upTo"`
+	BasisPoints int64  `json:"basisPoints"`
+}
+
+// FeeInput - the sale to price the fee of
+type FeeInput struct {
+	SellerUuid string
+	Category   string
+	Price      money.Money
+}
+
+// FeeQuote - the fee of a sale and the rule it was priced by
+type FeeQuote struct {
+	Rule           string      `json:"rule"`
+	Tier           string      `json:"tier"`
+	Price          money.Money `json:"price"`
+	Fee            money.Money `json:"fee"`
+	SellerProceeds money.Money `json:"sellerProceeds"`
+}
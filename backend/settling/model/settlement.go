@@ -0,0 +1,186 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ireuven89/hello-world/backend/money"
+)
+
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrForbidden       = errors.New("forbidden")
+	ErrAlreadyPaid     = errors.New("invoice already paid")
+	ErrPaymentDeclined = errors.New("payment declined")
+	ErrUnbalanced      = errors.New("ledger transaction is not balanced")
+)
+
+type InvoiceStatus string
+
+const (
+	Pending InvoiceStatus = "pending"
+	Paid    InvoiceStatus = "paid"
+)
+
+// Invoice - what the buyer of a won auction owes, one per auction. Fee is the part of Amount kept by the platform,
+// priced by the fee rule FeeRule, the seller is owed Amount minus Fee
+type Invoice struct {
+	ID               int64          `json:"-" db:"id"`
+	Uuid             string         `json:"uuid" db:"uuid"`
+	AuctionUuid      string         `json:"auctionUuid" db:"auction_uuid"`
+	BuyerUuid        string         `json:"buyerUuid" db:"buyer_uuid"`
+	SellerUuid       string         `json:"sellerUuid" db:"seller_uuid"`
+	Amount           int64          `json:"amount" db:"amount"`
+	Fee              int64          `json:"fee" db:"fee"`
+	FeeRule          string         `json:"feeRule" db:"fee_rule"`
+	Currency         money.Currency `json:"currency" db:"currency"`
+	Status           InvoiceStatus  `json:"status" db:"status"`
+	PaymentReference string         `json:"paymentReference,omitempty" db:"payment_reference"`
+	CreatedAt        time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt        time.Time      `json:"updatedAt" db:"updated_at"`
+	PaidAt           *time.Time     `json:"paidAt,omitempty" db:"paid_at"`
+}
+
+// Money - the amount the buyer owes
+func (i Invoice) Money() money.Money {
+	return money.Money{Amount: i.Amount, Currency: i.Currency}
+}
+
+// SellerProceeds - what the seller is owed once the platform fee is taken
+func (i Invoice) SellerProceeds() int64 {
+	return i.Amount - i.Fee
+}
+
+type Direction string
+
+const (
+	Debit  Direction = "debit"
+	Credit Direction = "credit"
+)
+
+// PlatformFees - the ledger account of the fees kept by the platform
+const PlatformFees = "platform:fees"
+
+func BuyerAccount(userUuid string) string {
+	return "buyer:" + userUuid
+}
+
+func SellerAccount(userUuid string) string {
+	return "seller:" + userUuid
+}
+
+// PaymentsAccount - the ledger account of the money received through a payment provider
+func PaymentsAccount(provider string) string {
+	return "payments:" + provider
+}
+
+// Entry - a line of the double-entry ledger. the entries of a transaction share TransactionUuid and their
+// debits and credits sum up to the same amount
+type Entry struct {
+	ID              int64          `json:"-" db:"id"`
+	Uuid            string         `json:"uuid" db:"uuid"`
+	TransactionUuid string         `json:"transactionUuid" db:"transaction_uuid"`
+	InvoiceUuid     string         `json:"invoiceUuid" db:"invoice_uuid"`
+	Account         string         `json:"account" db:"account"`
+	Direction       Direction      `json:"direction" db:"direction"`
+	Amount          int64          `json:"amount" db:"amount"`
+	Currency        money.Currency `json:"currency" db:"currency"`
+	Memo            string         `json:"memo" db:"memo"`
+	CreatedAt       time.Time      `json:"createdAt" db:"created_at"`
+}
+
+// Balanced - checks the entries of a transaction are all in one currency and their debits equal their credits
+func Balanced(entries []Entry) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("%w: no entries", ErrUnbalanced)
+	}
+
+	var debits, credits int64
+	for _, entry := range entries {
+		if entry.Currency != entries[0].Currency {
+			return fmt.Errorf("%w: %w", ErrUnbalanced, money.ErrCurrencyMismatch)
+		}
+
+		if entry.Amount < 0 {
+			return fmt.Errorf("%w: negative amount on %s", ErrUnbalanced, entry.Account)
+		}
+
+		switch entry.Direction {
+		case Debit:
+			debits += entry.Amount
+		case Credit:
+			credits += entry.Amount
+		default:
+			return fmt.Errorf("%w: unknown direction %q", ErrUnbalanced, entry.Direction)
+		}
+	}
+
+	if debits != credits {
+		return fmt.Errorf("%w: debits %d, credits %d", ErrUnbalanced, debits, credits)
+	}
+
+	return nil
+}
+
+// SettleInput - the sale of a won auction to settle
+type SettleInput struct {
+	AuctionUuid string
+	BuyerUuid   string
+	SellerUuid  string
+	Category    string
+	Amount      money.Money
+}
+
+// PayInput - the buyer, the user of the request, paying an invoice through the payment provider
+type PayInput struct {
+	InvoiceUuid string `json:"-"`
+	PayerUuid   string `json:"-"`
+}
+
+// ChargeInput - a charge asked from the payment provider, charges with the same IdempotencyKey are charged once
+type ChargeInput struct {
+	IdempotencyKey string
+	PayerUuid      string
+	Amount         money.Money
+	Description    string
+}
+
+type ChargeStatus string
+
+const (
+	ChargeSucceeded ChargeStatus = "succeeded"
+	ChargeDeclined  ChargeStatus = "declined"
+)
+
+// Charge - the outcome of a charge, Reference identifies it at the provider
+type Charge struct {
+	Reference string
+	Status    ChargeStatus
+	Reason    string
+}
+
+type InvoiceListInput struct {
+	UserUuid string
+	Page     PageRequest
+}
+
+// InvoiceList - a page of the invoices where the user is the buyer or the seller
+type InvoiceList struct {
+	Invoices []Invoice
+	Total    int64
+}
+
+type PageRequest struct {
+	Offset int64
+	Limit  int64
+}
+
+func (p *PageRequest) GetLimit() int64 {
+	if p.Limit == 0 {
+		return 50
+	}
+
+	return p.Limit
+}
@@ -0,0 +1,73 @@
+package settling
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+// PaymentProvider - charges buyers through a payment gateway
+type PaymentProvider interface {
+	Name() string
+	Charge(input model.ChargeInput) (model.Charge, error)
+}
+
+// FakeProvider - a payment provider kept in memory for local runs and tests. every charge succeeds
+// unless the payer was declined, a charge repeated with the same idempotency key returns the first outcome
+type FakeProvider struct {
+	mu       sync.Mutex
+	declined map[string]bool
+	charges  map[string]model.Charge
+}
+
+func NewFakeProvider() *FakeProvider {
+
+	return &FakeProvider{
+		declined: map[string]bool{},
+		charges:  map[string]model.Charge{},
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// Decline - makes the following charges of the payer fail
+func (p *FakeProvider) Decline(payerUuid string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.declined[payerUuid] = true
+}
+
+func (p *FakeProvider) Charge(input model.ChargeInput) (model.Charge, error) {
+	if input.IdempotencyKey == "" || input.Amount.Amount <= 0 {
+		return model.Charge{}, fmt.Errorf("%w: idempotency key and a positive amount are required", model.ErrInvalidInput)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if charge, ok := p.charges[input.IdempotencyKey]; ok {
+		return charge, nil
+	}
+
+	charge := model.Charge{Reference: "fake_" + uuid.New().String(), Status: model.ChargeSucceeded}
+	if p.declined[input.PayerUuid] {
+		charge.Status, charge.Reason = model.ChargeDeclined, "card declined"
+	}
+	p.charges[input.IdempotencyKey] = charge
+
+	return charge, nil
+}
+
+// Charges - the number of charges the provider took, repeated idempotency keys count once
+func (p *FakeProvider) Charges() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.charges)
+}
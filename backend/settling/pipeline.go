@@ -0,0 +1,50 @@
+package settling
+
+import (
+	"encoding/json"
+	"errors"
+
+	"go.uber.org/zap"
+
+	auctionmodel "github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+// HandleEvent - settles the auctions closed as sold, the other events are ignored.
+// the auctions stored before currencies were introduced are settled in the default currency.
+// the event carries the winning bidder, the buyer is the user the bidder bids for
+func (s *ServiceSettlement) HandleEvent(message []byte) error {
+	var event auctionmodel.Event
+
+	if err := json.Unmarshal(message, &event); err != nil {
+		s.logger.Error("ServiceSettlement.HandleEvent failed decoding event", zap.ByteString("message", message), zap.Error(err))
+		return err
+	}
+
+	if event.Type != auctionmodel.EventClosed || event.Status != auctionmodel.Sold || event.WinnerUuid == "" {
+		return nil
+	}
+
+	winner, err := s.bidders.FindOne(event.WinnerUuid)
+	if err != nil && !errors.Is(err, bidermodel.ErrNotFound) {
+		s.logger.Error("ServiceSettlement.HandleEvent failed finding the winner", zap.Any("event", event), zap.Error(err))
+		return err
+	}
+
+	currency := event.Currency
+	if currency == "" {
+		currency = money.Default
+	}
+
+	_, err = s.Settle(model.SettleInput{
+		AuctionUuid: event.AuctionUuid,
+		BuyerUuid:   winner.UserUuid,
+		SellerUuid:  event.SellerUuid,
+		Category:    event.Category,
+		Amount:      money.Money{Amount: event.WinningPrice, Currency: currency},
+	})
+
+	return err
+}
@@ -0,0 +1,96 @@
+package settling
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	auctionmodel "github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+func encodeEvent(t *testing.T, event auctionmodel.Event) []byte {
+	message, err := json.Marshal(event)
+	assert.NoError(t, err)
+
+	return message
+}
+
+// stubBidders - the user each known bidder bids for, other bidders are not found
+type stubBidders map[string]string
+
+func (b stubBidders) FindOne(uuid string) (bidermodel.Bidder, error) {
+	userUuid, ok := b[uuid]
+	if !ok {
+		return bidermodel.Bidder{}, bidermodel.ErrNotFound
+	}
+
+	return bidermodel.Bidder{Uuid: uuid, UserUuid: userUuid}, nil
+}
+
+func TestServiceSettlement_HandleEventSettlesSoldAuctions(t *testing.T) {
+	repo := &MockRepository{}
+	echoInvoice(repo)
+	service := New(repo, NewFakeProvider(), flatFees(1000), nil, stubBidders{"bidder-uuid": "buyer-uuid"}, DisputesConfig{}, zap.NewNop())
+
+	err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+		Type:         auctionmodel.EventClosed,
+		AuctionUuid:  "auction-uuid",
+		Status:       auctionmodel.Sold,
+		WinnerUuid:   "bidder-uuid",
+		SellerUuid:   "seller-uuid",
+		WinningPrice: 2000,
+		Currency:     money.GBP,
+	}))
+
+	assert.NoError(t, err)
+	invoice := repo.mock.Calls[0].Arguments.Get(0).(model.Invoice)
+	assert.Equal(t, "auction-uuid", invoice.AuctionUuid)
+	assert.Equal(t, "buyer-uuid", invoice.BuyerUuid)
+	assert.Equal(t, "seller-uuid", invoice.SellerUuid)
+	assert.Equal(t, int64(200), invoice.Fee)
+	assert.Equal(t, money.GBP, invoice.Currency)
+}
+
+func TestServiceSettlement_HandleEventUnknownWinner(t *testing.T) {
+	repo := &MockRepository{}
+	service := New(repo, NewFakeProvider(), flatFees(0), nil, stubBidders{}, DisputesConfig{}, zap.NewNop())
+
+	err := service.HandleEvent(encodeEvent(t, auctionmodel.Event{
+		Type:         auctionmodel.EventClosed,
+		AuctionUuid:  "auction-uuid",
+		Status:       auctionmodel.Sold,
+		WinnerUuid:   "bidder-gone",
+		SellerUuid:   "seller-uuid",
+		WinningPrice: 2000,
+	}))
+
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	repo.mock.AssertNotCalled(t, "CreateInvoice", mock.Anything, mock.Anything)
+}
+
+func TestServiceSettlement_HandleEventIgnoresOtherEvents(t *testing.T) {
+	events := []auctionmodel.Event{
+		{Type: auctionmodel.EventBid, AuctionUuid: "auction-uuid", Status: auctionmodel.InProgress, WinnerUuid: "buyer-uuid", WinningPrice: 100},
+		{Type: auctionmodel.EventClosed, AuctionUuid: "auction-uuid", Status: auctionmodel.Expired},
+	}
+
+	for _, event := range events {
+		repo := &MockRepository{}
+		service := New(repo, NewFakeProvider(), flatFees(0), nil, nil, DisputesConfig{}, zap.NewNop())
+
+		assert.NoError(t, service.HandleEvent(encodeEvent(t, event)))
+		repo.mock.AssertNotCalled(t, "CreateInvoice", mock.Anything, mock.Anything)
+	}
+}
+
+func TestServiceSettlement_HandleEventInvalidMessage(t *testing.T) {
+	service := New(&MockRepository{}, NewFakeProvider(), flatFees(0), nil, nil, DisputesConfig{}, zap.NewNop())
+
+	assert.Error(t, service.HandleEvent([]byte("not json")))
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+var disputeColumns = []string{
+	"id",
+	"uuid",
+	"invoice_uuid",
+	"auction_uuid",
+	"buyer_uuid",
+	"seller_uuid",
+	"reason",
+	"status",
+	"deadline",
+	"created_at",
+	"updated_at",
+	"resolved_at",
+}
+
+var evidenceColumns = []string{
+	"id",
+	"uuid",
+	"dispute_uuid",
+	"uploader_uuid",
+	"name",
+	"content_type",
+	"size",
+	"object_key",
+	"created_at",
+}
+
+// CreateDispute - inserts the dispute, an invoice is disputed once
+func (r *SettlementRepository) CreateDispute(dispute model.Dispute) error {
+	q := r.db.InsertInto(dbmodel.Disputes).
+		ValueMap(map[string]interface{}{
+			"uuid":         dispute.Uuid,
+			"invoice_uuid": dispute.InvoiceUuid,
+			"auction_uuid": dispute.AuctionUuid,
+			"buyer_uuid":   dispute.BuyerUuid,
+			"seller_uuid":  dispute.SellerUuid,
+			"reason":       dispute.Reason,
+			"status":       dispute.Status,
+			"deadline":     dispute.Deadline,
+			"created_at":   dispute.CreatedAt,
+			"updated_at":   dispute.UpdatedAt,
+		})
+
+	utils.New().DebugInsert(q, "insert dispute")
+
+	if _, err := q.Exec(); err != nil {
+		if isDuplicate(err) {
+			return model.ErrDisputeExists
+		}
+		r.logger.Error("SettlementRepository.CreateDispute failed inserting dispute", zap.String("invoice", dispute.InvoiceUuid), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Dispute - this method queries the dispute by its uuid
+func (r *SettlementRepository) Dispute(uuid string) (model.Dispute, error) {
+	var result model.Dispute
+
+	q := r.db.
+		Select(disputeColumns...).
+		From(dbmodel.Disputes).
+		Where(sqlz.Eq("uuid", uuid))
+
+	utils.New().DebugSelect(q, "get dispute")
+
+	if err := q.GetRow(&result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Dispute{}, model.ErrNotFound
+		}
+		r.logger.Error("SettlementRepository.Dispute failed getting dispute", zap.String("uuid", uuid), zap.Error(err))
+		return model.Dispute{}, err
+	}
+
+	return result, nil
+}
+
+// ListDisputes - this method queries a page of the disputes where the user is the buyer or the seller, newest first
+func (r *SettlementRepository) ListDisputes(input model.DisputeListInput) (model.DisputeList, error) {
+	var result model.DisputeList
+
+	conditions := []sqlz.WhereCondition{sqlz.Or(sqlz.Eq("buyer_uuid", input.UserUuid), sqlz.Eq("seller_uuid", input.UserUuid))}
+	if input.Status != "" {
+		conditions = append(conditions, sqlz.Eq("status", input.Status))
+	}
+
+	q := r.db.
+		Select(disputeColumns...).
+		From(dbmodel.Disputes).
+		Where(conditions...).
+		OrderBy(sqlz.Desc("id")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list disputes")
+
+	if err := q.GetAll(&result.Disputes); err != nil {
+		r.logger.Error("SettlementRepository.ListDisputes failed listing disputes", zap.Any("input", input), zap.Error(err))
+		return model.DisputeList{}, err
+	}
+
+	total, err := r.db.
+		Select("*").
+		From(dbmodel.Disputes).
+		Where(conditions...).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("SettlementRepository.ListDisputes failed counting disputes", zap.Any("input", input), zap.Error(err))
+		return model.DisputeList{}, err
+	}
+	result.Total = total
+
+	return result, nil
+}
+
+// DueDisputes - this method queries a batch of the disputes whose deadline passed, oldest deadline first
+func (r *SettlementRepository) DueDisputes(now time.Time, limit int64) ([]model.Dispute, error) {
+	var result []model.Dispute
+
+	q := r.db.
+		Select(disputeColumns...).
+		From(dbmodel.Disputes).
+		Where(sqlz.In("status", model.DisputeOpened, model.AwaitingSeller), sqlz.Lte("deadline", now)).
+		OrderBy(sqlz.Asc("deadline")).
+		Limit(limit)
+
+	utils.New().DebugSelect(q, "due disputes")
+
+	if err := q.GetAll(&result); err != nil {
+		r.logger.Error("SettlementRepository.DueDisputes failed listing disputes", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// TransitionDispute - moves the dispute from the status it was read in to its new status. a dispute moved by
+// someone else in the meantime is a conflict. a refund reverses the paid invoice and posts the refund entries
+// in the same transaction
+func (r *SettlementRepository) TransitionDispute(dispute model.Dispute, from model.DisputeStatus, refund []model.Entry) error {
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.
+			Update(dbmodel.Disputes).
+			SetMap(map[string]interface{}{
+				"status":      dispute.Status,
+				"deadline":    dispute.Deadline,
+				"updated_at":  dispute.UpdatedAt,
+				"resolved_at": dispute.ResolvedAt,
+			}).
+			Where(sqlz.Eq("uuid", dispute.Uuid), sqlz.Eq("status", from))
+
+		utils.New().DebugUpdate(q, "transition dispute")
+
+		res, err := q.Exec()
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return model.ErrDisputeConflict
+		}
+
+		if len(refund) == 0 {
+			return nil
+		}
+
+		update := tx.
+			Update(dbmodel.Invoices).
+			Set("status", model.Refunded).
+			Set("updated_at", dispute.UpdatedAt).
+			Where(sqlz.Eq("uuid", dispute.InvoiceUuid), sqlz.Eq("status", model.Paid))
+
+		utils.New().DebugUpdate(update, "refund invoice")
+
+		res, err = update.Exec()
+		if err != nil {
+			return err
+		}
+
+		if affected, err = res.RowsAffected(); err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return model.ErrNotDisputable
+		}
+
+		return insertEntries(tx, refund, dispute.UpdatedAt)
+	})
+
+	if err != nil && !errors.Is(err, model.ErrDisputeConflict) {
+		r.logger.Error("SettlementRepository.TransitionDispute failed updating dispute", zap.String("dispute", dispute.Uuid), zap.Error(err))
+	}
+
+	return err
+}
+
+// CreateEvidence - inserts the evidence attached to a dispute
+func (r *SettlementRepository) CreateEvidence(evidence model.Evidence) error {
+	q := r.db.InsertInto(dbmodel.DisputeEvidence).
+		ValueMap(map[string]interface{}{
+			"uuid":          evidence.Uuid,
+			"dispute_uuid":  evidence.DisputeUuid,
+			"uploader_uuid": evidence.UploaderUuid,
+			"name":          evidence.Name,
+			"content_type":  evidence.ContentType,
+			"size":          evidence.Size,
+			"object_key":    evidence.Key,
+			"created_at":    evidence.CreatedAt,
+		})
+
+	utils.New().DebugInsert(q, "insert evidence")
+
+	if _, err := q.Exec(); err != nil {
+		r.logger.Error("SettlementRepository.CreateEvidence failed inserting evidence", zap.String("dispute", evidence.DisputeUuid), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Evidence - this method queries the evidence of the dispute in the order it was attached
+func (r *SettlementRepository) Evidence(disputeUuid string) ([]model.Evidence, error) {
+	var result []model.Evidence
+
+	q := r.db.
+		Select(evidenceColumns...).
+		From(dbmodel.DisputeEvidence).
+		Where(sqlz.Eq("dispute_uuid", disputeUuid)).
+		OrderBy(sqlz.Asc("id"))
+
+	utils.New().DebugSelect(q, "list evidence")
+
+	if err := q.GetAll(&result); err != nil {
+		r.logger.Error("SettlementRepository.Evidence failed listing evidence", zap.String("dispute", disputeUuid), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+func testDispute(status model.DisputeStatus, now time.Time) model.Dispute {
+	return model.Dispute{
+		Uuid:        "dispute-uuid",
+		InvoiceUuid: "invoice-uuid",
+		BuyerUuid:   "buyer-uuid",
+		SellerUuid:  "seller-uuid",
+		Status:      status,
+		UpdatedAt:   now,
+	}
+}
+
+func TestSettlementRepository_CreateDisputeTwice(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectExec("INSERT INTO disputes").
+		WillReturnError(&mysql.MySQLError{Number: duplicateEntry, Message: "Duplicate entry"})
+
+	err = repo.CreateDispute(testDispute(model.DisputeOpened, time.Now()))
+
+	assert.ErrorIs(t, err, model.ErrDisputeExists)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_TransitionDisputeRefund(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("UPDATE disputes SET (.+) WHERE uuid = \\? AND status = \\?").
+		WithArgs(nil, nil, model.ResolvedRefund, now, "dispute-uuid", model.AwaitingSeller).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectExec("UPDATE invoices SET status = \\?, updated_at = \\? WHERE uuid = \\? AND status = \\?").
+		WithArgs(model.Refunded, now, "invoice-uuid", model.Paid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectExec("INSERT INTO ledger_entries").WillReturnResult(sqlmock.NewResult(3, 3))
+	mockSql.ExpectCommit()
+
+	err = repo.TransitionDispute(testDispute(model.ResolvedRefund, now), model.AwaitingSeller, testEntries())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_TransitionDisputeConflict(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("UPDATE disputes SET (.+) WHERE uuid = \\? AND status = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectRollback()
+
+	err = repo.TransitionDispute(testDispute(model.AwaitingSeller, time.Now()), model.DisputeOpened, nil)
+
+	assert.ErrorIs(t, err, model.ErrDisputeConflict)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_DueDisputes(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+	now := time.Now()
+
+	mockSql.ExpectQuery("SELECT (.+) FROM disputes WHERE status IN \\(\\?, \\?\\) AND deadline <= \\? ORDER BY deadline ASC LIMIT 10").
+		WithArgs(model.DisputeOpened, model.AwaitingSeller, now).
+		WillReturnRows(sqlmock.NewRows(disputeColumns).
+			AddRow(1, "dispute-uuid", "invoice-uuid", "auction-uuid", "buyer-uuid", "seller-uuid", "broken", "opened", now, now, now, nil))
+
+	result, err := repo.DueDisputes(now, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, model.DisputeOpened, result[0].Status)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
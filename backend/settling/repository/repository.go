@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/ido50/sqlz"
+	"go.uber.org/zap"
+
+	dbmodel "github.com/ireuven89/hello-world/backend/db/model"
+	"github.com/ireuven89/hello-world/backend/db/utils"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+// duplicateEntry - the mysql error number of a unique key violation
+const duplicateEntry = 1062
+
+var invoiceColumns = []string{
+	"id",
+	"uuid",
+	"auction_uuid",
+	"buyer_uuid",
+	"seller_uuid",
+	"amount",
+	"fee",
+	"fee_rule",
+	"currency",
+	"status",
+	"payment_reference",
+	"created_at",
+	"updated_at",
+	"paid_at",
+}
+
+var entryColumns = []string{
+	"id",
+	"uuid",
+	"transaction_uuid",
+	"invoice_uuid",
+	"account",
+	"direction",
+	"amount",
+	"currency",
+	"memo",
+	"created_at",
+}
+
+type SettlementRepository struct {
+	db     *sqlz.DB
+	logger *zap.Logger
+}
+
+func New(db *sqlz.DB, logger *zap.Logger) *SettlementRepository {
+
+	return &SettlementRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateInvoice - inserts the invoice and its ledger entries in one transaction. an auction has a single invoice,
+// when it already has one the existing invoice is returned and nothing is posted
+func (r *SettlementRepository) CreateInvoice(invoice model.Invoice, entries []model.Entry) (model.Invoice, error) {
+	now := time.Now()
+	invoice.CreatedAt, invoice.UpdatedAt = now, now
+
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.InsertInto(dbmodel.Invoices).
+			ValueMap(map[string]interface{}{
+				"uuid":         invoice.Uuid,
+				"auction_uuid": invoice.AuctionUuid,
+				"buyer_uuid":   invoice.BuyerUuid,
+				"seller_uuid":  invoice.SellerUuid,
+				"amount":       invoice.Amount,
+				"fee":          invoice.Fee,
+				"fee_rule":     invoice.FeeRule,
+				"currency":     invoice.Currency,
+				"status":       invoice.Status,
+				"created_at":   now,
+				"updated_at":   now,
+			})
+
+		utils.New().DebugInsert(q, "insert invoice")
+
+		if _, err := q.Exec(); err != nil {
+			return err
+		}
+
+		return insertEntries(tx, entries, now)
+	})
+
+	if err != nil {
+		if isDuplicate(err) {
+			return r.InvoiceByAuction(invoice.AuctionUuid)
+		}
+		r.logger.Error("SettlementRepository.CreateInvoice failed creating invoice", zap.Error(err))
+		return model.Invoice{}, err
+	}
+
+	return invoice, nil
+}
+
+// Invoice - this method queries a single invoice
+func (r *SettlementRepository) Invoice(uuid string) (model.Invoice, error) {
+	return r.invoice(sqlz.Eq("uuid", uuid))
+}
+
+// InvoiceByAuction - this method queries the invoice of an auction
+func (r *SettlementRepository) InvoiceByAuction(auctionUuid string) (model.Invoice, error) {
+	return r.invoice(sqlz.Eq("auction_uuid", auctionUuid))
+}
+
+func (r *SettlementRepository) invoice(where sqlz.WhereCondition) (model.Invoice, error) {
+	var result model.Invoice
+
+	q := r.db.
+		Select(invoiceColumns...).
+		From(dbmodel.Invoices).
+		Where(where)
+
+	utils.New().DebugSelect(q, "single invoice")
+
+	if err := q.GetRow(&result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Invoice{}, model.ErrNotFound
+		}
+		r.logger.Error("SettlementRepository.invoice failed finding invoice", zap.Error(err))
+		return model.Invoice{}, err
+	}
+
+	return result, nil
+}
+
+// ListInvoices - this method queries a page of the invoices where the user is the buyer or the seller, latest first
+func (r *SettlementRepository) ListInvoices(input model.InvoiceListInput) (model.InvoiceList, error) {
+	var result model.InvoiceList
+
+	where := sqlz.Or(sqlz.Eq("buyer_uuid", input.UserUuid), sqlz.Eq("seller_uuid", input.UserUuid))
+
+	q := r.db.
+		Select(invoiceColumns...).
+		From(dbmodel.Invoices).
+		Where(where).
+		OrderBy(sqlz.Desc("id")).
+		Limit(input.Page.GetLimit()).
+		Offset(input.Page.Offset)
+
+	utils.New().DebugSelect(q, "list invoices")
+
+	if err := q.GetAll(&result.Invoices); err != nil {
+		r.logger.Error("SettlementRepository.ListInvoices failed listing invoices", zap.Error(err))
+		return model.InvoiceList{}, err
+	}
+
+	total, err := r.db.
+		Select("*").
+		From(dbmodel.Invoices).
+		Where(where).
+		GetCount()
+
+	if err != nil {
+		r.logger.Error("SettlementRepository.ListInvoices failed counting invoices", zap.Error(err))
+		return model.InvoiceList{}, err
+	}
+	result.Total = total
+
+	return result, nil
+}
+
+// Entries - this method queries the ledger entries of an invoice in posting order
+func (r *SettlementRepository) Entries(invoiceUuid string) ([]model.Entry, error) {
+	var result []model.Entry
+
+	q := r.db.
+		Select(entryColumns...).
+		From(dbmodel.LedgerEntries).
+		Where(sqlz.Eq("invoice_uuid", invoiceUuid)).
+		OrderBy(sqlz.Asc("id"))
+
+	utils.New().DebugSelect(q, "list ledger entries")
+
+	if err := q.GetAll(&result); err != nil {
+		r.logger.Error("SettlementRepository.Entries failed listing entries", zap.String("invoice", invoiceUuid), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MarkPaid - moves a pending invoice to paid and posts the payment entries in the same transaction,
+// an invoice which is not pending anymore is left as is
+func (r *SettlementRepository) MarkPaid(invoiceUuid string, reference string, paidAt time.Time, entries []model.Entry) error {
+	err := r.db.Transactional(func(tx *sqlz.Tx) error {
+		q := tx.
+			Update(dbmodel.Invoices).
+			SetMap(map[string]interface{}{
+				"status":            model.Paid,
+				"payment_reference": reference,
+				"paid_at":           paidAt,
+				"updated_at":        paidAt,
+			}).
+			Where(sqlz.Eq("uuid", invoiceUuid), sqlz.Eq("status", model.Pending))
+
+		utils.New().DebugUpdate(q, "mark invoice paid")
+
+		res, err := q.Exec()
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return model.ErrAlreadyPaid
+		}
+
+		return insertEntries(tx, entries, paidAt)
+	})
+
+	if err != nil && !errors.Is(err, model.ErrAlreadyPaid) {
+		r.logger.Error("SettlementRepository.MarkPaid failed marking invoice paid", zap.String("invoice", invoiceUuid), zap.Error(err))
+	}
+
+	return err
+}
+
+// insertEntries - inserts the entries of a ledger transaction in a single statement
+func insertEntries(tx *sqlz.Tx, entries []model.Entry, now time.Time) error {
+	values := make([][]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		values = append(values, []interface{}{
+			uuid.New().String(),
+			entry.TransactionUuid,
+			entry.InvoiceUuid,
+			entry.Account,
+			entry.Direction,
+			entry.Amount,
+			entry.Currency,
+			entry.Memo,
+			now,
+		})
+	}
+
+	q := tx.
+		InsertInto(dbmodel.LedgerEntries).
+		Columns("uuid", "transaction_uuid", "invoice_uuid", "account", "direction", "amount", "currency", "memo", "created_at").
+		ValueMultiple(values)
+
+	utils.New().DebugInsert(q, "insert ledger entries")
+
+	_, err := q.Exec()
+
+	return err
+}
+
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == duplicateEntry
+}
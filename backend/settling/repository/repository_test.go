@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/ido50/sqlz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+func testInvoice() model.Invoice {
+	return model.Invoice{
+		Uuid:        "invoice-uuid",
+		AuctionUuid: "auction-uuid",
+		BuyerUuid:   "buyer-uuid",
+		SellerUuid:  "seller-uuid",
+		Amount:      1000,
+		Fee:         50,
+		Currency:    money.USD,
+		Status:      model.Pending,
+	}
+}
+
+func testEntries() []model.Entry {
+	return []model.Entry{
+		{TransactionUuid: "tx-uuid", InvoiceUuid: "invoice-uuid", Account: "buyer:buyer-uuid", Direction: model.Debit, Amount: 1000, Currency: money.USD},
+		{TransactionUuid: "tx-uuid", InvoiceUuid: "invoice-uuid", Account: "seller:seller-uuid", Direction: model.Credit, Amount: 950, Currency: money.USD},
+		{TransactionUuid: "tx-uuid", InvoiceUuid: "invoice-uuid", Account: model.PlatformFees, Direction: model.Credit, Amount: 50, Currency: money.USD},
+	}
+}
+
+func TestSettlementRepository_CreateInvoice(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("INSERT INTO invoices").WillReturnResult(sqlmock.NewResult(1, 1))
+	mockSql.ExpectExec("INSERT INTO ledger_entries").WillReturnResult(sqlmock.NewResult(3, 3))
+	mockSql.ExpectCommit()
+
+	invoice, err := repo.CreateInvoice(testInvoice(), testEntries())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "invoice-uuid", invoice.Uuid)
+	assert.False(t, invoice.CreatedAt.IsZero())
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_CreateInvoiceTwice(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("INSERT INTO invoices").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+	mockSql.ExpectRollback()
+	mockSql.ExpectQuery("SELECT (.+) FROM invoices WHERE auction_uuid = \\?").
+		WithArgs("auction-uuid").
+		WillReturnRows(sqlmock.NewRows(invoiceColumns).
+			AddRow(1, "existing-uuid", "auction-uuid", "buyer-uuid", "seller-uuid", 1000, 50, "default", "USD", "pending", "", time.Now(), time.Now(), nil))
+
+	invoice, err := repo.CreateInvoice(testInvoice(), testEntries())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "existing-uuid", invoice.Uuid)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_InvoiceNotFound(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT (.+) FROM invoices WHERE uuid = \\?").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(invoiceColumns))
+
+	_, err = repo.Invoice("missing")
+
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_ListInvoices(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectQuery("SELECT (.+) FROM invoices WHERE buyer_uuid = \\? OR seller_uuid = \\? ORDER BY id DESC").
+		WithArgs("user-uuid", "user-uuid").
+		WillReturnRows(sqlmock.NewRows(invoiceColumns).
+			AddRow(1, "invoice-uuid", "auction-uuid", "user-uuid", "seller-uuid", 1000, 50, "art", "USD", "paid", "fake-1", time.Now(), time.Now(), time.Now()))
+	mockSql.ExpectQuery("SELECT COUNT\\(\\*\\) FROM invoices").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	list, err := repo.ListInvoices(model.InvoiceListInput{UserUuid: "user-uuid"})
+
+	assert.NoError(t, err)
+	assert.Len(t, list.Invoices, 1)
+	assert.Equal(t, model.Paid, list.Invoices[0].Status)
+	assert.Equal(t, "art", list.Invoices[0].FeeRule)
+	assert.Equal(t, int64(1), list.Total)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_MarkPaid(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("UPDATE invoices SET (.+) WHERE uuid = \\? AND status = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockSql.ExpectExec("INSERT INTO ledger_entries").WillReturnResult(sqlmock.NewResult(2, 2))
+	mockSql.ExpectCommit()
+
+	err = repo.MarkPaid("invoice-uuid", "fake-1", time.Now(), testEntries()[:2])
+
+	assert.NoError(t, err)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_MarkPaidTwice(t *testing.T) {
+	mockDB, mockSql, err := sqlmock.New()
+	assert.NoError(t, err)
+	repo := New(sqlz.New(mockDB, "mysql"), zap.NewNop())
+
+	mockSql.ExpectBegin()
+	mockSql.ExpectExec("UPDATE invoices SET (.+) WHERE uuid = \\? AND status = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockSql.ExpectRollback()
+
+	err = repo.MarkPaid("invoice-uuid", "fake-1", time.Now(), testEntries()[:2])
+
+	assert.ErrorIs(t, err, model.ErrAlreadyPaid)
+	assert.NoError(t, mockSql.ExpectationsWereMet())
+}
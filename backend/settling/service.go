@@ -0,0 +1,313 @@
+package settling
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/aws"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+type Service interface {
+	Settle(input model.SettleInput) (model.Invoice, error)
+	GetInvoice(uuid string, viewerUuid string) (model.Invoice, error)
+	AuctionInvoice(auctionUuid string, viewerUuid string) (model.Invoice, error)
+	ListInvoices(input model.InvoiceListInput) (model.InvoiceList, error)
+	Ledger(invoiceUuid string, viewerUuid string) ([]model.Entry, error)
+	PayInvoice(input model.PayInput) (model.Invoice, error)
+	PreviewFee(input model.FeeInput) (model.FeeQuote, error)
+	OpenDispute(input model.DisputeInput) (model.Dispute, error)
+	GetDispute(uuid string, viewerUuid string) (model.Dispute, error)
+	ListDisputes(input model.DisputeListInput) (model.DisputeList, error)
+	ActOnDispute(input model.DisputeActionInput) (model.Dispute, error)
+	AddEvidence(input model.EvidenceInput) (model.Evidence, error)
+	ExpireDisputes(now time.Time) error
+	HandleEvent(message []byte) error
+}
+
+type Repository interface {
+	CreateInvoice(invoice model.Invoice, entries []model.Entry) (model.Invoice, error)
+	Invoice(uuid string) (model.Invoice, error)
+	InvoiceByAuction(auctionUuid string) (model.Invoice, error)
+	ListInvoices(input model.InvoiceListInput) (model.InvoiceList, error)
+	Entries(invoiceUuid string) ([]model.Entry, error)
+	MarkPaid(invoiceUuid string, reference string, paidAt time.Time, entries []model.Entry) error
+	CreateDispute(dispute model.Dispute) error
+	Dispute(uuid string) (model.Dispute, error)
+	ListDisputes(input model.DisputeListInput) (model.DisputeList, error)
+	DueDisputes(now time.Time, limit int64) ([]model.Dispute, error)
+	TransitionDispute(dispute model.Dispute, from model.DisputeStatus, refund []model.Entry) error
+	CreateEvidence(evidence model.Evidence) error
+	Evidence(disputeUuid string) ([]model.Evidence, error)
+}
+
+// Bidders - finds the user the winning bidder of an auction bids for, who is the buyer
+type Bidders interface {
+	FindOne(uuid string) (bidermodel.Bidder, error)
+}
+
+type ServiceSettlement struct {
+	repo     Repository
+	provider PaymentProvider
+	fees     *FeeCalculator
+	storage  aws.Service
+	bidders  Bidders
+	disputes DisputesConfig
+	logger   *zap.Logger
+}
+
+// New - the dispute evidence is kept in storage, the buyers of the closed auctions are found through bidders
+func New(repo Repository, provider PaymentProvider, fees *FeeCalculator, storage aws.Service, bidders Bidders, disputes DisputesConfig, logger *zap.Logger) Service {
+
+	return &ServiceSettlement{repo: repo, provider: provider, fees: fees, storage: storage, bidders: bidders, disputes: disputes.WithDefaults(), logger: logger}
+}
+
+// Settle - opens the invoice of a won auction and posts the sale to the ledger: the buyer is debited the price,
+// the seller is credited the price minus the platform fee and the platform is credited the fee.
+// the fee is priced by the fee rules and the invoice records the rule it was priced by.
+// settling an auction twice returns the invoice of the first settlement
+func (s *ServiceSettlement) Settle(input model.SettleInput) (model.Invoice, error) {
+	if err := validateSettle(input); err != nil {
+		return model.Invoice{}, err
+	}
+
+	quote, err := s.fees.Quote(model.FeeInput{SellerUuid: input.SellerUuid, Category: input.Category, Price: input.Amount})
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	invoice := model.Invoice{
+		Uuid:        uuid.New().String(),
+		AuctionUuid: input.AuctionUuid,
+		BuyerUuid:   input.BuyerUuid,
+		SellerUuid:  input.SellerUuid,
+		Amount:      input.Amount.Amount,
+		Fee:         quote.Fee.Amount,
+		FeeRule:     quote.Rule,
+		Currency:    input.Amount.Currency,
+		Status:      model.Pending,
+	}
+
+	entries := saleEntries(invoice)
+	if err := model.Balanced(entries); err != nil {
+		return model.Invoice{}, err
+	}
+
+	result, err := s.repo.CreateInvoice(invoice, entries)
+	if err != nil {
+		s.logger.Error("ServiceSettlement.Settle failed creating invoice", zap.Any("input", input), zap.Error(err))
+		return model.Invoice{}, err
+	}
+
+	return result, nil
+}
+
+// GetInvoice - the invoice as seen by its buyer, its seller or an admin
+func (s *ServiceSettlement) GetInvoice(uuid string, viewerUuid string) (model.Invoice, error) {
+	result, err := s.invoice(uuid)
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	if !s.viewsInvoice(result, viewerUuid) {
+		return model.Invoice{}, model.ErrForbidden
+	}
+
+	return result, nil
+}
+
+// AuctionInvoice - the invoice of the auction as seen by its buyer, its seller or an admin
+func (s *ServiceSettlement) AuctionInvoice(auctionUuid string, viewerUuid string) (model.Invoice, error) {
+	result, err := s.repo.InvoiceByAuction(auctionUuid)
+
+	if err != nil {
+		s.logger.Error("ServiceSettlement.AuctionInvoice failed getting invoice", zap.String("auction", auctionUuid), zap.Error(err))
+		return model.Invoice{}, err
+	}
+
+	if !s.viewsInvoice(result, viewerUuid) {
+		return model.Invoice{}, model.ErrForbidden
+	}
+
+	return result, nil
+}
+
+func (s *ServiceSettlement) ListInvoices(input model.InvoiceListInput) (model.InvoiceList, error) {
+	if input.UserUuid == "" {
+		return model.InvoiceList{}, fmt.Errorf("%w: userUuid is required", model.ErrInvalidInput)
+	}
+
+	result, err := s.repo.ListInvoices(input)
+	if err != nil {
+		s.logger.Error("ServiceSettlement.ListInvoices failed listing invoices", zap.Any("input", input), zap.Error(err))
+		return model.InvoiceList{}, err
+	}
+
+	return result, nil
+}
+
+// Ledger - returns the ledger entries of an invoice in posting order to its buyer, its seller or an admin
+func (s *ServiceSettlement) Ledger(invoiceUuid string, viewerUuid string) ([]model.Entry, error) {
+	if _, err := s.GetInvoice(invoiceUuid, viewerUuid); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Entries(invoiceUuid)
+	if err != nil {
+		s.logger.Error("ServiceSettlement.Ledger failed listing entries", zap.String("invoice", invoiceUuid), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PayInvoice - charges the buyer through the payment provider and posts the payment to the ledger.
+// the invoice uuid is the idempotency key of the charge so a retried payment is never charged twice
+func (s *ServiceSettlement) PayInvoice(input model.PayInput) (model.Invoice, error) {
+	if input.InvoiceUuid == "" || input.PayerUuid == "" {
+		return model.Invoice{}, fmt.Errorf("%w: invoiceUuid and payerUuid are required", model.ErrInvalidInput)
+	}
+
+	invoice, err := s.invoice(input.InvoiceUuid)
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	if invoice.BuyerUuid != input.PayerUuid {
+		return model.Invoice{}, model.ErrForbidden
+	}
+
+	if invoice.Status == model.Paid {
+		return model.Invoice{}, model.ErrAlreadyPaid
+	}
+
+	charge, err := s.provider.Charge(model.ChargeInput{
+		IdempotencyKey: invoice.Uuid,
+		PayerUuid:      invoice.BuyerUuid,
+		Amount:         invoice.Money(),
+		Description:    "auction " + invoice.AuctionUuid,
+	})
+	if err != nil {
+		s.logger.Error("ServiceSettlement.PayInvoice failed charging buyer", zap.Any("input", input), zap.Error(err))
+		return model.Invoice{}, err
+	}
+
+	if charge.Status != model.ChargeSucceeded {
+		return model.Invoice{}, fmt.Errorf("%w: %s", model.ErrPaymentDeclined, charge.Reason)
+	}
+
+	paidAt := time.Now()
+	entries := paymentEntries(invoice, s.provider.Name(), charge.Reference)
+	if err = s.repo.MarkPaid(invoice.Uuid, charge.Reference, paidAt, entries); err != nil {
+		if !errors.Is(err, model.ErrAlreadyPaid) {
+			s.logger.Error("ServiceSettlement.PayInvoice failed marking invoice paid", zap.Any("input", input), zap.Error(err))
+		}
+		return model.Invoice{}, err
+	}
+
+	invoice.Status = model.Paid
+	invoice.PaymentReference = charge.Reference
+	invoice.PaidAt = &paidAt
+
+	return invoice, nil
+}
+
+func (s *ServiceSettlement) invoice(uuid string) (model.Invoice, error) {
+	result, err := s.repo.Invoice(uuid)
+
+	if err != nil {
+		s.logger.Error("ServiceSettlement.invoice failed getting invoice", zap.String("uuid", uuid), zap.Error(err))
+		return model.Invoice{}, err
+	}
+
+	return result, nil
+}
+
+// viewsInvoice - whether the user is the buyer or the seller of the invoice, or an admin
+func (s *ServiceSettlement) viewsInvoice(invoice model.Invoice, userUuid string) bool {
+	if userUuid == "" {
+		return false
+	}
+
+	return userUuid == invoice.BuyerUuid || userUuid == invoice.SellerUuid || s.isAdmin(userUuid)
+}
+
+// PreviewFee - prices the fee a sale would be charged, sellers use it to see their proceeds before listing
+func (s *ServiceSettlement) PreviewFee(input model.FeeInput) (model.FeeQuote, error) {
+	if input.SellerUuid == "" {
+		return model.FeeQuote{}, fmt.Errorf("%w: sellerUuid is required", model.ErrInvalidInput)
+	}
+
+	return s.fees.Quote(input)
+}
+
+func validateSettle(input model.SettleInput) error {
+	if input.AuctionUuid == "" || input.BuyerUuid == "" || input.SellerUuid == "" {
+		return fmt.Errorf("%w: auction, buyer and seller are required", model.ErrInvalidInput)
+	}
+
+	if input.Amount.Amount <= 0 || !input.Amount.Currency.Valid() {
+		return fmt.Errorf("%w: a positive amount in a known currency is required", model.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// saleEntries - the ledger transaction of a sale
+func saleEntries(invoice model.Invoice) []model.Entry {
+	transaction := uuid.New().String()
+	entry := func(account string, direction model.Direction, amount int64) model.Entry {
+		return model.Entry{
+			TransactionUuid: transaction,
+			InvoiceUuid:     invoice.Uuid,
+			Account:         account,
+			Direction:       direction,
+			Amount:          amount,
+			Currency:        invoice.Currency,
+			Memo:            "sale of auction " + invoice.AuctionUuid,
+		}
+	}
+
+	entries := []model.Entry{
+		entry(model.BuyerAccount(invoice.BuyerUuid), model.Debit, invoice.Amount),
+		entry(model.SellerAccount(invoice.SellerUuid), model.Credit, invoice.SellerProceeds()),
+	}
+
+	if invoice.Fee > 0 {
+		entries = append(entries, entry(model.PlatformFees, model.Credit, invoice.Fee))
+	}
+
+	return entries
+}
+
+// paymentEntries - the ledger transaction of the buyer paying the invoice through the provider
+func paymentEntries(invoice model.Invoice, provider string, reference string) []model.Entry {
+	transaction := uuid.New().String()
+	memo := "payment " + reference
+
+	return []model.Entry{
+		{
+			TransactionUuid: transaction,
+			InvoiceUuid:     invoice.Uuid,
+			Account:         model.PaymentsAccount(provider),
+			Direction:       model.Debit,
+			Amount:          invoice.Amount,
+			Currency:        invoice.Currency,
+			Memo:            memo,
+		},
+		{
+			TransactionUuid: transaction,
+			InvoiceUuid:     invoice.Uuid,
+			Account:         model.BuyerAccount(invoice.BuyerUuid),
+			Direction:       model.Credit,
+			Amount:          invoice.Amount,
+			Currency:        invoice.Currency,
+			Memo:            memo,
+		},
+	}
+}
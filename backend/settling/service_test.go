@@ -0,0 +1,315 @@
+package settling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+type MockRepository struct {
+	mock mock.Mock
+}
+
+func (m *MockRepository) CreateInvoice(invoice model.Invoice, entries []model.Entry) (model.Invoice, error) {
+	args := m.mock.Called(invoice, entries)
+	result, _ := args.Get(0).(model.Invoice)
+
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) Invoice(uuid string) (model.Invoice, error) {
+	args := m.mock.Called(uuid)
+
+	return args.Get(0).(model.Invoice), args.Error(1)
+}
+
+func (m *MockRepository) InvoiceByAuction(auctionUuid string) (model.Invoice, error) {
+	args := m.mock.Called(auctionUuid)
+
+	return args.Get(0).(model.Invoice), args.Error(1)
+}
+
+func (m *MockRepository) ListInvoices(input model.InvoiceListInput) (model.InvoiceList, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(model.InvoiceList), args.Error(1)
+}
+
+func (m *MockRepository) Entries(invoiceUuid string) ([]model.Entry, error) {
+	args := m.mock.Called(invoiceUuid)
+	entries, _ := args.Get(0).([]model.Entry)
+
+	return entries, args.Error(1)
+}
+
+func (m *MockRepository) MarkPaid(invoiceUuid string, reference string, paidAt time.Time, entries []model.Entry) error {
+	args := m.mock.Called(invoiceUuid, reference, paidAt, entries)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateDispute(dispute model.Dispute) error {
+	args := m.mock.Called(dispute)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) Dispute(uuid string) (model.Dispute, error) {
+	args := m.mock.Called(uuid)
+
+	return args.Get(0).(model.Dispute), args.Error(1)
+}
+
+func (m *MockRepository) ListDisputes(input model.DisputeListInput) (model.DisputeList, error) {
+	args := m.mock.Called(input)
+
+	return args.Get(0).(model.DisputeList), args.Error(1)
+}
+
+func (m *MockRepository) DueDisputes(now time.Time, limit int64) ([]model.Dispute, error) {
+	args := m.mock.Called(now, limit)
+	disputes, _ := args.Get(0).([]model.Dispute)
+
+	return disputes, args.Error(1)
+}
+
+func (m *MockRepository) TransitionDispute(dispute model.Dispute, from model.DisputeStatus, refund []model.Entry) error {
+	args := m.mock.Called(dispute, from, refund)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateEvidence(evidence model.Evidence) error {
+	args := m.mock.Called(evidence)
+
+	return args.Error(0)
+}
+
+func (m *MockRepository) Evidence(disputeUuid string) ([]model.Evidence, error) {
+	args := m.mock.Called(disputeUuid)
+	evidence, _ := args.Get(0).([]model.Evidence)
+
+	return evidence, args.Error(1)
+}
+
+// flatFees - a fee calculator without rules, every sale is charged basisPoints
+func flatFees(basisPoints int64) *FeeCalculator {
+	fees, _ := NewFeeCalculator(Config{FeeBasisPoints: basisPoints})
+
+	return fees
+}
+
+// echoInvoice - makes CreateInvoice return the invoice it was given
+func echoInvoice(repo *MockRepository) {
+	repo.mock.On("CreateInvoice", mock.Anything, mock.Anything).Return(nil, nil).Run(func(args mock.Arguments) {
+		repo.mock.ExpectedCalls[0].ReturnArguments = mock.Arguments{args.Get(0).(model.Invoice), nil}
+	})
+}
+
+func TestServiceSettlement_Settle(t *testing.T) {
+	repo := &MockRepository{}
+	echoInvoice(repo)
+	service := New(repo, NewFakeProvider(), flatFees(500), nil, nil, DisputesConfig{}, zap.NewNop())
+
+	invoice, err := service.Settle(model.SettleInput{
+		AuctionUuid: "auction-uuid",
+		BuyerUuid:   "buyer-uuid",
+		SellerUuid:  "seller-uuid",
+		Amount:      money.Money{Amount: 10050, Currency: money.EUR},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10050), invoice.Amount)
+	assert.Equal(t, int64(502), invoice.Fee)
+	assert.Equal(t, int64(9548), invoice.SellerProceeds())
+	assert.Equal(t, DefaultFeeRule, invoice.FeeRule)
+	assert.Equal(t, money.EUR, invoice.Currency)
+	assert.Equal(t, model.Pending, invoice.Status)
+
+	entries := repo.mock.Calls[0].Arguments.Get(1).([]model.Entry)
+	assert.NoError(t, model.Balanced(entries))
+	assert.Len(t, entries, 3)
+	assert.Equal(t, model.Entry{TransactionUuid: entries[0].TransactionUuid, InvoiceUuid: invoice.Uuid, Account: "buyer:buyer-uuid", Direction: model.Debit, Amount: 10050, Currency: money.EUR, Memo: "sale of auction auction-uuid"}, entries[0])
+	assert.Equal(t, "seller:seller-uuid", entries[1].Account)
+	assert.Equal(t, int64(9548), entries[1].Amount)
+	assert.Equal(t, model.PlatformFees, entries[2].Account)
+	assert.Equal(t, int64(502), entries[2].Amount)
+	for _, entry := range entries {
+		assert.Equal(t, entries[0].TransactionUuid, entry.TransactionUuid)
+	}
+}
+
+func TestServiceSettlement_SettleWithoutFee(t *testing.T) {
+	repo := &MockRepository{}
+	echoInvoice(repo)
+	service := New(repo, NewFakeProvider(), flatFees(0), nil, nil, DisputesConfig{}, zap.NewNop())
+
+	_, err := service.Settle(model.SettleInput{AuctionUuid: "auction-uuid", BuyerUuid: "buyer-uuid", SellerUuid: "seller-uuid", Amount: money.Money{Amount: 100, Currency: money.USD}})
+
+	assert.NoError(t, err)
+	entries := repo.mock.Calls[0].Arguments.Get(1).([]model.Entry)
+	assert.Len(t, entries, 2)
+	assert.NoError(t, model.Balanced(entries))
+}
+
+func TestServiceSettlement_SettleInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input model.SettleInput
+	}{
+		{"missing seller", model.SettleInput{AuctionUuid: "auction-uuid", BuyerUuid: "buyer-uuid", Amount: money.Money{Amount: 100, Currency: money.USD}}},
+		{"no amount", model.SettleInput{AuctionUuid: "auction-uuid", BuyerUuid: "buyer-uuid", SellerUuid: "seller-uuid", Amount: money.Money{Currency: money.USD}}},
+		{"unknown currency", model.SettleInput{AuctionUuid: "auction-uuid", BuyerUuid: "buyer-uuid", SellerUuid: "seller-uuid", Amount: money.Money{Amount: 100, Currency: "XYZ"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			service := New(repo, NewFakeProvider(), flatFees(0), nil, nil, DisputesConfig{}, zap.NewNop())
+
+			_, err := service.Settle(test.input)
+
+			assert.ErrorIs(t, err, model.ErrInvalidInput)
+			repo.mock.AssertNotCalled(t, "CreateInvoice", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func pendingInvoice() model.Invoice {
+	return model.Invoice{
+		Uuid:        "invoice-uuid",
+		AuctionUuid: "auction-uuid",
+		BuyerUuid:   "buyer-uuid",
+		SellerUuid:  "seller-uuid",
+		Amount:      1000,
+		Fee:         50,
+		Currency:    money.USD,
+		Status:      model.Pending,
+	}
+}
+
+func TestServiceSettlement_PayInvoice(t *testing.T) {
+	repo := &MockRepository{}
+	provider := NewFakeProvider()
+	service := New(repo, provider, flatFees(0), nil, nil, DisputesConfig{}, zap.NewNop())
+	repo.mock.On("Invoice", "invoice-uuid").Return(pendingInvoice(), nil)
+	repo.mock.On("MarkPaid", "invoice-uuid", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	invoice, err := service.PayInvoice(model.PayInput{InvoiceUuid: "invoice-uuid", PayerUuid: "buyer-uuid"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Paid, invoice.Status)
+	assert.NotEmpty(t, invoice.PaymentReference)
+	assert.NotNil(t, invoice.PaidAt)
+	assert.Equal(t, 1, provider.Charges())
+
+	call := repo.mock.Calls[1]
+	assert.Equal(t, invoice.PaymentReference, call.Arguments.Get(1))
+	entries := call.Arguments.Get(3).([]model.Entry)
+	assert.NoError(t, model.Balanced(entries))
+	assert.Equal(t, "payments:fake", entries[0].Account)
+	assert.Equal(t, model.Debit, entries[0].Direction)
+	assert.Equal(t, "buyer:buyer-uuid", entries[1].Account)
+	assert.Equal(t, model.Credit, entries[1].Direction)
+	assert.Equal(t, int64(1000), entries[1].Amount)
+}
+
+func TestServiceSettlement_PayInvoiceRejected(t *testing.T) {
+	paid := pendingInvoice()
+	paid.Status = model.Paid
+
+	tests := []struct {
+		name    string
+		invoice model.Invoice
+		payer   string
+		decline bool
+		wantErr error
+	}{
+		{name: "not the buyer", invoice: pendingInvoice(), payer: "seller-uuid", wantErr: model.ErrForbidden},
+		{name: "already paid", invoice: paid, payer: "buyer-uuid", wantErr: model.ErrAlreadyPaid},
+		{name: "declined", invoice: pendingInvoice(), payer: "buyer-uuid", decline: true, wantErr: model.ErrPaymentDeclined},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			provider := NewFakeProvider()
+			if test.decline {
+				provider.Decline(test.payer)
+			}
+			service := New(repo, provider, flatFees(0), nil, nil, DisputesConfig{}, zap.NewNop())
+			repo.mock.On("Invoice", "invoice-uuid").Return(test.invoice, nil)
+
+			_, err := service.PayInvoice(model.PayInput{InvoiceUuid: "invoice-uuid", PayerUuid: test.payer})
+
+			assert.ErrorIs(t, err, test.wantErr)
+			repo.mock.AssertNotCalled(t, "MarkPaid", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestServiceSettlement_LedgerOfParties(t *testing.T) {
+	tests := []struct {
+		name    string
+		viewer  string
+		wantErr error
+	}{
+		{"buyer", "buyer-uuid", nil},
+		{"seller", "seller-uuid", nil},
+		{"admin", "admin-uuid", nil},
+		{"stranger", "bidder-uuid", model.ErrForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			repo.mock.On("Invoice", "invoice-uuid").Return(pendingInvoice(), nil)
+			repo.mock.On("Entries", "invoice-uuid").Return(saleEntries(pendingInvoice()), nil)
+			service := New(repo, NewFakeProvider(), flatFees(0), &MockStorage{}, nil, disputesConfig, zap.NewNop())
+
+			_, err := service.Ledger("invoice-uuid", test.viewer)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			if test.wantErr != nil {
+				repo.mock.AssertNotCalled(t, "Entries", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestFakeProvider_ChargesOncePerKey(t *testing.T) {
+	provider := NewFakeProvider()
+	input := model.ChargeInput{IdempotencyKey: "invoice-uuid", PayerUuid: "buyer-uuid", Amount: money.Money{Amount: 1000, Currency: money.USD}}
+
+	first, err := provider.Charge(input)
+	assert.NoError(t, err)
+	second, err := provider.Charge(input)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, provider.Charges())
+
+	_, err = provider.Charge(model.ChargeInput{IdempotencyKey: "other"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestBalanced(t *testing.T) {
+	entries := []model.Entry{
+		{Account: "buyer:a", Direction: model.Debit, Amount: 100, Currency: money.USD},
+		{Account: "seller:b", Direction: model.Credit, Amount: 90, Currency: money.USD},
+	}
+
+	assert.ErrorIs(t, model.Balanced(entries), model.ErrUnbalanced)
+
+	entries = append(entries, model.Entry{Account: model.PlatformFees, Direction: model.Credit, Amount: 10, Currency: money.EUR})
+	assert.ErrorIs(t, model.Balanced(entries), money.ErrCurrencyMismatch)
+
+	entries[2].Currency = money.USD
+	assert.NoError(t, model.Balanced(entries))
+}
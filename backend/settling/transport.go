@@ -0,0 +1,395 @@
+package settling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/julienschmidt/httprouter"
+	"github.com/labstack/gommon/log"
+
+	"github.com/ireuven89/hello-world/backend/authenticating"
+	"github.com/ireuven89/hello-world/backend/money"
+	"github.com/ireuven89/hello-world/backend/settling/model"
+)
+
+// maxEvidenceMemory - the part of an evidence upload kept in memory while parsing, the rest goes to disk
+const maxEvidenceMemory = 1 << 20
+
+func NewTransport(s Service, router *httprouter.Router, verifier authenticating.Verifier) Transport {
+
+	transport := Transport{
+		router: router,
+		s:      s,
+	}
+	RegisterRoutes(router, s, verifier) // Register routes during initialization
+	return transport
+}
+
+type Transport struct {
+	router *httprouter.Router
+	s      Service
+}
+
+func (t *Transport) ListenAndServe(port string) {
+	log.Printf("Starting server on port %s...", port)
+	err := http.ListenAndServe(":"+port, t.router)
+	if err != nil {
+		log.Fatalf("Server failed to start: %v", err)
+	}
+}
+
+// RegisterRoutes - verifier checks the token of the requests, every request acts as the user of its token
+func RegisterRoutes(router *httprouter.Router, s Service, verifier authenticating.Verifier) {
+	options := []kithttp.ServerOption{
+		kithttp.ServerBefore(authenticating.VerifyActor(verifier)),
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	getInvoiceHandler := kithttp.NewServer(
+		MakeEndpointGetInvoice(s),
+		decodeGetInvoiceRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	auctionInvoiceHandler := kithttp.NewServer(
+		MakeEndpointAuctionInvoice(s),
+		decodeAuctionInvoiceRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listInvoicesHandler := kithttp.NewServer(
+		MakeEndpointListInvoices(s),
+		decodeListInvoicesRequest,
+		encodeListInvoicesResponse,
+		options...,
+	)
+
+	ledgerHandler := kithttp.NewServer(
+		MakeEndpointLedger(s),
+		decodeGetInvoiceRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	payInvoiceHandler := kithttp.NewServer(
+		MakeEndpointPayInvoice(s),
+		decodePayInvoiceRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	previewFeeHandler := kithttp.NewServer(
+		MakeEndpointPreviewFee(s),
+		decodePreviewFeeRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	openDisputeHandler := kithttp.NewServer(
+		MakeEndpointOpenDispute(s),
+		decodeOpenDisputeRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	getDisputeHandler := kithttp.NewServer(
+		MakeEndpointGetDispute(s),
+		decodeGetDisputeRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	listDisputesHandler := kithttp.NewServer(
+		MakeEndpointListDisputes(s),
+		decodeListDisputesRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	disputeActionHandler := kithttp.NewServer(
+		MakeEndpointDisputeAction(s),
+		decodeDisputeActionRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	addEvidenceHandler := kithttp.NewServer(
+		MakeEndpointAddEvidence(s),
+		decodeAddEvidenceRequest,
+		kithttp.EncodeJSONResponse,
+		options...,
+	)
+
+	router.Handler(http.MethodGet, "/invoices/:uuid", getInvoiceHandler)
+	router.Handler(http.MethodGet, "/invoices/:uuid/ledger", ledgerHandler)
+	router.Handler(http.MethodPost, "/invoices/:uuid/pay", payInvoiceHandler)
+	router.Handler(http.MethodGet, "/auctions/:uuid/invoice", auctionInvoiceHandler)
+	router.Handler(http.MethodGet, "/users/:userUuid/invoices", listInvoicesHandler)
+	router.Handler(http.MethodGet, "/fees/preview", previewFeeHandler)
+	router.Handler(http.MethodPost, "/invoices/:uuid/disputes", openDisputeHandler)
+	router.Handler(http.MethodGet, "/disputes/:uuid", getDisputeHandler)
+	router.Handler(http.MethodPost, "/disputes/:uuid/actions", disputeActionHandler)
+	router.Handler(http.MethodPost, "/disputes/:uuid/evidence", addEvidenceHandler)
+	router.Handler(http.MethodGet, "/users/:userUuid/disputes", listDisputesHandler)
+}
+
+func encodeError(ctx context.Context, err error, writer http.ResponseWriter) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrInvalidInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, authenticating.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, model.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, model.ErrAlreadyPaid):
+		status = http.StatusConflict
+	case errors.Is(err, model.ErrPaymentDeclined):
+		status = http.StatusPaymentRequired
+	case errors.Is(err, model.ErrDisputeExists), errors.Is(err, model.ErrDisputeClosed), errors.Is(err, model.ErrDisputeConflict):
+		status = http.StatusConflict
+	case errors.Is(err, model.ErrNotDisputable):
+		status = http.StatusUnprocessableEntity
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	json.NewEncoder(writer).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func decodeGetInvoiceRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	viewer, err := authenticating.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetInvoiceRequest{
+		Uuid:       httprouter.ParamsFromContext(ctx).ByName("uuid"),
+		ViewerUuid: viewer,
+	}, nil
+}
+
+func decodeAuctionInvoiceRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	viewer, err := authenticating.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return AuctionInvoiceRequest{
+		AuctionUuid: httprouter.ParamsFromContext(ctx).ByName("uuid"),
+		ViewerUuid:  viewer,
+	}, nil
+}
+
+// decodeListInvoicesRequest - a user lists the user's own invoices only
+func decodeListInvoicesRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.InvoiceListInput
+	queryParams := r.URL.Query()
+
+	if input.UserUuid, err = ownUser(ctx); err != nil {
+		return nil, err
+	}
+	input.Page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	input.Page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	return ListInvoicesRequest{
+		input: input,
+	}, nil
+}
+
+func encodeListInvoicesResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
+	res, ok := response.(ListInvoicesResponse)
+
+	if !ok {
+		return errors.New("encodeListInvoicesResponse failed to parse response")
+	}
+
+	formatted := map[string]interface{}{
+		"invoices": res.list.Invoices,
+		"total":    res.list.Total,
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(writer).Encode(formatted)
+}
+
+func decodePayInvoiceRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.PayInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.PayerUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.InvoiceUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return PayInvoiceRequest{
+		pay: input,
+	}, nil
+}
+
+// decodePreviewFeeRequest - the price is a decimal of the currency, the default currency when none is given
+func decodePreviewFeeRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	queryParams := r.URL.Query()
+
+	currency := money.Currency(queryParams.Get("currency"))
+	if currency == "" {
+		currency = money.Default
+	}
+
+	price, err := money.Parse(queryParams.Get("price"), currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrInvalidInput, err)
+	}
+
+	return PreviewFeeRequest{
+		input: model.FeeInput{
+			SellerUuid: queryParams.Get("sellerUuid"),
+			Category:   queryParams.Get("category"),
+			Price:      price,
+		},
+	}, nil
+}
+
+func decodeOpenDisputeRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.DisputeInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.BuyerUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.InvoiceUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return OpenDisputeRequest{
+		dispute: input,
+	}, nil
+}
+
+func decodeGetDisputeRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	viewer, err := authenticating.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetDisputeRequest{
+		Uuid:       httprouter.ParamsFromContext(ctx).ByName("uuid"),
+		ViewerUuid: viewer,
+	}, nil
+}
+
+// decodeListDisputesRequest - a user lists the user's own disputes only
+func decodeListDisputesRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.DisputeListInput
+	queryParams := r.URL.Query()
+
+	if input.UserUuid, err = ownUser(ctx); err != nil {
+		return nil, err
+	}
+	input.Status = model.DisputeStatus(queryParams.Get("status"))
+	input.Page.Offset, _ = strconv.ParseInt(queryParams.Get("offset"), 10, 64)
+	input.Page.Limit, _ = strconv.ParseInt(queryParams.Get("limit"), 10, 64)
+
+	return ListDisputesRequest{
+		input: input,
+	}, nil
+}
+
+func decodeDisputeActionRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	var input model.DisputeActionInput
+
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+
+	if input.ActorUuid, err = authenticating.Actor(ctx); err != nil {
+		return nil, err
+	}
+
+	input.DisputeUuid = httprouter.ParamsFromContext(ctx).ByName("uuid")
+
+	return DisputeActionRequest{
+		action: input,
+	}, nil
+}
+
+// decodeAddEvidenceRequest - reads the multipart "file" field into a temporary file, the uploader is the user of
+// the request. the size limit is enforced by the service
+func decodeAddEvidenceRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	uploader, err := authenticating.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.ParseMultipartForm(maxEvidenceMemory); err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrInvalidInput, err)
+	}
+
+	upload, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrInvalidInput, err)
+	}
+	defer upload.Close()
+
+	file, err := os.CreateTemp("", "evidence-*")
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := io.Copy(file, upload)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	return AddEvidenceRequest{
+		evidence: model.EvidenceInput{
+			DisputeUuid:  httprouter.ParamsFromContext(ctx).ByName("uuid"),
+			UploaderUuid: uploader,
+			Name:         header.Filename,
+			ContentType:  header.Header.Get("Content-Type"),
+			Size:         size,
+			File:         file,
+		},
+	}, nil
+}
+
+// ownUser - the user of the request, which must be the user of the route
+func ownUser(ctx context.Context) (string, error) {
+	userUuid, err := authenticating.Actor(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if userUuid != httprouter.ParamsFromContext(ctx).ByName("userUuid") {
+		return "", model.ErrForbidden
+	}
+
+	return userUuid, nil
+}
@@ -0,0 +1,82 @@
+package streaming
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Channel - the redis channel carrying the auction events between the server instances
+const Channel = "auction-events"
+
+const resubscribeDelay = 5 * time.Second
+
+type Broker interface {
+	Publish(channel string, message []byte) error
+	Subscribe(channel string, stop chan struct{}, handle func(message []byte)) error
+}
+
+type Publisher interface {
+	Publish(message []byte) error
+}
+
+// Bus - carries the auction events through redis pub/sub so the subscribers of every instance receive them
+type Bus struct {
+	broker Broker
+	hub    *Hub
+	logger *zap.Logger
+}
+
+func NewBus(broker Broker, hub *Hub, logger *zap.Logger) *Bus {
+
+	return &Bus{broker: broker, hub: hub, logger: logger}
+}
+
+func (b *Bus) Publish(message []byte) error {
+
+	return b.broker.Publish(Channel, message)
+}
+
+// Run - feeds the hub from the redis channel until stop is closed, subscribing again when the connection drops
+func (b *Bus) Run(stop chan struct{}) {
+	for {
+		err := b.broker.Subscribe(Channel, stop, b.hub.Dispatch)
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		b.logger.Error("Bus.Run lost the event subscription", zap.Error(err))
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// Fanout - publishes every message to all the publishers, a failing publisher does not stop the others
+type Fanout struct {
+	publishers []Publisher
+}
+
+func NewFanout(publishers ...Publisher) *Fanout {
+
+	return &Fanout{publishers: publishers}
+}
+
+func (f *Fanout) Publish(message []byte) error {
+	var errs []error
+
+	for _, publisher := range f.publishers {
+		if err := publisher.Publish(message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package streaming
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockBroker struct {
+	mock     mock.Mock
+	messages [][]byte
+}
+
+func (m *MockBroker) Publish(channel string, message []byte) error {
+	args := m.mock.Called(channel, message)
+
+	return args.Error(0)
+}
+
+func (m *MockBroker) Subscribe(channel string, stop chan struct{}, handle func(message []byte)) error {
+	args := m.mock.Called(channel)
+
+	for _, message := range m.messages {
+		handle(message)
+	}
+	close(stop)
+
+	return args.Error(0)
+}
+
+type MockPublisher struct {
+	mock mock.Mock
+}
+
+func (m *MockPublisher) Publish(message []byte) error {
+	args := m.mock.Called(message)
+
+	return args.Error(0)
+}
+
+func TestBus_Run(t *testing.T) {
+	message := encodeEvent(t, "auction-a")
+	broker := &MockBroker{messages: [][]byte{message}}
+	broker.mock.On("Subscribe", Channel).Return(nil)
+	hub := NewHub(zap.NewNop())
+	events, unsubscribe := hub.Subscribe("auction-a")
+	defer unsubscribe()
+
+	NewBus(broker, hub, zap.NewNop()).Run(make(chan struct{}))
+
+	assert.Equal(t, message, <-events)
+}
+
+func TestBus_Publish(t *testing.T) {
+	broker := &MockBroker{}
+	broker.mock.On("Publish", Channel, []byte("event")).Return(nil)
+
+	err := NewBus(broker, NewHub(zap.NewNop()), zap.NewNop()).Publish([]byte("event"))
+
+	assert.NoError(t, err)
+	broker.mock.AssertExpectations(t)
+}
+
+func TestFanout_Publish(t *testing.T) {
+	failing := &MockPublisher{}
+	failing.mock.On("Publish", []byte("event")).Return(errors.New("rabbit is down"))
+	working := &MockPublisher{}
+	working.mock.On("Publish", []byte("event")).Return(nil)
+
+	err := NewFanout(failing, working).Publish([]byte("event"))
+
+	assert.Error(t, err)
+	working.mock.AssertCalled(t, "Publish", []byte("event"))
+}
@@ -0,0 +1,100 @@
+package streaming
+
+import (
+	"encoding/json"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+	bidermodel "github.com/ireuven89/hello-world/backend/bider/model"
+)
+
+const subscriberBuffer = 16
+
+// Hub - keeps the live subscribers of this instance and hands them the events of their auction
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan []byte]struct{}
+	logger      *zap.Logger
+}
+
+func NewHub(logger *zap.Logger) *Hub {
+
+	return &Hub{
+		subscribers: map[string]map[chan []byte]struct{}{},
+		logger:      logger,
+	}
+}
+
+// Subscribe - registers a subscriber of the auction, the returned func must be called once the subscriber is gone
+func (h *Hub) Subscribe(auctionUuid string) (<-chan []byte, func()) {
+	ch := make(chan []byte, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[auctionUuid] == nil {
+		h.subscribers[auctionUuid] = map[chan []byte]struct{}{}
+	}
+	h.subscribers[auctionUuid][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.subscribers[auctionUuid][ch]; !ok {
+			return
+		}
+
+		delete(h.subscribers[auctionUuid], ch)
+		if len(h.subscribers[auctionUuid]) == 0 {
+			delete(h.subscribers, auctionUuid)
+		}
+		close(ch)
+	}
+}
+
+// Dispatch - sends an auction event to the subscribers of its auction, with the bidders masked.
+// subscribers which fall behind lose the event rather than blocking the others
+func (h *Hub) Dispatch(message []byte) {
+	var event model.Event
+
+	if err := json.Unmarshal(message, &event); err != nil || event.AuctionUuid == "" {
+		h.logger.Error("Hub.Dispatch failed decoding event", zap.ByteString("message", message), zap.Error(err))
+		return
+	}
+
+	message, err := json.Marshal(public(event))
+	if err != nil {
+		h.logger.Error("Hub.Dispatch failed encoding event", zap.String("auction", event.AuctionUuid), zap.Error(err))
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[event.AuctionUuid] {
+		select {
+		case ch <- message:
+		default:
+			h.logger.Warn("Hub.Dispatch dropped event for a slow subscriber", zap.String("auction", event.AuctionUuid))
+		}
+	}
+}
+
+// public - the event as the stream shows it, the bidders are masked like in the bid history
+func public(event model.Event) model.Event {
+	if event.WinnerUuid != "" {
+		event.WinnerUuid = bidermodel.MaskIdentity(event.WinnerUuid)
+	}
+
+	if event.PreviousWinnerUuid != "" {
+		event.PreviousWinnerUuid = bidermodel.MaskIdentity(event.PreviousWinnerUuid)
+	}
+
+	if event.BidderUuid != "" {
+		event.BidderUuid = bidermodel.MaskIdentity(event.BidderUuid)
+	}
+
+	return event
+}
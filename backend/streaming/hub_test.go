@@ -0,0 +1,77 @@
+package streaming
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/ireuven89/hello-world/backend/auction/model"
+)
+
+func encodeEvent(t *testing.T, auctionUuid string) []byte {
+	message, err := json.Marshal(model.Event{Type: model.EventBid, AuctionUuid: auctionUuid, WinningPrice: 150})
+	assert.NoError(t, err)
+
+	return message
+}
+
+func TestHub_Dispatch(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	first, unsubscribeFirst := hub.Subscribe("auction-a")
+	other, unsubscribeOther := hub.Subscribe("auction-b")
+	defer unsubscribeOther()
+
+	message := encodeEvent(t, "auction-a")
+	hub.Dispatch(message)
+
+	assert.Equal(t, message, <-first)
+	assert.Len(t, other, 0)
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	_, open := <-first
+	assert.False(t, open)
+
+	hub.Dispatch(message)
+	assert.Empty(t, hub.subscribers["auction-a"])
+}
+
+func TestHub_DispatchSlowSubscriber(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	events, unsubscribe := hub.Subscribe("auction-a")
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer+5; i++ {
+		hub.Dispatch(encodeEvent(t, "auction-a"))
+	}
+
+	assert.Len(t, events, subscriberBuffer)
+}
+
+func TestHub_DispatchInvalidMessage(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	events, unsubscribe := hub.Subscribe("auction-a")
+	defer unsubscribe()
+
+	hub.Dispatch([]byte("not an event"))
+
+	assert.Len(t, events, 0)
+}
+
+func TestHub_DispatchMasksBidders(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	events, unsubscribe := hub.Subscribe("auction-a")
+	defer unsubscribe()
+
+	message, err := json.Marshal(model.Event{Type: model.EventBid, AuctionUuid: "auction-a", WinnerUuid: "bidder-uuid-a", PreviousWinnerUuid: "bidder-uuid-b", BidderUuid: "bidder-uuid-c"})
+	assert.NoError(t, err)
+	hub.Dispatch(message)
+
+	var event model.Event
+	assert.NoError(t, json.Unmarshal(<-events, &event))
+	assert.Equal(t, "bi***-a", event.WinnerUuid)
+	assert.Equal(t, "bi***-b", event.PreviousWinnerUuid)
+	assert.Equal(t, "bi***-c", event.BidderUuid)
+}
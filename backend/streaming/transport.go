@@ -0,0 +1,97 @@
+package streaming
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+const keepAliveInterval = 15 * time.Second
+
+// RegisterRoutes - the live auction stream, over websocket and over server sent events for clients without websocket
+func RegisterRoutes(e *echo.Echo, hub *Hub) {
+	group := e.Group("api/v1")
+
+	group.GET("/auctions/:auctionUuid/stream", WebSocketHandler(hub))
+	group.GET("/auctions/:auctionUuid/events", SSEHandler(hub))
+}
+
+// WebSocketHandler - pushes the auction events to the socket until the client goes away
+func WebSocketHandler(hub *Hub) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		auctionUuid := c.Param("auctionUuid")
+
+		websocket.Handler(func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			events, unsubscribe := hub.Subscribe(auctionUuid)
+			defer unsubscribe()
+
+			gone := make(chan struct{})
+			go func() {
+				defer close(gone)
+				var discard string
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			for {
+				select {
+				case <-gone:
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					if err := websocket.Message.Send(ws, string(event)); err != nil {
+						return
+					}
+				}
+			}
+		}).ServeHTTP(c.Response(), c.Request())
+
+		return nil
+	}
+}
+
+// SSEHandler - streams the auction events as server sent events until the request is done
+func SSEHandler(hub *Hub) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		auctionUuid := c.Param("auctionUuid")
+		writer := c.Response()
+
+		writer.Header().Set(echo.HeaderContentType, "text/event-stream")
+		writer.Header().Set(echo.HeaderCacheControl, "no-cache")
+		writer.Header().Set(echo.HeaderConnection, "keep-alive")
+		writer.WriteHeader(http.StatusOK)
+		writer.Flush()
+
+		events, unsubscribe := hub.Subscribe(auctionUuid)
+		defer unsubscribe()
+
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-c.Request().Context().Done():
+				return nil
+			case <-keepAlive.C:
+				if _, err := fmt.Fprint(writer, ": keep-alive\n\n"); err != nil {
+					return nil
+				}
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+				if _, err := fmt.Fprintf(writer, "data: %s\n\n", event); err != nil {
+					return nil
+				}
+			}
+			writer.Flush()
+		}
+	}
+}
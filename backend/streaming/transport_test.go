@@ -0,0 +1,68 @@
+package streaming
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *Hub) {
+	hub := NewHub(zap.NewNop())
+	e := echo.New()
+	RegisterRoutes(e, hub)
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return server, hub
+}
+
+// waitForSubscriber - the handlers subscribe once the connection is up, events sent before that are not replayed
+func waitForSubscriber(t *testing.T, hub *Hub, auctionUuid string) {
+	assert.Eventually(t, func() bool {
+		hub.mu.RLock()
+		defer hub.mu.RUnlock()
+		return len(hub.subscribers[auctionUuid]) > 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSSEHandler(t *testing.T) {
+	server, hub := newTestServer(t)
+
+	res, err := http.Get(server.URL + "/api/v1/auctions/auction-a/events")
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	waitForSubscriber(t, hub, "auction-a")
+	message := encodeEvent(t, "auction-a")
+	hub.Dispatch(message)
+
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "data: "+string(message), strings.TrimSpace(line))
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	server, hub := newTestServer(t)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/auctions/auction-a/stream"
+
+	ws, err := websocket.Dial(url, "", server.URL)
+	assert.NoError(t, err)
+	defer ws.Close()
+
+	waitForSubscriber(t, hub, "auction-a")
+	message := encodeEvent(t, "auction-a")
+	hub.Dispatch(message)
+
+	var received string
+	assert.NoError(t, websocket.Message.Receive(ws, &received))
+	assert.Equal(t, string(message), received)
+}
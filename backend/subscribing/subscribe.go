@@ -12,8 +12,12 @@ import (
 
 type SService interface {
 	Subscribe(stop chan struct{}) error
+	AddHandler(handler Handler)
 }
 
+// Handler - handles the body of a consumed message
+type Handler func(message []byte) error
+
 // AMQPConnection defines the interface for amqp.Connection
 type AMQPConnection interface {
 	Channel() (*amqp.Channel, error)
@@ -26,11 +30,12 @@ type AMQPChannel interface {
 }
 
 type Subscriber struct {
-	conn    AMQPConnection
-	sub     *rabbit.Subscriber
-	logger  *zap.Logger
-	queue   *amqp.Queue
-	channel AMQPChannel
+	conn     AMQPConnection
+	sub      *rabbit.Subscriber
+	logger   *zap.Logger
+	queue    *amqp.Queue
+	channel  AMQPChannel
+	handlers []Handler
 }
 
 func New(logger *zap.Logger) (SService, error) {
@@ -60,6 +65,11 @@ func New(logger *zap.Logger) (SService, error) {
 	return client, nil
 }
 
+// AddHandler - registers a handler called with every consumed message, handlers must be added before Subscribe
+func (c *Subscriber) AddHandler(handler Handler) {
+	c.handlers = append(c.handlers, handler)
+}
+
 func (c *Subscriber) Subscribe(stop chan struct{}) error {
 
 	messages, err := c.channel.Consume(c.queue.Name, "",
@@ -78,6 +88,7 @@ func (c *Subscriber) Subscribe(stop chan struct{}) error {
 	go func() {
 		for d := range messages {
 			c.logger.Info("Received message:", zap.Any("body", string(d.Body)))
+			c.handle(d.Body)
 		}
 	}()
 
@@ -94,3 +105,11 @@ func (c *Subscriber) Subscribe(stop chan struct{}) error {
 
 	return nil
 }
+
+func (c *Subscriber) handle(message []byte) {
+	for _, handler := range c.handlers {
+		if err := handler(message); err != nil {
+			c.logger.Error("Subscriber.handle failed handling message", zap.String("body", string(message)), zap.Error(err))
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package subscribing
 
 import (
+	"errors"
+	"testing"
+
 	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
 )
 
 type MockAMQPChannel struct {
@@ -28,6 +33,24 @@ func (m *MockAMQPConnection) Channel() (*amqp.Channel, error) {
 	return args.Get(0).(*amqp.Channel), args.Error(1)
 }
 
+func TestSubscriber_HandleCallsEveryHandler(t *testing.T) {
+	client := &Subscriber{logger: zap.NewNop()}
+	var received []string
+
+	client.AddHandler(func(message []byte) error {
+		received = append(received, "first:"+string(message))
+		return errors.New("failed")
+	})
+	client.AddHandler(func(message []byte) error {
+		received = append(received, "second:"+string(message))
+		return nil
+	})
+
+	client.handle([]byte("test message"))
+
+	assert.Equal(t, []string{"first:test message", "second:test message"}, received)
+}
+
 /*
 func TestSubscribe(t *testing.T) {
 	// Mock the logger
@@ -9,21 +9,17 @@ import (
 
 func MakeEndpointGetUser(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
-		req, ok := request.(string)
+		req, ok := request.(GetUserRequest)
 		if !ok {
 			return nil, fmt.Errorf("MakeEndpointGetUser failed cast request")
 		}
 
-		user, err := s.GetUser(req)
+		user, err := s.GetUser(req.uuid)
 		if err != nil {
 			return nil, fmt.Errorf("MakeEndpointGetUser: %v", err)
 		}
 
-		return model.UserResponse{
-			Name:        user.Name,
-			Uuid:        user.Name,
-			Description: user.Name,
-		}, nil
+		return GetUserResponse{userResponse(user)}, nil
 	}
 }
 
@@ -41,14 +37,10 @@ func MakeEndpointGetUsers(s Service) endpoint.Endpoint {
 		}
 
 		for _, user := range users {
-			result = append(result, model.UserResponse{
-				Name:        user.Name,
-				Uuid:        user.Name,
-				Description: user.Name,
-			})
+			result = append(result, userResponse(user))
 		}
 
-		return result, nil
+		return ListUserResponse{users: result}, nil
 	}
 }
 
@@ -106,3 +98,36 @@ func MakeEndpointDeleteUser(s Service) endpoint.Endpoint {
 		return "", nil
 	}
 }
+
+type GetReputationRequest struct {
+	uuid string
+}
+
+func MakeEndpointGetReputation(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetReputationRequest)
+		if !ok {
+			return nil, fmt.Errorf("MakeEndpointGetReputation failed cast request")
+		}
+
+		result, err := s.GetReputation(req.uuid)
+		if err != nil {
+			return nil, fmt.Errorf("MakeEndpointGetReputation: %v", err)
+		}
+
+		return result, nil
+	}
+}
+
+// userResponse - the user as shown by the api, with the user's reputation
+func userResponse(user model.User) model.UserResponse {
+
+	return model.UserResponse{
+		Name:        user.Name,
+		Uuid:        user.Uuid,
+		Region:      user.Region,
+		Description: user.Name,
+		RatingCount: user.RatingCount,
+		RatingScore: user.RatingScore,
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"github.com/ireuven89/hello-world/backend/routes"
 )
 
+// User - RatingCount and RatingScore are the reputation of the user, the number of ratings received
+// as a seller and as a buyer together and their average, as last published by the auction service
 type User struct {
-	ID     int    `json:"-" db:"id"`
-	Uuid   string `json:"uuid" db:"uuid"`
-	Name   string `json:"name" db:"name"`
-	Region string `json:"region" db:"region"`
+	ID          int     `json:"-" db:"id"`
+	Uuid        string  `json:"uuid" db:"uuid"`
+	Name        string  `json:"name" db:"name"`
+	Region      string  `json:"region" db:"region"`
+	RatingCount int64   `json:"ratingCount" db:"rating_count"`
+	RatingScore float64 `json:"ratingScore" db:"rating_score"`
 }
 
 func (u *User) IsEmpty() bool {
@@ -35,8 +39,33 @@ type DeleteUserInput struct {
 }
 
 type UserResponse struct {
-	Name        string `json:"name"`
-	Uuid        string `json:"uuid"`
-	Region      string `json:"region"`
-	Description string `json:"description"`
+	Name        string  `json:"name"`
+	Uuid        string  `json:"uuid"`
+	Region      string  `json:"region"`
+	Description string  `json:"description"`
+	RatingCount int64   `json:"ratingCount"`
+	RatingScore float64 `json:"ratingScore"`
+}
+
+// ReputationInput - the reputation of a user as published by the auction service
+type ReputationInput struct {
+	UserUuid    string
+	Count       int64
+	Score       float64
+	SellerCount int64
+	SellerScore float64
+	BuyerCount  int64
+	BuyerScore  float64
+}
+
+// Reputation - the ratings the user received after the user's sold auctions, overall, as a seller and as a buyer,
+// as last published by the auction service. a score is the average rating
+type Reputation struct {
+	UserUuid    string  `json:"userUuid" db:"uuid"`
+	Count       int64   `json:"count" db:"rating_count"`
+	Score       float64 `json:"score" db:"rating_score"`
+	SellerCount int64   `json:"sellerCount" db:"seller_count"`
+	SellerScore float64 `json:"sellerScore" db:"seller_score"`
+	BuyerCount  int64   `json:"buyerCount" db:"buyer_count"`
+	BuyerScore  float64 `json:"buyerScore" db:"buyer_score"`
 }
@@ -0,0 +1,41 @@
+package users
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	auctionmodel "github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/users/model"
+)
+
+// HandleEvent - keeps the reputation of the users rated after their sold auctions, the other events are ignored
+func (s *service) HandleEvent(message []byte) error {
+	var event auctionmodel.Event
+
+	if err := json.Unmarshal(message, &event); err != nil {
+		s.logger.Error("service.HandleEvent failed decoding event", zap.ByteString("message", message), zap.Error(err))
+		return err
+	}
+
+	if event.Type != auctionmodel.EventFeedback || event.Reputation == nil {
+		return nil
+	}
+
+	err := s.userRepository.SetReputation(model.ReputationInput{
+		UserUuid:    event.Reputation.UserUuid,
+		Count:       event.Reputation.Count,
+		Score:       event.Reputation.Score,
+		SellerCount: event.Reputation.SellerCount,
+		SellerScore: event.Reputation.SellerScore,
+		BuyerCount:  event.Reputation.BuyerCount,
+		BuyerScore:  event.Reputation.BuyerScore,
+	})
+
+	if err != nil {
+		s.logger.Error("service.HandleEvent failed setting reputation", zap.Any("event", event), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
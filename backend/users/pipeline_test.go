@@ -0,0 +1,40 @@
+package users
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	auctionmodel "github.com/ireuven89/hello-world/backend/auction/model"
+	"github.com/ireuven89/hello-world/backend/users/model"
+)
+
+func TestService_HandleFeedbackEvent(t *testing.T) {
+	repo := &MockUserRepository{}
+	repo.mock.On("SetReputation", mock.Anything).Return(nil)
+	s := New(zap.NewNop(), repo)
+
+	reputation := auctionmodel.Reputation{UserUuid: "seller-uuid"}
+	reputation.Add(auctionmodel.Seller, 5)
+	reputation.Add(auctionmodel.Seller, 4)
+	reputation.Add(auctionmodel.Buyer, 3)
+	message, err := json.Marshal(auctionmodel.Event{Type: auctionmodel.EventFeedback, AuctionUuid: "auction-uuid", Reputation: &reputation})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.HandleEvent(message))
+	repo.mock.AssertCalled(t, "SetReputation", model.ReputationInput{UserUuid: "seller-uuid", Count: 3, Score: 4, SellerCount: 2, SellerScore: 4.5, BuyerCount: 1, BuyerScore: 3})
+}
+
+func TestService_HandleOtherEvents(t *testing.T) {
+	repo := &MockUserRepository{}
+	s := New(zap.NewNop(), repo)
+
+	message, err := json.Marshal(auctionmodel.Event{Type: auctionmodel.EventClosed, AuctionUuid: "auction-uuid", Status: auctionmodel.Sold})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.HandleEvent(message))
+	repo.mock.AssertNotCalled(t, "SetReputation", mock.Anything)
+}
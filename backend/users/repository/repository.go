@@ -36,6 +36,25 @@ func New(db *sqlz.DB, redis Redis, logger *zap.Logger) *UserRepository {
 
 const redisQueryTTl = time.Minute * 3
 
+var userColumns = []string{
+	"id",
+	"uuid",
+	"name",
+	"region",
+	"rating_count",
+	"rating_score",
+}
+
+var reputationColumns = []string{
+	"uuid",
+	"rating_count",
+	"rating_score",
+	"seller_count",
+	"seller_score",
+	"buyer_count",
+	"buyer_score",
+}
+
 // ListUsers - this method queries users from DB
 func (r *UserRepository) ListUsers(input model.UserFetchInput) ([]model.User, error) {
 	var result []model.User
@@ -48,11 +67,7 @@ func (r *UserRepository) ListUsers(input model.UserFetchInput) ([]model.User, er
 		return cachedResult.([]model.User), nil
 	}
 
-	q := r.db.Select(
-		"id",
-		"uuid",
-		"name",
-		"region").
+	q := r.db.Select(userColumns...).
 		From(dbmodel.Users)
 
 	var whereClauses []sqlz.WhereCondition
@@ -96,11 +111,7 @@ func (r *UserRepository) FindUser(uuid string) (model.User, error) {
 		return cachedResult.(model.User), nil
 	}
 
-	q := r.db.Select(
-		"id",
-		"uuid",
-		"name",
-		"region").
+	q := r.db.Select(userColumns...).
 		From(dbmodel.Users).
 		Where(sqlz.Eq("uuid", uuid))
 
@@ -179,3 +190,46 @@ func (r *UserRepository) Delete(uuid string) error {
 
 	return nil
 }
+
+// SetReputation - this method stores the reputation of the user unless a newer one, counting more ratings,
+// is already stored. ratings are never removed so the count only grows
+func (r *UserRepository) SetReputation(input model.ReputationInput) error {
+	q := r.db.Update(dbmodel.Users).
+		SetMap(map[string]interface{}{
+			"rating_count": input.Count,
+			"rating_score": input.Score,
+			"seller_count": input.SellerCount,
+			"seller_score": input.SellerScore,
+			"buyer_count":  input.BuyerCount,
+			"buyer_score":  input.BuyerScore,
+		}).
+		Where(sqlz.Eq("uuid", input.UserUuid), sqlz.Lt("rating_count", input.Count))
+
+	utils.New().DebugUpdate(q, "update users reputation")
+
+	if _, err := q.Exec(); err != nil {
+		r.logger.Error("UserRepository.SetReputation failed updating reputation", zap.String("user", input.UserUuid), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Reputation - this method queries the reputation of the user, a user who was never rated has an empty one and
+// an unknown user is sql.ErrNoRows
+func (r *UserRepository) Reputation(uuid string) (model.Reputation, error) {
+	var result model.Reputation
+
+	q := r.db.Select(reputationColumns...).
+		From(dbmodel.Users).
+		Where(sqlz.Eq("uuid", uuid))
+
+	utils.New().DebugSelect(q, "get user reputation")
+
+	if err := q.GetRow(&result); err != nil {
+		r.logger.Error("UserRepository.Reputation failed getting reputation", zap.String("user", uuid), zap.Error(err))
+		return model.Reputation{}, err
+	}
+
+	return result, nil
+}
@@ -58,9 +58,9 @@ func TestRepository_FindUserWithoutCaching(t *testing.T) {
 	repo := New(mockSqlz.sqlz, mockRedis, logger)
 
 	cachedQuery := fmt.Sprintf("FindUser:%s", "uuid")
-	expectedQuery := `SELECT id, uuid, name, region FROM users WHERE uuid = ?`
-	rows := sqlmock.NewRows([]string{"id", "uuid", "name", "region"}).
-		AddRow(1, "1234", "John", "US")
+	expectedQuery := `SELECT id, uuid, name, region, rating_count, rating_score FROM users WHERE uuid = ?`
+	rows := sqlmock.NewRows([]string{"id", "uuid", "name", "region", "rating_count", "rating_score"}).
+		AddRow(1, "1234", "John", "US", 0, 0)
 	expectedResult := model.User{
 		ID:     1,
 		Uuid:   "1234",
@@ -95,9 +95,9 @@ func TestRepository_GetWithCaching(t *testing.T) {
 	repo := New(mockSqlz.sqlz, mockRedis, logger)
 
 	cachedQuery := fmt.Sprintf("FindUser:%s", "uuid")
-	expectedQuery := `SELECT id, uuid, name, region FROM users WHERE uuid = ?`
-	rows := sqlmock.NewRows([]string{"id", "uuid", "name", "region"}).
-		AddRow(1, "1234", "John", "US")
+	expectedQuery := `SELECT id, uuid, name, region, rating_count, rating_score FROM users WHERE uuid = ?`
+	rows := sqlmock.NewRows([]string{"id", "uuid", "name", "region", "rating_count", "rating_score"}).
+		AddRow(1, "1234", "John", "US", 0, 0)
 	cachedUser := model.User{
 		ID:     1,
 		Uuid:   "1234",
@@ -128,9 +128,9 @@ func TestUserRepository_ListUsersWithCaching(t *testing.T) {
 	repo := New(mockSqlz.sqlz, mockRedis, logger)
 
 	cachedQuery := fmt.Sprintf("ListUsers:%s%s%s%v%v", input.Region, input.Name, input.Uuid, input.Page, input.Size)
-	expectedQuery := `SELECT id, uuid, name, region FROM users WHERE name = ?`
-	rows := sqlmock.NewRows([]string{"id", "uuid", "name", "region"}).
-		AddRow(1, "1234", "name", "US")
+	expectedQuery := `SELECT id, uuid, name, region, rating_count, rating_score FROM users WHERE name = ?`
+	rows := sqlmock.NewRows([]string{"id", "uuid", "name", "region", "rating_count", "rating_score"}).
+		AddRow(1, "1234", "name", "US", 0, 0)
 	cachedUser := []model.User{{
 		ID:     1,
 		Uuid:   "1234",
@@ -225,3 +225,49 @@ func TestUserRepository_Upsert_Update(t *testing.T) {
 	assert.Equal(t, input.Uuid, id)               // Ensure the returned ID matches the UUID
 	assert.NoError(t, mock.ExpectationsWereMet()) // Ensure mock expectations were met
 }
+
+func TestUserRepository_SetReputation(t *testing.T) {
+	mockDb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to initialize mock DB: %v", err)
+	}
+	defer mockDb.Close()
+
+	repo := &UserRepository{
+		db:     sqlz.New(mockDb, "mysql"),
+		logger: zap.NewNop(),
+	}
+
+	// an older reputation, counting fewer ratings, is overwritten and a newer one is kept
+	mock.ExpectExec(`UPDATE users SET buyer_count = \?, buyer_score = \?, rating_count = \?, rating_score = \?, seller_count = \?, seller_score = \? WHERE uuid = \? AND rating_count < \?`).
+		WithArgs(1, 3.0, 3, 4.33, 2, 5.0, "user-uuid", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SetReputation(model.ReputationInput{UserUuid: "user-uuid", Count: 3, Score: 4.33, SellerCount: 2, SellerScore: 5, BuyerCount: 1, BuyerScore: 3})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Reputation(t *testing.T) {
+	mockDb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to initialize mock DB: %v", err)
+	}
+	defer mockDb.Close()
+
+	repo := &UserRepository{
+		db:     sqlz.New(mockDb, "mysql"),
+		logger: zap.NewNop(),
+	}
+
+	mock.ExpectQuery(`SELECT uuid, rating_count, rating_score, seller_count, seller_score, buyer_count, buyer_score FROM users WHERE uuid = \?`).
+		WithArgs("user-uuid").
+		WillReturnRows(sqlmock.NewRows(reputationColumns).AddRow("user-uuid", 3, 4.33, 2, 5.0, 1, 3.0))
+
+	result, err := repo.Reputation("user-uuid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Reputation{UserUuid: "user-uuid", Count: 3, Score: 4.33, SellerCount: 2, SellerScore: 5, BuyerCount: 1, BuyerScore: 3}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
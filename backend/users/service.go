@@ -11,6 +11,8 @@ type Service interface {
 	CreateUser(input model.UserUpsertInput) (string, error)
 	UpdateUser(input model.UserUpsertInput) error
 	DeleteUser(uuid string) error
+	GetReputation(uuid string) (model.Reputation, error)
+	HandleEvent(message []byte) error
 }
 
 type UserRepository interface {
@@ -18,6 +20,8 @@ type UserRepository interface {
 	FindUser(uuid string) (model.User, error)
 	Upsert(input model.UserUpsertInput) (string, error)
 	Delete(uuid string) error
+	SetReputation(input model.ReputationInput) error
+	Reputation(uuid string) (model.Reputation, error)
 }
 
 type service struct {
@@ -83,3 +87,15 @@ func (s *service) DeleteUser(uuid string) error {
 
 	return nil
 }
+
+// GetReputation - the reputation of the user, overall, as a seller and as a buyer
+func (s *service) GetReputation(uuid string) (model.Reputation, error) {
+	result, err := s.userRepository.Reputation(uuid)
+
+	if err != nil {
+		s.logger.Error("failed to retrieve user reputation", zap.Error(err))
+		return model.Reputation{}, err
+	}
+
+	return result, nil
+}
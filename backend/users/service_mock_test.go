@@ -42,6 +42,18 @@ func (m *MockUserRepository) Delete(uuid string) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) SetReputation(input model.ReputationInput) error {
+	args := m.mock.Called(input)
+
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Reputation(uuid string) (model.Reputation, error) {
+	args := m.mock.Called(uuid)
+
+	return args.Get(0).(model.Reputation), args.Error(1)
+}
+
 func (ms *MockService) CreateUser(input model.UserUpsertInput) (string, error) {
 	args := ms.mock.Called(input)
 
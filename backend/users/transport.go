@@ -72,11 +72,18 @@ func RegisterRoutes(router *httprouter.Router, s Service) {
 		encodeDeleteUserResponse,
 	)
 
+	getReputationHandler := kithttp.NewServer(
+		MakeEndpointGetReputation(s),
+		decodeGetReputationRequest,
+		kithttp.EncodeJSONResponse,
+	)
+
 	router.Handler(http.MethodGet, "/users/:id", getUserHandler)
 	router.Handler(http.MethodGet, "/users", getUsersHandler)
 	router.Handler(http.MethodPost, "/users", createUserHandler)
 	router.Handler(http.MethodPut, "/users", updateUserHandler)
 	router.Handler(http.MethodDelete, "/users/:id", deleteUserHandler)
+	router.Handler(http.MethodGet, "/users/:id/reputation", getReputationHandler)
 }
 
 type GetUserRequest struct {
@@ -89,7 +96,7 @@ type GetUserResponse struct {
 
 func decodeGetUserRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
 	params := httprouter.ParamsFromContext(ctx)
-	uuid := params.ByName("uuid")
+	uuid := params.ByName("id")
 
 	if uuid == "" {
 		return nil, errors.New("invalid param")
@@ -101,12 +108,14 @@ func decodeGetUserRequest(ctx context.Context, r *http.Request) (request interfa
 }
 
 func encodeGetUserResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
-	_, ok := response.(GetUserResponse)
+	res, ok := response.(GetUserResponse)
 	if !ok {
 		return fmt.Errorf("encodeGetArticleResponse failed cast response")
 	}
 
-	return nil
+	writer.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(writer).Encode(res.UserResponse)
 }
 
 type ListUserRequest struct {
@@ -125,7 +134,7 @@ func decodeListUserRequest(ctx context.Context, r *http.Request) (request interf
 		return nil, err
 	}
 
-	return req, nil
+	return model.UserFetchInput{Name: req.Name, Region: req.Region}, nil
 }
 
 func encodeListUsersResponse(ctx context.Context, writer http.ResponseWriter, response interface{}) error {
@@ -134,7 +143,9 @@ func encodeListUsersResponse(ctx context.Context, writer http.ResponseWriter, re
 		return fmt.Errorf("encodeListUsersResponse failed cast response")
 	}
 
-	return json.NewEncoder(writer).Encode(&res)
+	writer.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(writer).Encode(res.users)
 }
 
 type CreateUserRequest struct {
@@ -169,6 +180,14 @@ func encodeCreateUserResponse(ctx context.Context, writer http.ResponseWriter, r
 	return nil
 }
 
+func decodeGetReputationRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+
+	return GetReputationRequest{
+		uuid: params.ByName("id"),
+	}, nil
+}
+
 type DeleteUserRequest struct {
 	uuid string
 }
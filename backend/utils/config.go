@@ -23,16 +23,26 @@ const configPath = "%s/config/%s.json"
 
 func LoadConfig(service, env string) (Config, error) {
 	var config Config
-	dir, err := filepath.Abs(service)
-	file, err := os.Open(fmt.Sprintf(configPath, dir, env))
 
-	if err != nil {
+	if err := LoadConfigInto(service, env, &config); err != nil {
 		return Config{}, err
 	}
 
-	if err = json.NewDecoder(file).Decode(&config); err != nil {
-		return Config{}, err
+	return config, nil
+}
+
+// LoadConfigInto - decodes the service config file into a service specific config struct
+func LoadConfigInto(service, env string, config interface{}) error {
+	dir, err := filepath.Abs(service)
+	if err != nil {
+		return err
 	}
 
-	return config, nil
+	file, err := os.Open(fmt.Sprintf(configPath, dir, env))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewDecoder(file).Decode(config)
 }